@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -52,6 +53,8 @@ type client struct {
 	buildInfo         component.BuildInfo
 	heartbeater       *heartbeater
 	bufferPool        bufferPool
+	indexBlocklist    *indexBlocklist
+	hostTemplate      *hostTemplate
 }
 
 var jsonStreamPool = sync.Pool{
@@ -61,12 +64,16 @@ var jsonStreamPool = sync.Pool{
 }
 
 func newClient(set exporter.CreateSettings, cfg *Config, maxContentLength uint) *client {
+	// The configuration is validated, so the host template is valid.
+	hostTemplate, _ := parseHostTemplate(cfg.HostTemplate)
 	return &client{
 		config:            cfg,
 		logger:            set.Logger,
 		telemetrySettings: set.TelemetrySettings,
 		buildInfo:         set.BuildInfo,
 		bufferPool:        newBufferPool(maxContentLength, !cfg.DisableCompression),
+		indexBlocklist:    newIndexBlocklist(cfg),
+		hostTemplate:      hostTemplate,
 	}
 }
 
@@ -173,22 +180,66 @@ func (c *client) pushLogDataInBatches(ctx context.Context, ld plog.Logs, headers
 
 	for !is.done {
 		buf.Reset()
-		latestIterState, batchPermanentErrors := c.fillLogsBuffer(ld, buf, is)
-		permanentErrors = append(permanentErrors, batchPermanentErrors...)
+		latestIterState, indexes, batchPermanentErrors := c.fillLogsBuffer(ld, buf, is)
 		if !buf.Empty() {
 			if err := c.postEvents(ctx, buf, headers); err != nil {
+				if c.blocklistIndex(err, indexes) {
+					// Send the batch again, now routing the events of the blocklisted index.
+					continue
+				}
 				return consumererror.NewLogs(err, subLogs(ld, is))
 			}
 		}
+		permanentErrors = append(permanentErrors, batchPermanentErrors...)
 		is = latestIterState
 	}
 
 	return multierr.Combine(permanentErrors...)
 }
 
+// blocklistIndex blocklists the index of the event rejected by err, if any, and returns whether it did.
+// indexes are the indexes of the events of the rejected batch.
+func (c *client) blocklistIndex(err error, indexes []string) bool {
+	var indexErr *incorrectIndexError
+	if c.indexBlocklist == nil || !errors.As(err, &indexErr) || indexErr.eventNumber < 0 || indexErr.eventNumber >= len(indexes) {
+		return false
+	}
+	index := indexes[indexErr.eventNumber]
+	c.logger.Warn("Splunk HEC rejected index, blocklisting it",
+		zap.String("index", index), zap.Duration("duration", c.config.IndexBlocklist.Duration))
+	c.indexBlocklist.add(index)
+	return true
+}
+
+// setTemplatedHost sets the host of event to the one derived from the attributes of res by the
+// host template, if configured and all its placeholders are set.
+func (c *client) setTemplatedHost(res pcommon.Resource, event *splunk.Event) {
+	if c.hostTemplate == nil {
+		return
+	}
+	if host, ok := c.hostTemplate.render(res); ok {
+		event.Host = host
+	}
+}
+
+// routeIndex returns the index event is to be sent to, and false if event is to be dropped
+// because its index is blocklisted and no fallback index is available.
+func (c *client) routeIndex(event *splunk.Event) (string, bool) {
+	if c.indexBlocklist == nil || !c.indexBlocklist.blocked(event.Index) {
+		return event.Index, true
+	}
+	fallback := c.config.IndexBlocklist.FallbackIndex
+	if fallback == "" || c.indexBlocklist.blocked(fallback) {
+		return "", false
+	}
+	return fallback, true
+}
+
 // fillLogsBuffer fills the buffer with Splunk events until the buffer is full or all logs are processed.
-func (c *client) fillLogsBuffer(logs plog.Logs, buf buffer, is iterState) (iterState, []error) {
+// When the index blocklist is enabled, the indexes of the events written to the buffer are returned as well.
+func (c *client) fillLogsBuffer(logs plog.Logs, buf buffer, is iterState) (iterState, []string, []error) {
 	var b []byte
+	var indexes []string
 	var permanentErrors []error
 	jsonStream := jsonStreamPool.Get().(*jsoniter.Stream)
 	defer jsonStreamPool.Put(jsonStream)
@@ -202,35 +253,72 @@ func (c *client) fillLogsBuffer(logs plog.Logs, buf buffer, is iterState) (iterS
 				is.record = 0 // Reset record index for next library.
 				logRecord := sl.LogRecords().At(k)
 
+				var index string
 				if c.config.ExportRaw {
 					b = []byte(logRecord.Body().AsString() + "\n")
 				} else {
 					// Parsing log record to Splunk event.
 					event := mapLogRecordToSplunkEvent(rl.Resource(), logRecord, c.config)
-
-					// JSON encoding event and writing to buffer.
-					var err error
-					b, err = marshalEvent(event, c.config.MaxEventSize, jsonStream)
-					if err != nil {
+					host := event.Host
+					c.setTemplatedHost(rl.Resource(), event)
+					var ok bool
+					if index, ok = c.routeIndex(event); !ok {
 						permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
-							"dropped log event: %v, error: %w", event, err)))
+							"dropped log event: %v, error: index %q is blocklisted", event, event.Index)))
 						continue
 					}
+					// Events routed to another index or host are re-encoded, so that they are sent to the
+					// index recorded for the blocklist.
+					rerouted := index != event.Index || event.Host != host
+					event.Index = index
+
+					var err error
+					if raw, ok := rawEvent(logRecord, c.config); ok && !rerouted {
+						// The original event is sent as received.
+						if uint(len(raw)) > c.config.MaxEventSize {
+							permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+								"dropped log event: %v, error: event size %d exceeds limit %d", event, len(raw), c.config.MaxEventSize)))
+							continue
+						}
+						b = raw
+					} else {
+						if c.config.RawEventPassthrough {
+							delete(event.Fields, splunk.RawEventLabel)
+						}
+						if c.config.OtelFidelity {
+							if err = addOtelFidelityField(event, rl.Resource(), sl.Scope(), logRecord); err != nil {
+								permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+									"dropped log event: %v, error: %w", event, err)))
+								continue
+							}
+						}
+
+						// JSON encoding event and writing to buffer.
+						b, err = marshalEvent(event, c.config.MaxEventSize, jsonStream)
+						if err != nil {
+							permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+								"dropped log event: %v, error: %w", event, err)))
+							continue
+						}
+					}
 				}
 
 				// Continue adding events to buffer up to capacity.
 				_, err := buf.Write(b)
 				if err == nil {
+					if c.indexBlocklist != nil && !c.config.ExportRaw {
+						indexes = append(indexes, index)
+					}
 					continue
 				}
 				if errors.Is(err, errOverCapacity) {
 					if !buf.Empty() {
-						return iterState{i, j, k, false}, permanentErrors
+						return iterState{i, j, k, false}, indexes, permanentErrors
 					}
 					permanentErrors = append(permanentErrors, consumererror.NewPermanent(
 						fmt.Errorf("dropped log event: error: event size %d bytes larger than configured max"+
 							" content length %d bytes", len(b), c.config.MaxContentLengthLogs)))
-					return iterState{i, j, k + 1, false}, permanentErrors
+					return iterState{i, j, k + 1, false}, indexes, permanentErrors
 				}
 				permanentErrors = append(permanentErrors,
 					consumererror.NewPermanent(fmt.Errorf("error writing the event: %w", err)))
@@ -238,10 +326,13 @@ func (c *client) fillLogsBuffer(logs plog.Logs, buf buffer, is iterState) (iterS
 		}
 	}
 
-	return iterState{done: true}, permanentErrors
+	return iterState{done: true}, indexes, permanentErrors
 }
 
-func (c *client) fillMetricsBuffer(metrics pmetric.Metrics, buf buffer, is iterState) (iterState, []error) {
+// fillMetricsBuffer fills the buffer with Splunk events until the buffer is full or all metrics are processed.
+// When the index blocklist is enabled, the indexes of the events written to the buffer are returned as well.
+func (c *client) fillMetricsBuffer(metrics pmetric.Metrics, buf buffer, is iterState) (iterState, []string, []error) {
+	var indexes []string
 	var permanentErrors []error
 	jsonStream := jsonStreamPool.Get().(*jsoniter.Stream)
 	defer jsonStreamPool.Put(jsonStream)
@@ -258,7 +349,16 @@ func (c *client) fillMetricsBuffer(metrics pmetric.Metrics, buf buffer, is iterS
 				// Parsing metric record to Splunk event.
 				events := mapMetricToSplunkEvent(rm.Resource(), metric, c.config, c.logger)
 				tempBuf := bytes.NewBuffer(make([]byte, 0, c.config.MaxContentLengthMetrics))
+				var metricIndexes []string
 				for _, event := range events {
+					c.setTemplatedHost(rm.Resource(), event)
+					index, ok := c.routeIndex(event)
+					if !ok {
+						permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+							"dropped metric event: %v, error: index %q is blocklisted", event, event.Index)))
+						continue
+					}
+					event.Index = index
 					// JSON encoding event and writing to buffer.
 					b, err := marshalEvent(event, c.config.MaxEventSize, jsonStream)
 					if err != nil {
@@ -266,22 +366,26 @@ func (c *client) fillMetricsBuffer(metrics pmetric.Metrics, buf buffer, is iterS
 						continue
 					}
 					tempBuf.Write(b)
+					if c.indexBlocklist != nil {
+						metricIndexes = append(metricIndexes, index)
+					}
 				}
 
 				// Continue adding events to buffer up to capacity.
 				b := tempBuf.Bytes()
 				_, err := buf.Write(b)
 				if err == nil {
+					indexes = append(indexes, metricIndexes...)
 					continue
 				}
 				if errors.Is(err, errOverCapacity) {
 					if !buf.Empty() {
-						return iterState{i, j, k, false}, permanentErrors
+						return iterState{i, j, k, false}, indexes, permanentErrors
 					}
 					permanentErrors = append(permanentErrors, consumererror.NewPermanent(
 						fmt.Errorf("dropped metric event: error: event size %d bytes larger than configured max"+
 							" content length %d bytes", len(b), c.config.MaxContentLengthMetrics)))
-					return iterState{i, j, k + 1, false}, permanentErrors
+					return iterState{i, j, k + 1, false}, indexes, permanentErrors
 				}
 				permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
 					"error writing the event: %w", err)))
@@ -289,16 +393,32 @@ func (c *client) fillMetricsBuffer(metrics pmetric.Metrics, buf buffer, is iterS
 		}
 	}
 
-	return iterState{done: true}, permanentErrors
+	return iterState{done: true}, indexes, permanentErrors
 }
 
-func (c *client) fillMetricsBufferMultiMetrics(events []*splunk.Event, buf buffer, is iterState) (iterState, []error) {
+// fillMetricsBufferMultiMetrics fills the buffer with the merged Splunk events until the buffer is full or all
+// events are processed. When the index blocklist is enabled, the indexes of the events written to the buffer are
+// returned as well.
+func (c *client) fillMetricsBufferMultiMetrics(events []*splunk.Event, buf buffer, is iterState) (iterState, []string, []error) {
+	var indexes []string
 	var permanentErrors []error
 	jsonStream := jsonStreamPool.Get().(*jsoniter.Stream)
 	defer jsonStreamPool.Put(jsonStream)
 
 	for i := is.record; i < len(events); i++ {
 		event := events[i]
+		index, ok := c.routeIndex(event)
+		if !ok {
+			permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+				"dropped metric event: %v, error: index %q is blocklisted", event, event.Index)))
+			continue
+		}
+		if index != event.Index {
+			// The merged events are kept as is, as the batch may be sent again.
+			routed := *event
+			routed.Index = index
+			event = &routed
+		}
 		// JSON encoding event and writing to buffer.
 		b, jsonErr := marshalEvent(event, c.config.MaxEventSize, jsonStream)
 		if jsonErr != nil {
@@ -311,7 +431,7 @@ func (c *client) fillMetricsBufferMultiMetrics(events []*splunk.Event, buf buffe
 				return iterState{
 					record: i,
 					done:   false,
-				}, permanentErrors
+				}, indexes, permanentErrors
 			}
 			permanentErrors = append(permanentErrors, consumererror.NewPermanent(
 				fmt.Errorf("dropped metric event: error: event size %d bytes larger than configured max"+
@@ -319,17 +439,22 @@ func (c *client) fillMetricsBufferMultiMetrics(events []*splunk.Event, buf buffe
 			return iterState{
 				record: i + 1,
 				done:   i+1 != len(events),
-			}, permanentErrors
+			}, indexes, permanentErrors
 		} else if err != nil {
 			permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
 				"error writing the event: %w", err)))
+		} else if c.indexBlocklist != nil {
+			indexes = append(indexes, index)
 		}
 	}
 
-	return iterState{done: true}, permanentErrors
+	return iterState{done: true}, indexes, permanentErrors
 }
 
-func (c *client) fillTracesBuffer(traces ptrace.Traces, buf buffer, is iterState) (iterState, []error) {
+// fillTracesBuffer fills the buffer with Splunk events until the buffer is full or all traces are processed.
+// When the index blocklist is enabled, the indexes of the events written to the buffer are returned as well.
+func (c *client) fillTracesBuffer(traces ptrace.Traces, buf buffer, is iterState) (iterState, []string, []error) {
+	var indexes []string
 	var permanentErrors []error
 	jsonStream := jsonStreamPool.Get().(*jsoniter.Stream)
 	defer jsonStreamPool.Put(jsonStream)
@@ -345,6 +470,14 @@ func (c *client) fillTracesBuffer(traces ptrace.Traces, buf buffer, is iterState
 
 				// Parsing span record to Splunk event.
 				event := mapSpanToSplunkEvent(rs.Resource(), span, c.config)
+				c.setTemplatedHost(rs.Resource(), event)
+				index, ok := c.routeIndex(event)
+				if !ok {
+					permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
+						"dropped span event: %v, error: index %q is blocklisted", event, event.Index)))
+					continue
+				}
+				event.Index = index
 
 				// JSON encoding event and writing to buffer.
 				b, err := marshalEvent(event, c.config.MaxEventSize, jsonStream)
@@ -356,16 +489,19 @@ func (c *client) fillTracesBuffer(traces ptrace.Traces, buf buffer, is iterState
 				// Continue adding events to buffer up to capacity.
 				_, err = buf.Write(b)
 				if err == nil {
+					if c.indexBlocklist != nil {
+						indexes = append(indexes, index)
+					}
 					continue
 				}
 				if errors.Is(err, errOverCapacity) {
 					if !buf.Empty() {
-						return iterState{i, j, k, false}, permanentErrors
+						return iterState{i, j, k, false}, indexes, permanentErrors
 					}
 					permanentErrors = append(permanentErrors, consumererror.NewPermanent(
 						fmt.Errorf("dropped span event: error: event size %d bytes larger than configured max"+
 							" content length %d bytes", len(b), c.config.MaxContentLengthTraces)))
-					return iterState{i, j, k + 1, false}, permanentErrors
+					return iterState{i, j, k + 1, false}, indexes, permanentErrors
 				}
 				permanentErrors = append(permanentErrors, consumererror.NewPermanent(fmt.Errorf(
 					"error writing the event: %w", err)))
@@ -373,7 +509,7 @@ func (c *client) fillTracesBuffer(traces ptrace.Traces, buf buffer, is iterState
 		}
 	}
 
-	return iterState{done: true}, permanentErrors
+	return iterState{done: true}, indexes, permanentErrors
 }
 
 // pushMultiMetricsDataInBatches sends batches of Splunk multi-metric events in JSON format.
@@ -394,7 +530,10 @@ func (c *client) pushMultiMetricsDataInBatches(ctx context.Context, md pmetric.M
 				metric := sm.Metrics().At(k)
 
 				// Parsing metric record to Splunk event.
-				events = append(events, mapMetricToSplunkEvent(rm.Resource(), metric, c.config, c.logger)...)
+				for _, event := range mapMetricToSplunkEvent(rm.Resource(), metric, c.config, c.logger) {
+					c.setTemplatedHost(rm.Resource(), event)
+					events = append(events, event)
+				}
 			}
 		}
 	}
@@ -407,14 +546,17 @@ func (c *client) pushMultiMetricsDataInBatches(ctx context.Context, md pmetric.M
 	for !is.done {
 		buf.Reset()
 
-		latestIterState, batchPermanentErrors := c.fillMetricsBufferMultiMetrics(merged, buf, is)
-		permanentErrors = append(permanentErrors, batchPermanentErrors...)
+		latestIterState, indexes, batchPermanentErrors := c.fillMetricsBufferMultiMetrics(merged, buf, is)
 		if !buf.Empty() {
 			if err := c.postEvents(ctx, buf, headers); err != nil {
+				if c.blocklistIndex(err, indexes) {
+					// Send the batch again, now routing the events of the blocklisted index.
+					continue
+				}
 				return consumererror.NewMetrics(err, md)
 			}
 		}
-
+		permanentErrors = append(permanentErrors, batchPermanentErrors...)
 		is = latestIterState
 	}
 
@@ -432,14 +574,17 @@ func (c *client) pushMetricsDataInBatches(ctx context.Context, md pmetric.Metric
 
 	for !is.done {
 		buf.Reset()
-		latestIterState, batchPermanentErrors := c.fillMetricsBuffer(md, buf, is)
-		permanentErrors = append(permanentErrors, batchPermanentErrors...)
+		latestIterState, indexes, batchPermanentErrors := c.fillMetricsBuffer(md, buf, is)
 		if !buf.Empty() {
 			if err := c.postEvents(ctx, buf, headers); err != nil {
+				if c.blocklistIndex(err, indexes) {
+					// Send the batch again, now routing the events of the blocklisted index.
+					continue
+				}
 				return consumererror.NewMetrics(err, subMetrics(md, is))
 			}
 		}
-
+		permanentErrors = append(permanentErrors, batchPermanentErrors...)
 		is = latestIterState
 	}
 
@@ -457,13 +602,17 @@ func (c *client) pushTracesDataInBatches(ctx context.Context, td ptrace.Traces,
 
 	for !is.done {
 		buf.Reset()
-		latestIterState, batchPermanentErrors := c.fillTracesBuffer(td, buf, is)
-		permanentErrors = append(permanentErrors, batchPermanentErrors...)
+		latestIterState, indexes, batchPermanentErrors := c.fillTracesBuffer(td, buf, is)
 		if !buf.Empty() {
 			if err := c.postEvents(ctx, buf, headers); err != nil {
+				if c.blocklistIndex(err, indexes) {
+					// Send the batch again, now routing the events of the blocklisted index.
+					continue
+				}
 				return consumererror.NewTraces(err, subTraces(td, is))
 			}
 		}
+		permanentErrors = append(permanentErrors, batchPermanentErrors...)
 		is = latestIterState
 	}
 
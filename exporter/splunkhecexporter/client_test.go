@@ -939,6 +939,30 @@ func TestReceiveRaw(t *testing.T) {
 	}
 }
 
+func TestReceiveRawEventPassthrough(t *testing.T) {
+	raw := `{"time":1.5,"event":{"b":1, "a":"x"},"fields":{"k":"v"}}`
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lr := records.AppendEmpty()
+	lr.Body().SetStr("x")
+	lr.Attributes().PutStr(splunk.RawEventLabel, raw)
+	records.AppendEmpty().Body().SetStr("mylog")
+
+	conf := createDefaultConfig().(*Config)
+	conf.RawEventPassthrough = true
+	conf.DisableCompression = true
+	got, err := runLogExport(conf, logs, 1, t)
+	require.NoError(t, err)
+	assert.Equal(t, raw+`{"host":"unknown","event":"mylog"}`, string(got[0].body))
+
+	// Events whose host is changed by the host template are re-encoded.
+	logs.ResourceLogs().At(0).Resource().Attributes().PutStr("k8s.pod.name", "pod")
+	conf.HostTemplate = "{k8s.pod.name}"
+	got, err = runLogExport(conf, logs, 1, t)
+	require.NoError(t, err)
+	assert.Equal(t, `{"host":"pod","event":"x","fields":{"k8s.pod.name":"pod"}}{"host":"pod","event":"mylog","fields":{"k8s.pod.name":"pod"}}`, string(got[0].body))
+}
+
 func TestReceiveLogEvent(t *testing.T) {
 	logs := createLogData(1, 1, 1)
 	cfg := NewFactory().CreateDefaultConfig().(*Config)
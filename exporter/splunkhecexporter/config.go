@@ -46,6 +46,17 @@ type HecHeartbeat struct {
 	Interval time.Duration `mapstructure:"interval"`
 }
 
+// HecIndexBlocklist defines how events targeting an index rejected by Splunk HEC are handled.
+type HecIndexBlocklist struct {
+	// Enabled blocklists the index of an event rejected with the "Incorrect index" code, instead of failing the whole batch.
+	Enabled bool `mapstructure:"enabled"`
+	// Duration for which a rejected index is blocklisted. Defaults to 5m.
+	Duration time.Duration `mapstructure:"duration"`
+	// FallbackIndex is the index events targeting a blocklisted index are sent to.
+	// If not set, these events are dropped.
+	FallbackIndex string `mapstructure:"fallback_index"`
+}
+
 // HecTelemetry defines the telemetry configuration for the exporter
 type HecTelemetry struct {
 	// Enabled is the bool to enable telemetry inside splunk hec exporter
@@ -113,6 +124,10 @@ type Config struct {
 	SplunkAppVersion string `mapstructure:"splunk_app_version"`
 	// HecToOtelAttrs creates a mapping from attributes to HEC specific metadata: source, sourcetype, index and host.
 	HecToOtelAttrs splunk.HecToOtelAttrs `mapstructure:"hec_metadata_to_otel_attrs"`
+	// HostTemplate derives the host of events from resource attributes, such as "{k8s.pod.name|host.name}".
+	// Placeholders are replaced with the first of their attributes which is set. Events keep the host mapped by
+	// HecToOtelAttrs when one of the placeholders has no attribute set.
+	HostTemplate string `mapstructure:"host_template"`
 	// HecFields creates a mapping from attributes to HEC fields.
 	HecFields OtelToHecFields `mapstructure:"otel_to_hec_fields"`
 
@@ -125,6 +140,15 @@ type Config struct {
 	// ExportRaw to send only the log's body, targeting a Splunk HEC raw endpoint.
 	ExportRaw bool `mapstructure:"export_raw"`
 
+	// OtelFidelity adds the OTLP JSON encoding of each log record, with its resource and scope, to a reserved HEC field
+	// so the Splunk HEC receiver can restore it without loss. Defaults to false.
+	OtelFidelity bool `mapstructure:"otel_fidelity"`
+
+	// RawEventPassthrough sends the original JSON of log records received by the Splunk HEC receiver with its
+	// raw_event setting, held by their "splunk.raw_event" attribute, instead of re-encoding them, unless host_template
+	// or index routing changes their host or index. Defaults to false.
+	RawEventPassthrough bool `mapstructure:"raw_event_passthrough"`
+
 	// UseMultiMetricFormat combines metric events to save space during ingestion.
 	UseMultiMetricFormat bool `mapstructure:"use_multi_metric_format"`
 
@@ -133,6 +157,9 @@ type Config struct {
 
 	// Telemetry is the configuration for splunk hec exporter telemetry
 	Telemetry HecTelemetry `mapstructure:"telemetry"`
+
+	// IndexBlocklist is the configuration to temporarily blocklist indexes rejected by Splunk HEC.
+	IndexBlocklist HecIndexBlocklist `mapstructure:"index_blocklist"`
 }
 
 func (cfg *Config) getURL() (out *url.URL, err error) {
@@ -180,6 +207,14 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf(`requires "max_event_size" <= %d`, maxMaxEventSize)
 	}
 
+	if _, err := parseHostTemplate(cfg.HostTemplate); err != nil {
+		return fmt.Errorf(`invalid "host_template": %w`, err)
+	}
+
+	if cfg.IndexBlocklist.Enabled && cfg.IndexBlocklist.Duration <= 0 {
+		return errors.New(`requires a positive "index_blocklist::duration"`)
+	}
+
 	if err := cfg.QueueSettings.Validate(); err != nil {
 		return fmt.Errorf("sending_queue settings has invalid configuration: %w", err)
 	}
@@ -55,6 +55,7 @@ func TestLoadConfig(t *testing.T) {
 				LogDataEnabled:          true,
 				ProfilingDataEnabled:    true,
 				ExportRaw:               true,
+				RawEventPassthrough:     true,
 				MaxEventSize:            5 * 1024 * 1024,
 				MaxContentLengthLogs:    2 * 1024 * 1024,
 				MaxContentLengthMetrics: 2 * 1024 * 1024,
@@ -93,6 +94,7 @@ func TestLoadConfig(t *testing.T) {
 					Index:      "myindex",
 					Host:       "myhost",
 				},
+				HostTemplate: "{k8s.pod.name|host.name}",
 				HecFields: OtelToHecFields{
 					SeverityText:   "myseverityfield",
 					SeverityNumber: "myseveritynumfield",
@@ -112,6 +114,11 @@ func TestLoadConfig(t *testing.T) {
 						"customKey": "customVal",
 					},
 				},
+				IndexBlocklist: HecIndexBlocklist{
+					Enabled:       true,
+					Duration:      time.Minute,
+					FallbackIndex: "fallback",
+				},
 			},
 		},
 	}
@@ -205,6 +212,29 @@ func TestConfig_Validate(t *testing.T) {
 			}(),
 			wantErr: "requires \"max_event_size\" <= 838860800",
 		},
+		{
+			name: "non positive index blocklist duration",
+			cfg: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.HTTPClientSettings.Endpoint = "http://foo_bar.com"
+				cfg.IndexBlocklist.Enabled = true
+				cfg.IndexBlocklist.Duration = 0
+				cfg.Token = "foo"
+				return cfg
+			}(),
+			wantErr: "requires a positive \"index_blocklist::duration\"",
+		},
+		{
+			name: "invalid host template",
+			cfg: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.HTTPClientSettings.Endpoint = "http://foo_bar.com"
+				cfg.HostTemplate = "{k8s.pod.name"
+				cfg.Token = "foo"
+				return cfg
+			}(),
+			wantErr: `invalid "host_template": unclosed placeholder in host template "{k8s.pod.name"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -24,6 +24,7 @@ const (
 	defaultHTTPTimeout     = 10 * time.Second
 	defaultIdleConnTimeout = 10 * time.Second
 	defaultSplunkAppName   = "OpenTelemetry Collector Contrib"
+	defaultBlocklistPeriod = 5 * time.Minute
 )
 
 // TODO: Find a place for this to be shared.
@@ -86,6 +87,9 @@ func createDefaultConfig() component.Config {
 			OverrideMetricsNames: map[string]string{},
 			ExtraAttributes:      map[string]string{},
 		},
+		IndexBlocklist: HecIndexBlocklist{
+			Duration: defaultBlocklistPeriod,
+		},
 	}
 }
 
@@ -52,8 +52,12 @@ func (hec *defaultHecWorker) send(ctx context.Context, buf buffer, headers map[s
 	}
 	defer resp.Body.Close()
 
+	eventNumber, incorrectIndex := incorrectIndexEventNumber(resp)
 	err = splunk.HandleHTTPCode(resp)
 	if err != nil {
+		if incorrectIndex {
+			return &incorrectIndexError{err: err, eventNumber: eventNumber}
+		}
 		return err
 	}
 
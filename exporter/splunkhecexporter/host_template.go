@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// hostTemplate derives the host of events from the attributes of their resource. Templates mix
+// literal text with placeholders such as {k8s.pod.name|host.name}, replaced with the value of the
+// first of their resource attributes which is set and not empty.
+type hostTemplate struct {
+	parts []hostTemplatePart
+}
+
+// hostTemplatePart is either literal text, or the placeholder of the attributes listed.
+type hostTemplatePart struct {
+	literal    string
+	attributes []string
+}
+
+// parseHostTemplate parses template, returning nil when it is empty.
+func parseHostTemplate(template string) (*hostTemplate, error) {
+	if template == "" {
+		return nil, nil
+	}
+	t := &hostTemplate{}
+	for rest := template; rest != ""; {
+		start := strings.IndexAny(rest, "{}")
+		if start < 0 {
+			t.parts = append(t.parts, hostTemplatePart{literal: rest})
+			break
+		}
+		if rest[start] == '}' {
+			return nil, fmt.Errorf("unexpected '}' in host template %q", template)
+		}
+		if start > 0 {
+			t.parts = append(t.parts, hostTemplatePart{literal: rest[:start]})
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder in host template %q", template)
+		}
+		placeholder := rest[start+1 : start+end]
+		part := hostTemplatePart{}
+		for _, attribute := range strings.Split(placeholder, "|") {
+			attribute = strings.TrimSpace(attribute)
+			if attribute == "" || strings.ContainsRune(attribute, '{') {
+				return nil, fmt.Errorf("invalid placeholder {%s} in host template %q", placeholder, template)
+			}
+			part.attributes = append(part.attributes, attribute)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[start+end+1:]
+	}
+	return t, nil
+}
+
+// render returns the host derived from the attributes of res, or false when none of the attributes
+// of one of the placeholders is set.
+func (t *hostTemplate) render(res pcommon.Resource) (string, bool) {
+	var sb strings.Builder
+	for _, part := range t.parts {
+		if part.attributes == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+		found := false
+		for _, attribute := range part.attributes {
+			if v, ok := res.Attributes().Get(attribute); ok && v.AsString() != "" {
+				sb.WriteString(v.AsString())
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func TestParseHostTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  string
+	}{
+		{template: ""},
+		{template: "static"},
+		{template: "{host.name}"},
+		{template: "{k8s.pod.name | host.name}.{k8s.namespace.name}"},
+		{template: "{k8s.pod.name", wantErr: `unclosed placeholder in host template "{k8s.pod.name"`},
+		{template: "host}", wantErr: `unexpected '}' in host template "host}"`},
+		{template: "{}", wantErr: `invalid placeholder {} in host template "{}"`},
+		{template: "{a||b}", wantErr: `invalid placeholder {a||b} in host template "{a||b}"`},
+		{template: "{a{b}", wantErr: `invalid placeholder {a{b} in host template "{a{b}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, err := parseHostTemplate(tt.template)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.template == "", template == nil)
+		})
+	}
+}
+
+func TestHostTemplateRender(t *testing.T) {
+	template, err := parseHostTemplate("{k8s.pod.name|host.name}.{k8s.namespace.name}")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		want       string
+		wantOK     bool
+	}{
+		{
+			name:       "first attribute",
+			attributes: map[string]interface{}{"k8s.pod.name": "pod", "host.name": "node", "k8s.namespace.name": "ns"},
+			want:       "pod.ns",
+			wantOK:     true,
+		},
+		{
+			name:       "fallback attribute",
+			attributes: map[string]interface{}{"k8s.pod.name": "", "host.name": "node", "k8s.namespace.name": "ns"},
+			want:       "node.ns",
+			wantOK:     true,
+		},
+		{
+			name:       "missing placeholder",
+			attributes: map[string]interface{}{"host.name": "node"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := pcommon.NewResource()
+			require.NoError(t, res.Attributes().FromRaw(tt.attributes))
+			host, ok := template.render(res)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, host)
+		})
+	}
+}
+
+func TestClientSetTemplatedHost(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HostTemplate = "{k8s.pod.name}"
+	c := newLogsClient(exportertest.NewNopCreateSettings(), cfg)
+
+	res := pcommon.NewResource()
+	event := &splunk.Event{Host: "node"}
+	c.setTemplatedHost(res, event)
+	assert.Equal(t, "node", event.Host)
+
+	res.Attributes().PutStr("k8s.pod.name", "pod")
+	c.setTemplatedHost(res, event)
+	assert.Equal(t, "pod", event.Host)
+}
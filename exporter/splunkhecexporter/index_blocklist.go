@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// hecCodeIncorrectIndex is the code of the Splunk HEC response rejecting an event targeting an index
+// that does not exist or that the token is not allowed to write to.
+const hecCodeIncorrectIndex = 7
+
+// incorrectIndexError is returned when Splunk HEC rejected a batch because of the index of one of its events.
+type incorrectIndexError struct {
+	err         error
+	eventNumber int
+}
+
+func (e *incorrectIndexError) Error() string {
+	return fmt.Sprintf("incorrect index of event %d: %v", e.eventNumber, e.err)
+}
+
+func (e *incorrectIndexError) Unwrap() error {
+	return e.err
+}
+
+// incorrectIndexEventNumber returns the number of the event rejected because of its index if the
+// body of resp reports an incorrect index. The body of resp remains readable.
+func incorrectIndexEventNumber(resp *http.Response) (int, bool) {
+	if resp.StatusCode != http.StatusBadRequest {
+		return 0, false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0, false
+	}
+	var hecResp struct {
+		Code               int  `json:"code"`
+		InvalidEventNumber *int `json:"invalid-event-number"`
+	}
+	if json.Unmarshal(body, &hecResp) != nil || hecResp.Code != hecCodeIncorrectIndex || hecResp.InvalidEventNumber == nil {
+		return 0, false
+	}
+	return *hecResp.InvalidEventNumber, true
+}
+
+// indexBlocklist keeps track of the indexes rejected by Splunk HEC for the configured duration.
+type indexBlocklist struct {
+	mu       sync.Mutex
+	duration time.Duration
+	until    map[string]time.Time
+	now      func() time.Time
+}
+
+func newIndexBlocklist(config *Config) *indexBlocklist {
+	if !config.IndexBlocklist.Enabled {
+		return nil
+	}
+	return &indexBlocklist{
+		duration: config.IndexBlocklist.Duration,
+		until:    map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// add blocklists index for the configured duration.
+func (b *indexBlocklist) add(index string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.until[index] = b.now().Add(b.duration)
+}
+
+// blocked returns whether index is currently blocklisted.
+func (b *indexBlocklist) blocked(index string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	until, ok := b.until[index]
+	if !ok {
+		return false
+	}
+	if !b.now().Before(until) {
+		delete(b.until, index)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// indexRejectingServer accepts events unless they target one of the rejected indexes, in which case
+// the whole batch is rejected with the Splunk HEC "Incorrect index" response.
+type indexRejectingServer struct {
+	mu       sync.Mutex
+	rejected map[string]bool
+	indexes  []string
+}
+
+func (s *indexRejectingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var indexes []string
+	decoder := json.NewDecoder(r.Body)
+	for i := 0; ; i++ {
+		var event splunk.Event
+		if err := decoder.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if s.rejected[event.Index] {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, `{"text":"Incorrect index","code":7,"invalid-event-number":%d}`, i)
+			return
+		}
+		indexes = append(indexes, event.Index)
+	}
+	s.indexes = append(s.indexes, indexes...)
+	_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+}
+
+func newIndexedLogs(indexes ...string) plog.Logs {
+	ld := plog.NewLogs()
+	for _, index := range indexes {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr(splunk.DefaultIndexLabel, index)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("event")
+	}
+	return ld
+}
+
+func TestIndexBlocklist(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		fallbackIndex string
+		wantIndexes   []string
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{
+			name:          "disabled",
+			wantIndexes:   nil,
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:          "fallback",
+			enabled:       true,
+			fallbackIndex: "fallback",
+			wantIndexes:   []string{"main", "fallback", "main"},
+		},
+		{
+			name:          "drop",
+			enabled:       true,
+			wantIndexes:   []string{"main", "main"},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:          "rejected_fallback",
+			enabled:       true,
+			fallbackIndex: "also_missing",
+			wantIndexes:   []string{"main", "main"},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &indexRejectingServer{rejected: map[string]bool{"missing": true, "also_missing": true}}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Endpoint = server.URL
+			cfg.Token = "1234-1234"
+			cfg.DisableCompression = true
+			cfg.IndexBlocklist = HecIndexBlocklist{Enabled: tt.enabled, Duration: time.Hour, FallbackIndex: tt.fallbackIndex}
+
+			c := newLogsClient(exportertest.NewNopCreateSettings(), cfg)
+			require.NoError(t, c.start(context.Background(), componenttest.NewNopHost()))
+			err := c.pushLogData(context.Background(), newIndexedLogs("main", "missing", "main"))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantPermanent, consumererror.IsPermanent(err))
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantIndexes, handler.indexes)
+			if tt.enabled {
+				assert.True(t, c.indexBlocklist.blocked("missing"))
+			}
+			require.NoError(t, c.stop(context.Background()))
+		})
+	}
+}
+
+func TestIndexBlocklistMetricsTraces(t *testing.T) {
+	indexes := []string{"main", "missing", "main"}
+	md := pmetric.NewMetrics()
+	td := ptrace.NewTraces()
+	for _, index := range indexes {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr(splunk.DefaultIndexLabel, index)
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName("gauge")
+		metric.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(splunk.DefaultIndexLabel, index)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	}
+	tests := []struct {
+		name        string
+		multiMetric bool
+		push        func(c *client) error
+		wantIndexes []string
+	}{
+		{
+			name:        "metrics",
+			push:        func(c *client) error { return c.pushMetricsData(context.Background(), md) },
+			wantIndexes: []string{"main", "fallback", "main"},
+		},
+		{
+			name:        "multi_metrics",
+			multiMetric: true,
+			push:        func(c *client) error { return c.pushMetricsData(context.Background(), md) },
+			// The events of the main index are merged.
+			wantIndexes: []string{"main", "fallback"},
+		},
+		{
+			name:        "traces",
+			push:        func(c *client) error { return c.pushTraceData(context.Background(), td) },
+			wantIndexes: []string{"main", "fallback", "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &indexRejectingServer{rejected: map[string]bool{"missing": true}}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Endpoint = server.URL
+			cfg.Token = "1234-1234"
+			cfg.DisableCompression = true
+			cfg.UseMultiMetricFormat = tt.multiMetric
+			cfg.IndexBlocklist = HecIndexBlocklist{Enabled: true, Duration: time.Hour, FallbackIndex: "fallback"}
+
+			c := newClient(exportertest.NewNopCreateSettings(), cfg, cfg.MaxContentLengthMetrics)
+			require.NoError(t, c.start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, c.stop(context.Background()))
+			}()
+			require.NoError(t, tt.push(c))
+			assert.Equal(t, tt.wantIndexes, handler.indexes)
+			assert.True(t, c.indexBlocklist.blocked("missing"))
+		})
+	}
+}
+
+func TestIndexBlocklistExpiry(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	assert.Nil(t, newIndexBlocklist(cfg))
+
+	cfg.IndexBlocklist.Enabled = true
+	blocklist := newIndexBlocklist(cfg)
+	now := time.Now()
+	blocklist.now = func() time.Time { return now }
+
+	blocklist.add("missing")
+	assert.True(t, blocklist.blocked("missing"))
+	assert.False(t, blocklist.blocked("main"))
+
+	now = now.Add(cfg.IndexBlocklist.Duration)
+	assert.False(t, blocklist.blocked("missing"))
+}
+
+func TestIndexBlocklistRawEventPassthrough(t *testing.T) {
+	handler := &indexRejectingServer{rejected: map[string]bool{"missing": true}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Token = "1234-1234"
+	cfg.DisableCompression = true
+	cfg.RawEventPassthrough = true
+	cfg.IndexBlocklist = HecIndexBlocklist{Enabled: true, Duration: time.Hour, FallbackIndex: "fallback"}
+
+	ld := newIndexedLogs("main", "missing", "main")
+	for i, index := range []string{"main", "missing", "main"} {
+		lr := ld.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords().At(0)
+		lr.Attributes().PutStr(splunk.RawEventLabel, fmt.Sprintf(`{"event":"event","index":%q}`, index))
+	}
+
+	c := newLogsClient(exportertest.NewNopCreateSettings(), cfg)
+	require.NoError(t, c.start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, c.stop(context.Background()))
+	}()
+	require.NoError(t, c.pushLogData(context.Background(), ld))
+	// The event of the blocklisted index is re-encoded with the fallback index, which is not blocklisted.
+	assert.Equal(t, []string{"main", "fallback", "main"}, handler.indexes)
+	assert.True(t, c.indexBlocklist.blocked("missing"))
+	assert.False(t, c.indexBlocklist.blocked("fallback"))
+}
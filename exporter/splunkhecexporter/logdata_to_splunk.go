@@ -94,6 +94,27 @@ func mapLogRecordToSplunkEvent(res pcommon.Resource, lr plog.LogRecord, config *
 	}
 }
 
+var otlpJSONMarshaler = &plog.JSONMarshaler{}
+
+// addOtelFidelityField stores the OTLP JSON encoding of lr, along with its
+// resource and scope, in a reserved field of event so that the Splunk HEC
+// receiver can restore the log record without loss.
+func addOtelFidelityField(event *splunk.Event, res pcommon.Resource, scope pcommon.InstrumentationScope, lr plog.LogRecord) error {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	res.CopyTo(rl.Resource())
+	rl.Resource().Attributes().Remove(splunk.HecTokenLabel)
+	sl := rl.ScopeLogs().AppendEmpty()
+	scope.CopyTo(sl.Scope())
+	lr.CopyTo(sl.LogRecords().AppendEmpty())
+	b, err := otlpJSONMarshaler.MarshalLogs(ld)
+	if err != nil {
+		return err
+	}
+	event.Fields[splunk.OtelFidelityField] = string(b)
+	return nil
+}
+
 // nanoTimestampToEpochMilliseconds transforms nanoseconds into <sec>.<ms>. For example, 1433188255.500 indicates 1433188255 seconds and 500 milliseconds after epoch.
 func nanoTimestampToEpochMilliseconds(ts pcommon.Timestamp) float64 {
 	return time.Duration(ts).Round(time.Millisecond).Seconds()
@@ -145,3 +166,16 @@ func flattenAndMergeMap(src, dst map[string]any, key string) {
 		}
 	}
 }
+
+// rawEvent returns the original JSON of a log record received by the
+// Splunk HEC receiver, when sending it as received is enabled.
+func rawEvent(lr plog.LogRecord, config *Config) ([]byte, bool) {
+	if !config.RawEventPassthrough {
+		return nil, false
+	}
+	raw, ok := lr.Attributes().Get(splunk.RawEventLabel)
+	if !ok || raw.Type() != pcommon.ValueTypeStr || raw.Str() == "" {
+		return nil, false
+	}
+	return []byte(raw.Str()), true
+}
@@ -458,6 +458,39 @@ func Test_emptyLogRecord(t *testing.T) {
 	assert.Empty(t, event.Fields)
 }
 
+func Test_addOtelFidelityField(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "checkout")
+	res.Attributes().PutStr(splunk.HecTokenLabel, "secret")
+	scope := pcommon.NewInstrumentationScope()
+	scope.SetName("io.opentelemetry.checkout")
+	scope.SetVersion("1.2.3")
+	lr := plog.NewLogRecord()
+	lr.SetTimestamp(pcommon.Timestamp(1001000123))
+	lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	lr.Attributes().PutEmptyMap("nested").PutInt("int", 42)
+	lr.Body().SetEmptyMap().PutEmptySlice("items").AppendEmpty().SetInt(1)
+
+	event := mapLogRecordToSplunkEvent(res, lr, &Config{})
+	assert.NoError(t, addOtelFidelityField(event, res, scope, lr))
+
+	encoded, ok := event.Fields[splunk.OtelFidelityField].(string)
+	assert.True(t, ok)
+	ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs([]byte(encoded))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"service.name": "checkout"}, rl.Resource().Attributes().AsRaw())
+	sl := rl.ScopeLogs().At(0)
+	assert.Equal(t, "io.opentelemetry.checkout", sl.Scope().Name())
+	assert.Equal(t, "1.2.3", sl.Scope().Version())
+	got := sl.LogRecords().At(0)
+	assert.Equal(t, lr.Timestamp(), got.Timestamp())
+	assert.Equal(t, lr.SeverityNumber(), got.SeverityNumber())
+	assert.Equal(t, lr.Attributes().AsRaw(), got.Attributes().AsRaw())
+	assert.Equal(t, lr.Body().AsRaw(), got.Body().AsRaw())
+}
+
 func Test_nanoTimestampToEpochMilliseconds(t *testing.T) {
 	splunkTs := nanoTimestampToEpochMilliseconds(1001000000)
 	assert.Equal(t, 1.001, splunkTs)
@@ -533,3 +566,20 @@ func Test_mergeValue(t *testing.T) {
 	}
 
 }
+
+func Test_rawEvent(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	lr := plog.NewLogRecord()
+	lr.Attributes().PutStr(splunk.RawEventLabel, `{"event":"x"}`)
+	_, ok := rawEvent(lr, config)
+	assert.False(t, ok)
+
+	config.RawEventPassthrough = true
+	raw, ok := rawEvent(lr, config)
+	assert.True(t, ok)
+	assert.Equal(t, `{"event":"x"}`, string(raw))
+
+	lr.Attributes().PutInt(splunk.RawEventLabel, 1)
+	_, ok = rawEvent(lr, config)
+	assert.False(t, ok)
+}
@@ -26,8 +26,17 @@ const (
 	HecTokenLabel              = "com.splunk.hec.access_token" // #nosec
 	// HecEventMetricType is the type of HEC event. Set to metric, as per https://docs.splunk.com/Documentation/Splunk/8.0.3/Metrics/GetMetricsInOther.
 	HecEventMetricType = "metric"
-	DefaultRawPath     = "/services/collector/raw"
-	DefaultHealthPath  = "/services/collector/health"
+	// HecMetricNameField and HecMetricValueField hold the name and value of the metric of an event using the
+	// single-metric format, as per https://docs.splunk.com/Documentation/Splunk/8.0.3/Metrics/GetMetricsInOther.
+	HecMetricNameField  = "metric_name"
+	HecMetricValueField = "_value"
+	// OtelFidelityField is the reserved HEC field holding the OTLP JSON encoding of a log record, along with its
+	// resource and scope, so it can be restored without loss after a hop through HEC.
+	OtelFidelityField = "otel.fidelity"
+	// RawEventLabel is the log record attribute holding the original JSON of a received HEC event.
+	RawEventLabel     = "splunk.raw_event"
+	DefaultRawPath    = "/services/collector/raw"
+	DefaultHealthPath = "/services/collector/health"
 )
 
 // AccessTokenPassthroughConfig configures passing through access tokens.
@@ -52,7 +61,8 @@ func (e Event) IsMetric() bool {
 	return e.Event == HecEventMetricType || (e.Event == nil && len(e.GetMetricValues()) > 0)
 }
 
-// GetMetricValues extracts metric key value pairs from a Splunk HEC metric.
+// GetMetricValues extracts metric key value pairs from a Splunk HEC metric,
+// using either the single-metric or the multiple-metric format.
 func (e Event) GetMetricValues() map[string]interface{} {
 	values := map[string]interface{}{}
 	for k, v := range e.Fields {
@@ -60,6 +70,11 @@ func (e Event) GetMetricValues() map[string]interface{} {
 			values[k[12:]] = v
 		}
 	}
+	if name, ok := e.Fields[HecMetricNameField].(string); ok && name != "" {
+		if v, ok := e.Fields[HecMetricValueField]; ok {
+			values[name] = v
+		}
+	}
 	return values
 }
 
@@ -22,6 +22,21 @@ func TestGetValues(t *testing.T) {
 	assert.Equal(t, map[string]interface{}{"foo": "bar", "foo2": "foobar"}, metric.GetMetricValues())
 }
 
+func TestGetValues_SingleMetric(t *testing.T) {
+	metric := Event{
+		Fields: map[string]interface{}{
+			"metric_name": "cpu",
+			"_value":      1.5,
+			"host":        "h",
+		},
+	}
+	assert.Equal(t, map[string]interface{}{"cpu": 1.5}, metric.GetMetricValues())
+	assert.True(t, metric.IsMetric())
+
+	delete(metric.Fields, "_value")
+	assert.Equal(t, map[string]interface{}{}, metric.GetMetricValues())
+}
+
 func TestIsMetric(t *testing.T) {
 	ev := Event{
 		Event: map[string]interface{}{},
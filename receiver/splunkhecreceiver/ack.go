@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	channelHeader     = "X-Splunk-Request-Channel"
+	channelQueryParam = "channel"
+)
+
+// ackRequest is the body of requests to the ack endpoint.
+type ackRequest struct {
+	Acks []uint64 `json:"acks"`
+}
+
+// ackResponse is the body of responses of the ack endpoint.
+type ackResponse struct {
+	Acks map[string]bool `json:"acks"`
+}
+
+// ackManager keeps track, per channel, of the requests whose data was accepted
+// by the next consumer until their status is queried. The state is persisted
+// in client when a storage extension is configured.
+type ackManager struct {
+	mu       sync.Mutex
+	channels map[string]*ackChannel
+	client   storage.Client
+	logger   *zap.Logger
+	// maxPending is the maximum number of ack IDs of a channel not queried yet, zero meaning no limit.
+	maxPending int
+	// maxIdle is the time after which idle channels are forgotten, zero meaning never.
+	maxIdle   time.Duration
+	now       func() time.Time
+	lastSweep time.Time
+	// dirty holds the channels whose state changed since it was last written to
+	// the storage, and channelsDirty whether the list of channels did.
+	dirty         map[string]struct{}
+	channelsDirty bool
+}
+
+type ackChannel struct {
+	nextID uint64
+	acked  map[uint64]struct{}
+	// used is the last time data was sent on the channel or its ack IDs were queried.
+	used time.Time
+}
+
+func newAckManager(config *Config) *ackManager {
+	if !config.Ack.Enabled {
+		return nil
+	}
+	return &ackManager{
+		channels:   map[string]*ackChannel{},
+		dirty:      map[string]struct{}{},
+		maxPending: config.Ack.MaxPendingAcksPerChannel,
+		maxIdle:    config.Ack.MaxIdleTime,
+		now:        time.Now,
+		lastSweep:  time.Now(),
+	}
+}
+
+// full reports whether channel reached the maximum number of ack IDs not
+// queried yet, in which case its requests are rejected until they are.
+func (m *ackManager) full(channel string) bool {
+	if m.maxPending <= 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.channels[channel]
+	return ok && len(c.acked) >= m.maxPending
+}
+
+// ack returns a new ack ID of channel, acknowledged right away as it is only
+// requested once the data was accepted.
+func (m *ackManager) ack(channel string) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+	c, ok := m.channels[channel]
+	if !ok {
+		c = &ackChannel{acked: map[uint64]struct{}{}}
+		m.channels[channel] = c
+	}
+	c.used = now
+	id := c.nextID
+	c.nextID++
+	c.acked[id] = struct{}{}
+	m.persist(channel, !ok)
+	return id
+}
+
+// query returns the status of ackIDs of channel. Acknowledged IDs are
+// forgotten once reported, as Splunk HEC does.
+func (m *ackManager) query(channel string, ackIDs []uint64) map[string]bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	m.sweep(now)
+	statuses := make(map[string]bool, len(ackIDs))
+	c := m.channels[channel]
+	if c != nil {
+		c.used = now
+	}
+	forgotten := false
+	for _, id := range ackIDs {
+		acked := false
+		if c != nil {
+			if _, acked = c.acked[id]; acked {
+				delete(c.acked, id)
+				forgotten = true
+			}
+		}
+		statuses[strconv.FormatUint(id, 10)] = acked
+	}
+	if forgotten {
+		m.persist(channel, false)
+	}
+	return statuses
+}
+
+// sweep forgets the channels idle for longer than the maximum idle time, along
+// with their ack IDs, like Splunk HEC does. Channels are swept at most once per
+// maximum idle time. It must be called with m.mu held.
+func (m *ackManager) sweep(now time.Time) {
+	if m.maxIdle <= 0 || now.Sub(m.lastSweep) < m.maxIdle {
+		return
+	}
+	m.lastSweep = now
+	var idle []string
+	for name, c := range m.channels {
+		if now.Sub(c.used) >= m.maxIdle {
+			delete(m.channels, name)
+			idle = append(idle, name)
+		}
+	}
+	if len(idle) > 0 {
+		m.forget(idle)
+	}
+}
+
+// channel returns the channel of req, taken from the X-Splunk-Request-Channel
+// header or the channel query parameter.
+func channel(req *http.Request) string {
+	if c := req.Header.Get(channelHeader); c != "" {
+		return c
+	}
+	return req.URL.Query().Get(channelQueryParam)
+}
+
+// successRespBody returns the body answering a request whose data was
+// accepted, the Splunk HEC success response, holding the ack ID of the
+// request when indexer acknowledgment is enabled, and the positions of the
+// invalid events skipped.
+func (r *splunkReceiver) successRespBody(req *http.Request) []byte {
+	skippedNumbers := skippedEventNumbers(req)
+	if r.acks == nil && len(skippedNumbers) == 0 {
+		return okRespBody
+	}
+	response := hecResponse{Text: responseSuccess, Code: hecCodeSuccess, SkippedEventNumbers: skippedNumbers}
+	if r.acks != nil {
+		ackID := r.acks.ack(channel(req))
+		response.AckID = &ackID
+	}
+	respBody, _ := jsoniter.Marshal(response)
+	return respBody
+}
+
+func (r *splunkReceiver) handleAckReq(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		r.writeCompressibleResponse(resp, req, http.StatusBadRequest, invalidMethodRespBody)
+		return
+	}
+	if r.tokens != nil {
+		if token, status, respBody, _ := r.tokens.authorize(req); token == nil {
+			r.writeCompressibleResponse(resp, req, status, respBody)
+			return
+		}
+	}
+	if r.acks == nil {
+		r.writeCompressibleResponse(resp, req, http.StatusBadRequest, ackDisabledRespBody)
+		return
+	}
+	c := channel(req)
+	if c == "" {
+		r.writeCompressibleResponse(resp, req, http.StatusBadRequest, dataChannelMissingRespBody)
+		return
+	}
+	var ackReq ackRequest
+	if err := jsoniter.NewDecoder(req.Body).Decode(&ackReq); err != nil {
+		r.writeCompressibleResponse(resp, req, http.StatusBadRequest, invalidFormatRespBody)
+		return
+	}
+	respBody, err := jsoniter.Marshal(ackResponse{Acks: r.acks.query(c, ackReq.Acks)})
+	if err != nil {
+		r.writeCompressibleResponse(resp, req, http.StatusInternalServerError, errInternalServerError)
+		return
+	}
+	r.writeCompressibleResponse(resp, req, http.StatusOK, respBody)
+}
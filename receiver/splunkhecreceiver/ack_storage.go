@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// ackChannelsKey is the storage key of the list of the persisted channels.
+	ackChannelsKey = "channels"
+	// ackChannelKeyPrefix prefixes the storage keys of the state of each channel.
+	ackChannelKeyPrefix = "channel/"
+	// ackFlushInterval is the interval the changed ack state is written to the storage at.
+	ackFlushInterval = time.Second
+)
+
+// ackChannelState is the persisted state of an ack channel.
+type ackChannelState struct {
+	NextID uint64   `json:"next_id"`
+	Acked  []uint64 `json:"acked"`
+}
+
+// start restores the ack state persisted in the storage extension configured
+// by config, if any, and persists the state from then on.
+func (m *ackManager) start(ctx context.Context, host component.Host, config AckConfig, settings component.TelemetrySettings, id component.ID) error {
+	if config.StorageID == nil {
+		return nil
+	}
+	client, err := getStorageClient(ctx, host, *config.StorageID, id)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err = m.load(ctx, client); err != nil {
+		_ = client.Close(ctx)
+		return fmt.Errorf("failed to restore ack state: %w", err)
+	}
+	m.client = client
+	m.logger = settings.Logger
+	return nil
+}
+
+// shutdown writes the changed ack state and closes the storage client it is
+// persisted with.
+func (m *ackManager) shutdown(ctx context.Context) error {
+	m.flush(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.client == nil {
+		return nil
+	}
+	err := m.client.Close(ctx)
+	m.client = nil
+	return err
+}
+
+func (m *ackManager) load(ctx context.Context, client storage.Client) error {
+	data, err := client.Get(ctx, ackChannelsKey)
+	if err != nil || data == nil {
+		return err
+	}
+	var channels []string
+	if err = jsoniter.Unmarshal(data, &channels); err != nil {
+		return err
+	}
+	for _, name := range channels {
+		if data, err = client.Get(ctx, ackChannelKeyPrefix+name); err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+		var state ackChannelState
+		if err = jsoniter.Unmarshal(data, &state); err != nil {
+			return err
+		}
+		c := &ackChannel{nextID: state.NextID, acked: make(map[uint64]struct{}, len(state.Acked)), used: m.now()}
+		for _, id := range state.Acked {
+			c.acked[id] = struct{}{}
+		}
+		m.channels[name] = c
+	}
+	return nil
+}
+
+// persist records that the state of channel changed, along with the list of
+// channels when it was just created, so that it is written by the next flush.
+// It must be called with m.mu held.
+func (m *ackManager) persist(name string, created bool) {
+	if m.client == nil {
+		return
+	}
+	m.dirty[name] = struct{}{}
+	m.channelsDirty = m.channelsDirty || created
+}
+
+// forget records that the forgotten channels names are to be removed from the
+// storage by the next flush. It must be called with m.mu held.
+func (m *ackManager) forget(names []string) {
+	if m.client == nil {
+		return
+	}
+	for _, name := range names {
+		m.dirty[name] = struct{}{}
+	}
+	m.channelsDirty = true
+}
+
+// flushPeriodically writes the changed ack state to the storage every
+// ackFlushInterval until ctx is cancelled.
+func (m *ackManager) flushPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(ackFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.flush(ctx)
+		}
+	}
+}
+
+// flush writes the state of the channels changed since the previous flush to
+// the storage. The state is copied while holding m.mu, and written once it is
+// released, so that requests do not wait for the storage. Failures are logged
+// as the data the ack IDs are about was already accepted.
+func (m *ackManager) flush(ctx context.Context) {
+	m.mu.Lock()
+	client := m.client
+	if client == nil || len(m.dirty) == 0 && !m.channelsDirty {
+		m.mu.Unlock()
+		return
+	}
+	states := make(map[string]*ackChannelState, len(m.dirty))
+	for name := range m.dirty {
+		c, ok := m.channels[name]
+		if !ok {
+			// The channel was forgotten.
+			states[name] = nil
+			continue
+		}
+		state := &ackChannelState{NextID: c.nextID, Acked: make([]uint64, 0, len(c.acked))}
+		for id := range c.acked {
+			state.Acked = append(state.Acked, id)
+		}
+		states[name] = state
+	}
+	var channels []string
+	if m.channelsDirty {
+		channels = make([]string, 0, len(m.channels))
+		for channel := range m.channels {
+			channels = append(channels, channel)
+		}
+	}
+	m.dirty = map[string]struct{}{}
+	m.channelsDirty = false
+	m.mu.Unlock()
+
+	ops := make([]storage.Operation, 0, len(states)+1)
+	for name, state := range states {
+		if state == nil {
+			ops = append(ops, storage.DeleteOperation(ackChannelKeyPrefix+name))
+			continue
+		}
+		data, err := jsoniter.Marshal(state)
+		if err != nil {
+			m.logger.Warn("Failed to encode ack state", zap.String("channel", name), zap.Error(err))
+			continue
+		}
+		ops = append(ops, storage.SetOperation(ackChannelKeyPrefix+name, data))
+	}
+	if channels != nil {
+		data, err := jsoniter.Marshal(channels)
+		if err != nil {
+			m.logger.Warn("Failed to encode ack channels", zap.Error(err))
+		} else {
+			ops = append(ops, storage.SetOperation(ackChannelsKey, data))
+		}
+	}
+	if err := client.Batch(ctx, ops...); err != nil {
+		m.logger.Warn("Failed to persist ack state", zap.Error(err))
+	}
+}
+
+// getStorageClient returns a client of the storage extension storageID for the component id.
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, id component.ID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension %q not found", storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension %q found", storageID)
+	}
+	return storageExt.GetClient(ctx, component.KindReceiver, id, "")
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newAckStorageReceiver(t *testing.T, storageID component.ID) *splunkReceiver {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0"
+	config.Ack.Enabled = true
+	config.Ack.StorageID = &storageID
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+	return rcv.(*splunkReceiver)
+}
+
+func Test_splunkhecReceiver_ackStorage(t *testing.T) {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("acks", t.TempDir())
+	storageID := storagetest.NewStorageID("acks")
+
+	r := newAckStorageReceiver(t, storageID)
+	require.NoError(t, r.Start(context.Background(), host))
+	for i := 0; i < 3; i++ {
+		status, _ := serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"first"}`)
+		require.Equal(t, http.StatusOK, status)
+	}
+	status, _ := serveAckTestRequest(r, "/services/collector/event", "other", `{"event":"second"}`)
+	require.Equal(t, http.StatusOK, status)
+	_, body := serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0]}`)
+	assert.JSONEq(t, `{"acks":{"0":true}}`, body)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// Ack IDs not yet queried are still reported after a restart, and new ones keep increasing.
+	r = newAckStorageReceiver(t, storageID)
+	require.NoError(t, r.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+	_, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0,1,2,3]}`)
+	assert.JSONEq(t, `{"acks":{"0":false,"1":true,"2":true,"3":false}}`, body)
+	_, body = serveAckTestRequest(r, "/services/collector/ack", "other", `{"acks":[0]}`)
+	assert.JSONEq(t, `{"acks":{"0":true}}`, body)
+	_, body = serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"third"}`)
+	assert.Equal(t, `{"text":"Success","code":0,"ackId":3}`, body)
+}
+
+func Test_splunkhecReceiver_ackStorageIdleChannels(t *testing.T) {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("acks", t.TempDir())
+	storageID := storagetest.NewStorageID("acks")
+
+	r := newAckStorageReceiver(t, storageID)
+	require.NoError(t, r.Start(context.Background(), host))
+	now := time.Now()
+	r.acks.now = func() time.Time { return now }
+	status, _ := serveAckTestRequest(r, "/services/collector/event", "idle", `{"event":"first"}`)
+	require.Equal(t, http.StatusOK, status)
+	now = now.Add(r.config.Ack.MaxIdleTime)
+	status, _ = serveAckTestRequest(r, "/services/collector/event", "active", `{"event":"second"}`)
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// The state of forgotten channels is removed from the storage as well.
+	r = newAckStorageReceiver(t, storageID)
+	require.NoError(t, r.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+	_, body := serveAckTestRequest(r, "/services/collector/ack", "idle", `{"acks":[0]}`)
+	assert.JSONEq(t, `{"acks":{"0":false}}`, body)
+	_, body = serveAckTestRequest(r, "/services/collector/ack", "active", `{"acks":[0]}`)
+	assert.JSONEq(t, `{"acks":{"0":true}}`, body)
+}
+
+func Test_ackManagerFlush(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindReceiver, component.NewID("splunk_hec"), "")
+	acks := newAckManager(&Config{Ack: AckConfig{Enabled: true}})
+	acks.client = client
+	acks.logger = zap.NewNop()
+
+	// Acks are written to the storage by the next flush, not while answering requests.
+	acks.ack("ch")
+	data, err := client.Get(context.Background(), ackChannelKeyPrefix+"ch")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	acks.flush(context.Background())
+	data, err = client.Get(context.Background(), ackChannelKeyPrefix+"ch")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"next_id":1,"acked":[0]}`, string(data))
+	data, err = client.Get(context.Background(), ackChannelsKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["ch"]`, string(data))
+
+	// Only the channels changed since the previous flush are written.
+	require.NoError(t, client.Delete(context.Background(), ackChannelKeyPrefix+"ch"))
+	acks.ack("other")
+	acks.flush(context.Background())
+	data, err = client.Get(context.Background(), ackChannelKeyPrefix+"ch")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+	data, err = client.Get(context.Background(), ackChannelKeyPrefix+"other")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"next_id":1,"acked":[0]}`, string(data))
+}
+
+func Test_splunkhecReceiver_ackStorageNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		host *storagetest.StorageHost
+		id   component.ID
+		err  string
+	}{
+		{
+			name: "missing",
+			host: storagetest.NewStorageHost(),
+			id:   storagetest.NewStorageID("missing"),
+			err:  `storage extension "test_storage/missing" not found`,
+		},
+		{
+			name: "not_storage",
+			host: storagetest.NewStorageHost().WithNonStorageExtension("other"),
+			id:   storagetest.NewNonStorageID("other"),
+			err:  `non-storage extension "non_storage/other" found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newAckStorageReceiver(t, tt.id)
+			assert.EqualError(t, r.Start(context.Background(), tt.host), tt.err)
+		})
+	}
+}
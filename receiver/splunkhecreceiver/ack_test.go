@@ -0,0 +1,165 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_ackManager(t *testing.T) {
+	acks := newAckManager(&Config{Ack: AckConfig{Enabled: true}})
+	assert.Equal(t, uint64(0), acks.ack("a"))
+	assert.Equal(t, uint64(1), acks.ack("a"))
+	assert.Equal(t, uint64(0), acks.ack("b"))
+
+	assert.Equal(t, map[string]bool{"0": true, "1": true, "2": false}, acks.query("a", []uint64{0, 1, 2}))
+	assert.Equal(t, map[string]bool{"0": false, "1": false}, acks.query("a", []uint64{0, 1}))
+	assert.Equal(t, map[string]bool{"0": true}, acks.query("b", []uint64{0}))
+	assert.Equal(t, map[string]bool{"0": false}, acks.query("c", []uint64{0}))
+
+	assert.Nil(t, newAckManager(&Config{}))
+}
+
+func Test_ackManagerLimits(t *testing.T) {
+	acks := newAckManager(&Config{Ack: AckConfig{Enabled: true, MaxPendingAcksPerChannel: 2, MaxIdleTime: time.Minute}})
+	now := time.Now()
+	acks.now = func() time.Time { return now }
+	acks.lastSweep = now
+
+	acks.ack("a")
+	assert.False(t, acks.full("a"))
+	acks.ack("a")
+	assert.True(t, acks.full("a"))
+	assert.False(t, acks.full("b"))
+	acks.query("a", []uint64{0})
+	assert.False(t, acks.full("a"))
+
+	// Channels idle for the maximum idle time are forgotten, along with their ack IDs.
+	now = now.Add(30 * time.Second)
+	acks.ack("b")
+	now = now.Add(30 * time.Second)
+	acks.ack("c")
+	assert.Equal(t, map[string]bool{"1": false}, acks.query("a", []uint64{1}))
+	assert.Equal(t, map[string]bool{"0": true}, acks.query("b", []uint64{0}))
+	assert.Len(t, acks.channels, 2)
+}
+
+func Test_splunkhecReceiver_ackChannelFull(t *testing.T) {
+	r := startAckReceiver(t, true, consumertest.NewNop())
+	r.acks.maxPending = 1
+
+	status, _ := serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"first"}`)
+	assert.Equal(t, http.StatusOK, status)
+	status, body := serveAckTestRequest(r, "/services/collector/raw", "ch", "second")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, `{"text":"Server is busy","code":9}`, body)
+	status, _ = serveAckTestRequest(r, "/services/collector/event", "other", `{"event":"third"}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	// Requests are accepted again once the ack IDs of the channel are queried.
+	_, _ = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0]}`)
+	status, _ = serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"fourth"}`)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func startAckReceiver(t *testing.T, enabled bool, nextConsumer consumer.Logs) *splunkReceiver {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Ack.Enabled = enabled
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, nextConsumer)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r
+}
+
+func serveAckTestRequest(r *splunkReceiver, path, channel, body string) (int, string) {
+	req := httptest.NewRequest("POST", "http://localhost:0"+path, strings.NewReader(body))
+	if channel != "" {
+		req.Header.Set(channelHeader, channel)
+	}
+	w := httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+func Test_splunkhecReceiver_ack(t *testing.T) {
+	r := startAckReceiver(t, true, consumertest.NewNop())
+
+	status, body := serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"first"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"text":"Success","code":0,"ackId":0}`, body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/raw", "ch", "second")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"text":"Success","code":0,"ackId":1}`, body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/event?channel=other", "", `{"event":"third"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"text":"Success","code":0,"ackId":0}`, body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0,1,2]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"acks":{"0":true,"1":true,"2":false}}`, body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"acks":{"0":false}}`, body)
+}
+
+func Test_splunkhecReceiver_ackFailures(t *testing.T) {
+	r := startAckReceiver(t, true, consumertest.NewErr(errors.New("failed")))
+
+	status, body := serveAckTestRequest(r, "/services/collector/event", "", `{"event":"first"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, string(dataChannelMissingRespBody), body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/raw", "", "first")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, string(dataChannelMissingRespBody), body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/event", "ch", `{"event":"first"}`)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, string(errInternalServerError), body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"acks":{"0":false}}`, body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, string(dataChannelMissingRespBody), body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, string(invalidFormatRespBody), body)
+}
+
+func Test_splunkhecReceiver_ackDisabled(t *testing.T) {
+	r := startAckReceiver(t, false, consumertest.NewNop())
+
+	status, body := serveAckTestRequest(r, "/services/collector/event", "", `{"event":"first"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, string(okRespBody), body)
+
+	status, body = serveAckTestRequest(r, "/services/collector/ack", "ch", `{"acks":[0]}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, string(ackDisabledRespBody), body)
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+const (
+	retryAfterHeader = "Retry-After"
+	// concurrentRetryAfter is the duration clients are told to retry requests rejected
+	// as too many requests are decoded concurrently after.
+	concurrentRetryAfter = time.Second
+)
+
+var (
+	errServerBusy        = errors.New("pipeline is saturated, shedding requests")
+	errTooManyConcurrent = errors.New("too many concurrent requests")
+)
+
+// shedding returns whether data requests are currently shed because the next
+// consumer recently refused data, and for how long they still are.
+func (r *splunkReceiver) shedding() (time.Duration, bool) {
+	remaining := time.Duration(r.shedUntil.Load() - time.Now().UnixNano())
+	return remaining, remaining > 0
+}
+
+// startShedding sheds data requests for the configured duration, if any.
+// Requests are admitted again once it elapses, the next one probing whether
+// the pipeline accepts data again.
+func (r *splunkReceiver) startShedding() {
+	if r.config.AdmissionControl.ShedDuration > 0 {
+		r.shedUntil.Store(time.Now().Add(r.config.AdmissionControl.ShedDuration).UnixNano())
+	}
+}
+
+// setRetryAfter tells the client to retry after d, rounded up to the second.
+func setRetryAfter(resp http.ResponseWriter, d time.Duration) {
+	resp.Header().Set(retryAfterHeader, strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+}
+
+// consumeFailure returns the status code and body of the response to a request
+// whose data the next consumer refused with err. Retryable errors are answered
+// with a 503 status, code 9 and a Retry-After header when configured to, so
+// that clients back off, and with a 500 status otherwise.
+func (r *splunkReceiver) consumeFailure(resp http.ResponseWriter, err error) (int, []byte) {
+	if r.config.AdmissionControl.RetryAfter > 0 && !consumererror.IsPermanent(err) {
+		setRetryAfter(resp, r.config.AdmissionControl.RetryAfter)
+		return http.StatusServiceUnavailable, serverBusyRespBody
+	}
+	return http.StatusInternalServerError, errInternalServerError
+}
+
+// newRequestSlots returns the semaphore bounding the number of data requests
+// decoded concurrently, or nil when they are not bounded.
+func newRequestSlots(config *Config) chan struct{} {
+	if config.AdmissionControl.MaxConcurrentRequests <= 0 {
+		return nil
+	}
+	return make(chan struct{}, config.AdmissionControl.MaxConcurrentRequests)
+}
+
+// acquireRequestSlot waits for a slot to decode a data request, for up to the
+// configured queue timeout. It returns the function releasing the slot, or
+// false when no slot was available in time. Requests are then shed, as the
+// pipeline does not keep up with them, before it refuses data.
+func (r *splunkReceiver) acquireRequestSlot(ctx context.Context) (func(), bool) {
+	if r.requestSlots == nil {
+		return func() {}, true
+	}
+	release := func() { <-r.requestSlots }
+	select {
+	case r.requestSlots <- struct{}{}:
+		return release, true
+	default:
+	}
+	if r.config.AdmissionControl.QueueTimeout <= 0 {
+		r.startShedding()
+		return nil, false
+	}
+	timer := time.NewTimer(r.config.AdmissionControl.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case r.requestSlots <- struct{}{}:
+		return release, true
+	case <-timer.C:
+		r.startShedding()
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_admissionControl(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.AdmissionControl.ShedDuration = time.Minute
+	var consumeErr error
+	consumed := 0
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+		consumed++
+		return consumeErr
+	})
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(raw bool) *http.Response {
+		w := httptest.NewRecorder()
+		if raw {
+			r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("foo")))
+		} else {
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+		}
+		return w.Result()
+	}
+
+	consumeErr = consumererror.NewPermanent(errors.New("bad data"))
+	assert.Equal(t, http.StatusInternalServerError, send(false).StatusCode)
+	assert.Equal(t, http.StatusInternalServerError, send(false).StatusCode)
+	assert.Equal(t, 2, consumed)
+
+	consumeErr = errors.New("sending_queue is full")
+	assert.Equal(t, http.StatusInternalServerError, send(false).StatusCode)
+	assert.Equal(t, 3, consumed)
+
+	for _, raw := range []bool{false, true} {
+		resp := send(raw)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+		assert.Equal(t, 3, consumed)
+	}
+
+	// Once the shedding duration elapses, requests are admitted again.
+	r.shedUntil.Store(time.Now().Add(-time.Second).UnixNano())
+	consumeErr = nil
+	assert.Equal(t, http.StatusOK, send(true).StatusCode)
+	assert.Equal(t, http.StatusOK, send(false).StatusCode)
+	assert.Equal(t, 5, consumed)
+}
+
+func Test_splunkhecReceiver_admissionControlDisabled(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error { return errors.New("sending_queue is full") })
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	}
+}
+
+func Test_splunkhecReceiver_retryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		consumeErr error
+		status     int
+		respBody   string
+		header     string
+	}{
+		{
+			name:       "retryable",
+			retryAfter: 10 * time.Second,
+			consumeErr: errors.New("sending_queue is full"),
+			status:     http.StatusServiceUnavailable,
+			respBody:   `{"text":"Server is busy","code":9}`,
+			header:     "10",
+		},
+		{
+			name:       "permanent",
+			retryAfter: 10 * time.Second,
+			consumeErr: consumererror.NewPermanent(errors.New("bad data")),
+			status:     http.StatusInternalServerError,
+			respBody:   `{"text":"Internal Server Error","code":8}`,
+		},
+		{
+			name:       "disabled",
+			consumeErr: errors.New("sending_queue is full"),
+			status:     http.StatusInternalServerError,
+			respBody:   `{"text":"Internal Server Error","code":8}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.AdmissionControl.RetryAfter = tt.retryAfter
+			next, err := consumer.NewLogs(func(context.Context, plog.Logs) error { return tt.consumeErr })
+			require.NoError(t, err)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			for _, raw := range []bool{false, true} {
+				w := httptest.NewRecorder()
+				if raw {
+					r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("foo")))
+				} else {
+					r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+				}
+				assert.Equal(t, tt.status, w.Code)
+				assert.JSONEq(t, tt.respBody, w.Body.String())
+				assert.Equal(t, tt.header, w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func Test_setRetryAfter(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		time.Millisecond:        "1",
+		time.Second:             "1",
+		1500 * time.Millisecond: "2",
+	} {
+		w := httptest.NewRecorder()
+		setRetryAfter(w, d)
+		assert.Equal(t, expected, w.Header().Get("Retry-After"))
+	}
+}
+
+func Test_splunkhecReceiver_sheddingOnSaturation(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.AdmissionControl.ShedDuration = time.Minute
+	config.AdmissionControl.MaxConcurrentRequests = 1
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+		return w
+	}
+
+	// A request getting no slot starts shedding before the pipeline refuses data.
+	r.requestSlots <- struct{}{}
+	w := send()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	<-r.requestSlots
+
+	w = send()
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, 0, sink.LogRecordCount())
+}
+
+func Test_splunkhecReceiver_maxConcurrentRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		queueTimeout time.Duration
+		wantStatus   int
+	}{
+		{
+			name:       "rejected",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:         "queued",
+			queueTimeout: time.Minute,
+			wantStatus:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.AdmissionControl.MaxConcurrentRequests = 1
+			config.AdmissionControl.QueueTimeout = tt.queueTimeout
+			consuming := make(chan struct{}, 2)
+			unblock := make(chan struct{})
+			next, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+				consuming <- struct{}{}
+				<-unblock
+				return nil
+			})
+			require.NoError(t, err)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			first := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				r.handleReq(first, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+			}()
+			<-consuming
+
+			second := httptest.NewRecorder()
+			secondDone := make(chan struct{})
+			go func() {
+				defer close(secondDone)
+				r.handleRawReq(second, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("bar")))
+			}()
+			if tt.queueTimeout > 0 {
+				// The second request waits for the first one to complete.
+				select {
+				case <-consuming:
+					t.Fatal("second request decoded concurrently")
+				case <-time.After(50 * time.Millisecond):
+				}
+			} else {
+				<-secondDone
+			}
+			close(unblock)
+			<-done
+			<-secondDone
+
+			assert.Equal(t, http.StatusOK, first.Code)
+			assert.Equal(t, tt.wantStatus, second.Code)
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, string(serverBusyRespBody), second.Body.String())
+				assert.Equal(t, "1", second.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
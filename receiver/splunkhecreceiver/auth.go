@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/auth"
+)
+
+// withAuth returns next authenticating requests with the authenticator
+// extension configured in the HTTP server settings, if any. The receiver
+// authenticates requests itself rather than leaving it to the HTTP server, so
+// failures are answered with HEC responses and health checks stay
+// unauthenticated, as with Splunk.
+func (r *splunkReceiver) withAuth(host component.Host, next http.Handler) (http.Handler, error) {
+	if r.config.Auth == nil {
+		return next, nil
+	}
+	server, err := r.config.Auth.GetServerAuthenticator(host.GetExtensions())
+	if err != nil {
+		return nil, err
+	}
+	return r.authInterceptor(server, next), nil
+}
+
+func (r *splunkReceiver) authInterceptor(server auth.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == r.config.HealthPath || req.URL.Path == r.config.HealthPath+"/1.0" {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		ctx, err := server.Authenticate(req.Context(), req.Header)
+		if err != nil {
+			opCtx := req.Context()
+			if r.logsConsumer == nil {
+				opCtx = r.obsrecv.StartMetricsOp(opCtx)
+			} else {
+				opCtx = r.obsrecv.StartLogsOp(opCtx)
+			}
+			r.failRequest(opCtx, resp, http.StatusUnauthorized, invalidAuthorizationRespBody, 0, err)
+			return
+		}
+		next.ServeHTTP(resp, req.WithContext(ctx))
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+type authHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *authHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func Test_splunkhecReceiver_auth(t *testing.T) {
+	authID := component.NewID("testauth")
+	host := &authHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			authID: auth.NewServer(auth.WithServerAuthenticate(func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+				for _, value := range headers["Authorization"] {
+					if value == "Splunk valid" {
+						return ctx, nil
+					}
+				}
+				return ctx, errors.New("unauthenticated")
+			})),
+		},
+	}
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = addr
+	config.Auth = &configauth.Authentication{AuthenticatorID: authID}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+		wantBody      []byte
+	}{
+		{
+			name:          "authenticated",
+			path:          "/services/collector/event",
+			authorization: "Splunk valid",
+			wantStatus:    http.StatusOK,
+			wantBody:      okRespBody,
+		},
+		{
+			name:          "unauthenticated",
+			path:          "/services/collector/event",
+			authorization: "Splunk invalid",
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      invalidAuthorizationRespBody,
+		},
+		{
+			name:       "health_unauthenticated",
+			path:       "/services/collector/health",
+			wantStatus: http.StatusOK,
+			wantBody:   healthyRespBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "http://"+addr+tt.path, strings.NewReader(`{"event":"foo"}`))
+			require.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, string(tt.wantBody), string(body))
+		})
+	}
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+func Test_splunkhecReceiver_authNotFound(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.Auth = &configauth.Authentication{AuthenticatorID: component.NewID("missing")}
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, rcv.Shutdown(context.Background()))
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/internal/metadata"
+)
+
+// consumeLogBatches passes ld to the next consumer in batches of at most
+// max_events_per_batch log records, stopping at the first batch failing to be
+// consumed. Log records are moved out of ld as batches are built, so that the
+// consumed batches can be released. It returns the number of log records
+// passed on before the failing batch.
+func (r *splunkReceiver) consumeLogBatches(ctx context.Context, ld plog.Logs) (int, error) {
+	maxRecords := r.config.MaxEventsPerBatch
+	if maxRecords <= 0 || ld.LogRecordCount() <= maxRecords {
+		return 0, r.logsConsumer.ConsumeLogs(ctx, ld)
+	}
+	return splitLogs(ld, maxRecords, func(batch plog.Logs) error {
+		return r.logsConsumer.ConsumeLogs(ctx, batch)
+	})
+}
+
+// splitLogs calls consume with batches of at most maxRecords log records of
+// ld, keeping their resources and scopes, until it returns an error. It
+// returns the number of log records of the batches consumed before.
+func splitLogs(ld plog.Logs, maxRecords int, consume func(plog.Logs) error) (int, error) {
+	batch := plog.NewLogs()
+	count, consumed := 0, 0
+	resources := ld.ResourceLogs()
+	for i := 0; i < resources.Len(); i++ {
+		rl := resources.At(i)
+		var destResource plog.ResourceLogs
+		hasResource := false
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			var destRecords plog.LogRecordSlice
+			hasScope := false
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				if count == maxRecords {
+					if err := consume(batch); err != nil {
+						return consumed, err
+					}
+					consumed += count
+					batch = plog.NewLogs()
+					count = 0
+					hasResource, hasScope = false, false
+				}
+				if !hasResource {
+					destResource = batch.ResourceLogs().AppendEmpty()
+					rl.Resource().CopyTo(destResource.Resource())
+					destResource.SetSchemaUrl(rl.SchemaUrl())
+					hasResource = true
+				}
+				if !hasScope {
+					destScope := destResource.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(destScope.Scope())
+					destScope.SetSchemaUrl(sl.SchemaUrl())
+					destRecords = destScope.LogRecords()
+					hasScope = true
+				}
+				records.At(k).MoveTo(destRecords.AppendEmpty())
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return consumed, nil
+	}
+	return consumed, consume(batch)
+}
+
+// logsDelivery tracks the outcome of passing the logs of a request to the next
+// consumer, which may happen in several steps as the request is decoded.
+type logsDelivery struct {
+	// accepted reports whether the next consumer accepted some logs of the request.
+	accepted bool
+	// failedEvent is the position of the first event of the first refused logs.
+	failedEvent  int
+	retryableErr error
+	permanentErr error
+}
+
+// record records the outcome of passing the logs of the events starting at
+// position firstEvent to the next consumer.
+func (d *logsDelivery) record(firstEvent int, err error) {
+	if err == nil {
+		d.accepted = true
+		return
+	}
+	if !d.failed() {
+		d.failedEvent = firstEvent
+	}
+	if consumererror.IsPermanent(err) {
+		d.permanentErr = multierr.Append(d.permanentErr, err)
+	} else {
+		d.retryableErr = multierr.Append(d.retryableErr, err)
+	}
+}
+
+// failed reports whether the next consumer refused some logs of the request.
+func (d *logsDelivery) failed() bool {
+	return d.retryableErr != nil || d.permanentErr != nil
+}
+
+// stopped reports whether the remaining logs of the request are no longer
+// passed to the next consumer, as it refused some with a retryable error, or
+// with any error without partial success.
+func (d *logsDelivery) stopped(partialSuccess bool) bool {
+	return d.retryableErr != nil || !partialSuccess && d.permanentErr != nil
+}
+
+// deliverLogs passes the logs converted by converter so far to the next
+// consumer, one resource at a time with partial success, and resets converter
+// so that the logs can be released.
+func (r *splunkReceiver) deliverLogs(ctx context.Context, converter *logsConverter, req *http.Request, delivery *logsDelivery) {
+	ld, groups := converter.take()
+	if ld.LogRecordCount() == 0 {
+		return
+	}
+	r.setTraceContext(req, ld)
+	r.markReceived()
+	if r.config.PartialSuccess {
+		r.deliverLogsPartially(ctx, ld, groups, delivery)
+		return
+	}
+	err := r.logsConsumer.ConsumeLogs(ctx, ld)
+	r.recordConsumeResult(err)
+	for _, group := range groups {
+		r.recordEvents(ctx, group.sourceTypes, err)
+	}
+	// Groups are created in the order of their first event.
+	delivery.record(groups[0].firstEvent, err)
+}
+
+// respondLogs answers a request whose logs were passed to the next consumer.
+// Requests some logs of which were accepted before others were refused are
+// answered with a 400 status, code 6 and the position of the first refused
+// event, as retrying them would duplicate the accepted logs.
+func (r *splunkReceiver) respondLogs(ctx context.Context, delivery *logsDelivery, numEvents int, resp http.ResponseWriter, req *http.Request) {
+	switch {
+	case !delivery.failed():
+		r.obsrecv.EndLogsOp(ctx, metadata.Type, numEvents, nil)
+		if err := r.writeSuccess(resp, req); err != nil {
+			r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(err))
+		}
+	case delivery.accepted || r.config.PartialSuccess && delivery.retryableErr == nil:
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, delivery.failedEvent), numEvents, multierr.Append(delivery.retryableErr, delivery.permanentErr))
+	case delivery.retryableErr != nil:
+		status, failRespBody := r.consumeFailure(resp, delivery.retryableErr)
+		r.failRequest(ctx, resp, status, failRespBody, numEvents, delivery.retryableErr)
+	default:
+		status, failRespBody := r.consumeFailure(resp, delivery.permanentErr)
+		r.failRequest(ctx, resp, status, failRespBody, numEvents, delivery.permanentErr)
+	}
+}
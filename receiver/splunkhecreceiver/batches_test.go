@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestSplitLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	for _, host := range []string{"a", "b"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("host.name", host)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("scope")
+		for i := 0; i < 3; i++ {
+			sl.LogRecords().AppendEmpty().Body().SetStr(fmt.Sprintf("%s%d", host, i))
+		}
+	}
+
+	var batches []plog.Logs
+	consumed, err := splitLogs(ld, 2, func(batch plog.Logs) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, consumed)
+
+	want := [][]string{{"a0", "a1"}, {"a2", "b0"}, {"b1", "b2"}}
+	require.Len(t, batches, len(want))
+	for i, batch := range batches {
+		var bodies []string
+		for j := 0; j < batch.ResourceLogs().Len(); j++ {
+			rl := batch.ResourceLogs().At(j)
+			host, _ := rl.Resource().Attributes().Get("host.name")
+			sl := rl.ScopeLogs().At(0)
+			assert.Equal(t, "scope", sl.Scope().Name())
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				body := sl.LogRecords().At(k).Body().Str()
+				assert.Equal(t, body[:1], host.Str())
+				bodies = append(bodies, body)
+			}
+		}
+		assert.Equal(t, want[i], bodies)
+	}
+
+	// Splitting stops at the first failing batch.
+	errFailed := errors.New("failed")
+	calls := 0
+	consumed, err = splitLogs(batches[1], 1, func(plog.Logs) error {
+		calls++
+		if calls == 2 {
+			return errFailed
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, consumed)
+}
+
+func Test_splunkhecReceiver_maxEventsPerBatch(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.MaxEventsPerBatch = 2
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	body := strings.Repeat(`{"event":"event"}`, 5)
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("1\n2\n3")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var counts []int
+	for _, ld := range sink.AllLogs() {
+		counts = append(counts, ld.LogRecordCount())
+	}
+	assert.Equal(t, []int{2, 2, 1, 2, 1}, counts)
+}
+
+func Test_splunkhecReceiver_maxEventsPerBatchStreaming(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.MaxEventsPerBatch = 2
+	consumed := make(chan plog.Logs, 1)
+	next, err := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+		consumed <- ld
+		return nil
+	})
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	// Batches are passed on while the rest of the body is still being sent.
+	body, writer := io.Pipe()
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", body))
+	}()
+	_, err = writer.Write([]byte(`{"event":"1"}{"event":"2"}{"event":"3"}`))
+	require.NoError(t, err)
+	select {
+	case ld := <-consumed:
+		assert.Equal(t, 2, ld.LogRecordCount())
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the first batch was not passed on before the end of the body")
+	}
+	_, err = writer.Write([]byte(`{"event":"4"}{"event":"5"}`))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	assert.Equal(t, 2, (<-consumed).LogRecordCount())
+	assert.Equal(t, 1, (<-consumed).LogRecordCount())
+	<-done
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func Test_splunkhecReceiver_maxEventsPerBatchPartialProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		failAt   int
+		err      error
+		status   int
+		respBody string
+		batches  int
+	}{
+		{
+			name:     "retryable_first_batch",
+			failAt:   1,
+			err:      errors.New("busy"),
+			status:   http.StatusInternalServerError,
+			respBody: `{"text":"Internal Server Error","code":8}`,
+			batches:  1,
+		},
+		{
+			name:     "retryable_later_batch",
+			failAt:   2,
+			err:      errors.New("busy"),
+			status:   http.StatusBadRequest,
+			respBody: `{"text":"Invalid data format","code":6,"invalid-event-number":2}`,
+			batches:  2,
+		},
+		{
+			name:     "permanent_later_batch",
+			failAt:   3,
+			err:      consumererror.NewPermanent(errors.New("invalid")),
+			status:   http.StatusBadRequest,
+			respBody: `{"text":"Invalid data format","code":6,"invalid-event-number":4}`,
+			batches:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.MaxEventsPerBatch = 2
+			calls := 0
+			next, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+				calls++
+				if calls == tt.failAt {
+					return tt.err
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			w := httptest.NewRecorder()
+			body := strings.Repeat(`{"event":"event"}`, 7)
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.respBody, w.Body.String())
+			// Events are no longer passed on once a batch is refused.
+			assert.Equal(t, tt.batches, calls)
+		})
+	}
+
+	// Raw requests are answered the same once some of their batches were accepted.
+	config := createDefaultConfig().(*Config)
+	config.MaxEventsPerBatch = 2
+	calls := 0
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error {
+		calls++
+		if calls == 2 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	rcv.(*splunkReceiver).handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("1\n2\n3\n4")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"text":"Invalid data format","code":6,"invalid-event-number":2}`, w.Body.String())
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/internal/metadata"
+)
+
+// blackholeSet holds the indexes whose events are acknowledged but dropped,
+// like events sent to the nullQueue of Splunk.
+type blackholeSet map[string]struct{}
+
+func newBlackholeSet(config *Config) blackholeSet {
+	if len(config.BlackholeIndexes) == 0 {
+		return nil
+	}
+	indexes := make(blackholeSet, len(config.BlackholeIndexes))
+	for _, index := range config.BlackholeIndexes {
+		indexes[index] = struct{}{}
+	}
+	return indexes
+}
+
+// drops reports whether the events sent to index are dropped.
+func (b blackholeSet) drops(index string) bool {
+	_, ok := b[index]
+	return ok
+}
+
+// acceptDropped answers a request all the events of which were dropped, as
+// sent to a blackhole index or suppressed as duplicates, or passed to the
+// traces consumer, as if they were accepted by the next consumer.
+func (r *splunkReceiver) acceptDropped(ctx context.Context, resp http.ResponseWriter, req *http.Request, numEvents int) {
+	r.markReceived()
+	if r.logsConsumer == nil {
+		r.obsrecv.EndMetricsOp(ctx, metadata.Type, numEvents, nil)
+	} else {
+		r.obsrecv.EndLogsOp(ctx, metadata.Type, numEvents, nil)
+	}
+	if err := r.writeSuccess(resp, req); err != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, numEvents, err)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/internal/metadata"
+)
+
+func Test_splunkhecReceiver_blackholeIndexes(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	settings := receivertest.NewNopCreateSettings()
+	settings.ID = component.NewIDWithName(metadata.Type, "blackhole")
+	config := createDefaultConfig().(*Config)
+	config.BlackholeIndexes = []string{"debug"}
+	config.RawEvent.Enabled = true
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(settings, *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(handler http.HandlerFunc, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPost, url, strings.NewReader(body)))
+		return w
+	}
+
+	w := send(r.handleReq, "http://localhost/services/collector",
+		`{"event":"a","index":"main","sourcetype":"app"}{"event":"b","index":"debug","sourcetype":"app"}{"event":"c","sourcetype":"app"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 2, sink.LogRecordCount())
+	records := sink.AllLogs()[0].ResourceLogs()
+	assert.Equal(t, "a", records.At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	raw, ok := records.At(1).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get(rawEventAttr)
+	require.True(t, ok)
+	assert.Equal(t, `{"event":"c","sourcetype":"app"}`, raw.Str())
+
+	// Requests all the events of which are dropped are acknowledged without reaching the next consumer.
+	w = send(r.handleReq, "http://localhost/services/collector?index=debug", `{"event":"d","sourcetype":"app"}{"event":"e","sourcetype":"db"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(okRespBody), w.Body.String())
+	w = send(r.handleRawReq, "http://localhost/services/collector/raw?index=debug&sourcetype=db", "f\ng")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, sink.AllLogs(), 1)
+
+	assert.Equal(t, map[string]float64{
+		"app/accepted": 2,
+		"app/dropped":  2,
+		"db/dropped":   3,
+	}, viewSums(t, statEvents.Name(), settings.ID, tagSourceType, tagOutcome))
+}
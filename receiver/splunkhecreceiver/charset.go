@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// charset transcodes request bodies to UTF-8 from their encoding.
+type charset struct {
+	encoding encoding.Encoding
+}
+
+// newCharset returns the charset of request bodies, or nil when they are read
+// as is. UTF-8 bodies are only transcoded when their invalid bytes are
+// sanitized, as the UTF-8 decoder replaces them with U+FFFD.
+func newCharset(name string, sanitizeInvalidUTF8 bool) (*charset, error) {
+	switch strings.ToLower(name) {
+	case "", "utf-8", "utf8":
+		if sanitizeInvalidUTF8 {
+			return &charset{encoding: unicode.UTF8}, nil
+		}
+		return nil, nil
+	}
+	e, err := ianaindex.IANA.Encoding(name)
+	if err != nil || e == nil {
+		return nil, fmt.Errorf("unsupported encoding %q", name)
+	}
+	return &charset{encoding: e}, nil
+}
+
+// transcode returns body transcoded to UTF-8, or body itself when c is nil.
+func (c *charset) transcode(body io.ReadCloser) io.ReadCloser {
+	if c == nil {
+		return body
+	}
+	return &transcodingReader{Reader: transform.NewReader(body, c.encoding.NewDecoder()), body: body}
+}
+
+// transcodingReader reads a transcoded request body.
+type transcodingReader struct {
+	io.Reader
+	body io.Closer
+}
+
+func (t *transcodingReader) Close() error {
+	return t.body.Close()
+}
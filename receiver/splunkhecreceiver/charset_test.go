@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_charset_transcode(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		sanitize bool
+		body     string
+		want     string
+	}{
+		{name: "utf-8", body: "caf\xc3\xa9 \xff", want: "caf\xc3\xa9 \xff"},
+		{name: "sanitized_utf-8", sanitize: true, body: "caf\xc3\xa9 \xff", want: "café �"},
+		{name: "latin-1", encoding: "ISO-8859-1", body: "caf\xe9", want: "café"},
+		{name: "shift-jis", encoding: "Shift_JIS", body: "\x93\xfa\x96\x7b", want: "日本"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newCharset(tt.encoding, tt.sanitize)
+			require.NoError(t, err)
+			got, err := io.ReadAll(c.transcode(io.NopCloser(strings.NewReader(tt.body))))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	_, err := newCharset("klingon", false)
+	assert.EqualError(t, err, `unsupported encoding "klingon"`)
+}
+
+func Test_splunkhecReceiver_encoding(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Encoding = "iso-8859-1"
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"caf`+"\xe9"+`","fields":{"r`+"\xf4"+`le":"ma`+"\xee"+`tre"}}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("na\xefve")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 2, sink.LogRecordCount())
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "café", record.Body().Str())
+	assert.Equal(t, map[string]interface{}{"rôle": "maître"}, record.Attributes().AsRaw())
+	assert.Equal(t, "naïve", sink.AllLogs()[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
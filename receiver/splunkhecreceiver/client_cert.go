@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// clientCertificateCustomizer returns the customizer recording the identity of
+// the client certificate req was sent with, or nil when it was sent without one
+// or no attribute is configured. The TLS server only accepts certificates
+// verified against the client CA, so their identity can be trusted.
+func clientCertificateCustomizer(config ClientCertificateConfig, req *http.Request) func(resource pcommon.Resource) {
+	if config.CommonNameAttribute == "" && config.SubjectAltNamesAttribute == "" {
+		return nil
+	}
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := req.TLS.PeerCertificates[0]
+	var altNames []string
+	if config.SubjectAltNamesAttribute != "" {
+		altNames = append(altNames, cert.DNSNames...)
+		altNames = append(altNames, cert.EmailAddresses...)
+		for _, ip := range cert.IPAddresses {
+			altNames = append(altNames, ip.String())
+		}
+		for _, uri := range cert.URIs {
+			altNames = append(altNames, uri.String())
+		}
+	}
+	return func(resource pcommon.Resource) {
+		if config.CommonNameAttribute != "" && cert.Subject.CommonName != "" {
+			resource.Attributes().PutStr(config.CommonNameAttribute, cert.Subject.CommonName)
+		}
+		if len(altNames) > 0 {
+			slice := resource.Attributes().PutEmptySlice(config.SubjectAltNamesAttribute)
+			slice.EnsureCapacity(len(altNames))
+			for _, name := range altNames {
+				slice.AppendEmpty().SetStr(name)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestClientCertificateCustomizer(t *testing.T) {
+	spiffe, err := url.Parse("spiffe://example.org/forwarder")
+	require.NoError(t, err)
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "forwarder-1"},
+		DNSNames:       []string{"forwarder-1.example.org"},
+		EmailAddresses: []string{"ops@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.1")},
+		URIs:           []*url.URL{spiffe},
+	}
+	tests := []struct {
+		name       string
+		config     ClientCertificateConfig
+		state      *tls.ConnectionState
+		commonName string
+		altNames   []any
+	}{
+		{
+			name: "all_attributes",
+			config: ClientCertificateConfig{
+				CommonNameAttribute:      "cn",
+				SubjectAltNamesAttribute: "san",
+			},
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			commonName: "forwarder-1",
+			altNames:   []any{"forwarder-1.example.org", "ops@example.org", "10.0.0.1", "spiffe://example.org/forwarder"},
+		},
+		{
+			name:       "common_name_only",
+			config:     ClientCertificateConfig{CommonNameAttribute: "cn"},
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			commonName: "forwarder-1",
+		},
+		{
+			name: "no_certificate",
+			config: ClientCertificateConfig{
+				CommonNameAttribute:      "cn",
+				SubjectAltNamesAttribute: "san",
+			},
+			state: &tls.ConnectionState{},
+		},
+		{
+			name: "no_tls",
+			config: ClientCertificateConfig{
+				CommonNameAttribute:      "cn",
+				SubjectAltNamesAttribute: "san",
+			},
+		},
+		{
+			name:  "disabled",
+			state: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "https://localhost/services/collector", nil)
+			req.TLS = tt.state
+			customize := clientCertificateCustomizer(tt.config, req)
+			if tt.commonName == "" && tt.altNames == nil {
+				assert.Nil(t, customize)
+				return
+			}
+			require.NotNil(t, customize)
+
+			resource := pcommon.NewResource()
+			customize(resource)
+			commonName, ok := resource.Attributes().Get("cn")
+			require.True(t, ok)
+			assert.Equal(t, tt.commonName, commonName.Str())
+			altNames, ok := resource.Attributes().Get("san")
+			if tt.altNames == nil {
+				assert.False(t, ok)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, tt.altNames, altNames.Slice().AsRaw())
+			}
+		})
+	}
+}
+
+func Test_splunkhecReceiver_clientCertificateAttributes(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.TLSSetting = &configtls.TLSServerSetting{ClientCAFile: "/ca.crt"}
+	config.ClientCertificate.CommonNameAttribute = "tls.client.subject.common_name"
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "https://localhost/services/collector/raw", strings.NewReader("foo"))
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "forwarder-1"}}}}
+	w := httptest.NewRecorder()
+	rcv.(*splunkReceiver).handleRawReq(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	attrs := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes()
+	commonName, ok := attrs.Get("tls.client.subject.common_name")
+	require.True(t, ok)
+	assert.Equal(t, "forwarder-1", commonName.Str())
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Command splunkconfmigrate prints the Splunk HEC receiver configuration
+// equivalent to the HEC stanzas of Splunk inputs.conf and props.conf files.
+// Settings which could not be converted are reported on the standard error.
+//
+//	splunkconfmigrate -inputs inputs.conf [-props props.conf]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkconf"
+)
+
+func main() {
+	inputsPath := flag.String("inputs", "", "path of the Splunk inputs.conf file")
+	propsPath := flag.String("props", "", "path of the Splunk props.conf file, optional")
+	flag.Parse()
+
+	if err := run(*inputsPath, *propsPath, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(inputsPath, propsPath string, stdout, stderr io.Writer) error {
+	if inputsPath == "" {
+		return errors.New("the -inputs flag is required")
+	}
+	inputs, err := os.Open(inputsPath)
+	if err != nil {
+		return err
+	}
+	defer inputs.Close()
+
+	var props io.Reader
+	if propsPath != "" {
+		file, err := os.Open(propsPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		props = file
+	}
+
+	result, err := splunkconf.Convert(inputs, props)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(stderr, "warning:", warning)
+	}
+	out, err := result.YAML()
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
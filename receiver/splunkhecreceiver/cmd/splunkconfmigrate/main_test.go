@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	testdata := filepath.Join("..", "..", "splunkconf", "testdata")
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run(filepath.Join(testdata, "inputs.conf"), filepath.Join(testdata, "props.conf"), &stdout, &stderr))
+
+	expected, err := os.ReadFile(filepath.Join(testdata, "expected.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), stdout.String())
+	assert.True(t, strings.HasPrefix(stderr.String(), "warning: [http] enableSSL"))
+}
+
+func TestRun_missingInputs(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.EqualError(t, run("", "", &stdout, &stderr), "the -inputs flag is required")
+}
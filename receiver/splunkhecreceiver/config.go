@@ -4,11 +4,64 @@
 package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
 )
 
+var (
+	errMissingReplayDirectory = errors.New("replay directory must be specified")
+	errNegativeReplayInterval = errors.New("replay interval must not be negative")
+	errNegativeHeartbeat      = errors.New("heartbeat interval must not be negative")
+	errInvalidReverseDNSTTL   = errors.New("hostname reverse_dns cache_ttl must be positive")
+	errInvalidRawEventMaxSize = errors.New("raw_event max_size must be positive")
+	errNegativeCompressionMin = errors.New("response_compression min_size must not be negative")
+	errNegativeDecompressed   = errors.New("max_decompressed_size must not be negative")
+	errNegativeContentLength  = errors.New("max_content_length must not be negative")
+	errMissingTenantHeader    = errors.New("tenant header must be specified")
+	errMissingTenantAttribute = errors.New("tenant resource_attribute must be specified")
+	errEmptyToken             = errors.New("tokens must not be empty")
+	errDuplicateToken         = errors.New("tokens must be unique")
+	errEmptyResourceDimension = errors.New("metrics resource_dimensions must not be empty")
+	errNegativeShedDuration   = errors.New("admission_control shed_duration must not be negative")
+	errMissingRouteAttribute  = errors.New("routing attribute must be specified")
+	errMissingSeverityField   = errors.New("severity field must be specified")
+	errNegativeLookupReload   = errors.New("host_lookup reload_interval must not be negative")
+	errEmptyFieldName         = errors.New("fields resource_attributes and drop must not be empty")
+	errNegativeConcurrency    = errors.New("admission_control max_concurrent_requests and queue_timeout must not be negative")
+	errNegativeRetryAfter     = errors.New("admission_control retry_after must not be negative")
+	errNegativeRateLimit      = errors.New("rate_limit events_per_second and burst must not be negative")
+	errRateLimitTenantsKey    = errors.New("rate_limit tenants require the tenant key")
+	errRateLimitTenantProxies = errors.New("rate_limit tenant key requires tenant trusted_proxies")
+	errAckStorageDisabled     = errors.New("ack storage requires ack to be enabled")
+	errNegativeAckLimits      = errors.New("ack max_pending_acks_per_channel and max_idle_time must not be negative")
+	errEmptyBlackholeIndex    = errors.New("blackhole_indexes must not be empty")
+	errMissingCORSOrigins     = errors.New("cors allowed_origins must be specified")
+	errEmptyTracesSourceType  = errors.New("traces sourcetypes must not be empty")
+	errMissingClientCA        = errors.New("client_certificate attributes require tls client_ca_file")
+	errDuplicatePath          = errors.New("event_path, raw_path, health_path and ack path must be distinct")
+	errNegativeTimestampSkew  = errors.New("timestamp max_future and max_past must not be negative")
+	errNegativeDedupTTL       = errors.New("dedup ttl must not be negative")
+	errInvalidDedupEntries    = errors.New("dedup max_entries must be positive")
+	errNegativeServerTimeout  = errors.New("read_timeout, read_header_timeout, write_timeout and idle_timeout must not be negative")
+	errNegativeMaxHeaderBytes = errors.New("max_header_bytes must not be negative")
+	errInvalidHTTP2FrameSize  = errors.New("http2 max_read_frame_size must be between 16KiB and 16MiB")
+	errNegativeMaxEventSize   = errors.New("max_event_size must not be negative")
+	errEmptyHeaderAttribute   = errors.New("headers_to_attributes headers and attributes must not be empty")
+	errEmptySourceTypeRename  = errors.New("sourcetype_rename sourcetypes must not be empty")
+	errNegativeEventsPerBatch = errors.New("max_events_per_batch must not be negative")
+	errNegativeTokensReload   = errors.New("tokens_file reload_interval must not be negative")
+	errEmptyProfilingField    = errors.New("profiling sourcetypes and resource_attributes must not be empty")
+)
+
 type SplittingStrategy string
 
 const (
@@ -21,12 +74,745 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+	// EventPath for event data collection, such as '/services/collector/event'. Events are accepted on any path
+	// not served by another endpoint when empty, the default.
+	EventPath string `mapstructure:"event_path"`
 	// RawPath for raw data collection, default is '/services/collector/raw'
 	RawPath string `mapstructure:"raw_path"`
+	// Encoding is the character encoding of request bodies, such as "iso-8859-1" or "shift_jis", transcoded to
+	// UTF-8. Default is "utf-8".
+	Encoding string `mapstructure:"encoding"`
+	// SanitizeInvalidUTF8 replaces the invalid UTF-8 sequences of UTF-8 request bodies with U+FFFD, so that
+	// received logs hold valid strings.
+	SanitizeInvalidUTF8 bool `mapstructure:"sanitize_invalid_utf8"`
 	// Splitting defines the splitting strategy used by the receiver when ingesting raw events. Can be set to "line" or "none". Default is "line".
 	Splitting SplittingStrategy `mapstructure:"splitting"`
+	// LineBreaker is the regular expression breaking raw events when splitting by line, instead of newlines, the
+	// text matched by its first capturing group being discarded like with the Splunk LINE_BREAKER setting.
+	LineBreaker string `mapstructure:"line_breaker"`
+	// UnixSocket configures serving the HEC endpoints on a Unix domain socket in addition to endpoint.
+	UnixSocket UnixSocketConfig `mapstructure:"unix_socket"`
+	// ReadTimeout is the maximum duration for reading requests, including their body. No timeout when zero, the
+	// default.
+	ReadTimeout time.Duration `mapstructure:"read_timeout"`
+	// ReadHeaderTimeout is the maximum duration for reading the headers of requests, default is 20s.
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	// WriteTimeout is the maximum duration before timing out writes of responses, default is 20s.
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	// IdleTimeout is the maximum duration to wait for the next request on idle keep-alive connections. ReadTimeout
+	// is used when zero, the default.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+	// MaxHeaderBytes is the maximum size in bytes of the headers of requests, default is 1MiB.
+	MaxHeaderBytes int `mapstructure:"max_header_bytes"`
+	// DisableKeepAlives closes connections after each request instead of keeping them open for the next ones.
+	DisableKeepAlives bool `mapstructure:"disable_keep_alives"`
+	// HTTP2 configures the HTTP/2 connections negotiated by TLS clients.
+	HTTP2 HTTP2Config `mapstructure:"http2"`
 	// HealthPath for health API, default is '/services/collector/health'
 	HealthPath string `mapstructure:"health_path"`
 	// HecToOtelAttrs creates a mapping from HEC metadata to attributes.
 	HecToOtelAttrs splunk.HecToOtelAttrs `mapstructure:"hec_metadata_to_otel_attrs"`
+	// Profile selects a predefined mapping from HEC metadata to attributes: "splunk", "otel-1.27" or "ecs".
+	// Fields of HecToOtelAttrs set to non default values take precedence over the profile.
+	Profile string `mapstructure:"profile"`
+	// RecordMetadata lists the HEC metadata fields, among "host", "source", "sourcetype" and "index", set as
+	// log record attributes instead of resource attributes.
+	RecordMetadata []string `mapstructure:"record_metadata"`
+	// Hostname configures how the host of events is normalized before being mapped to an attribute.
+	Hostname HostnameConfig `mapstructure:"hostname"`
+	// HostLookup configures enriching resources with the attributes of their host read from a lookup file.
+	HostLookup HostLookupConfig `mapstructure:"host_lookup"`
+	// Timestamp configures how the times of events are interpreted.
+	Timestamp TimestampConfig `mapstructure:"timestamp"`
+	// UseReceiveTimeOnMissing sets the timestamp of log records whose event has no time to the time the event was received at.
+	UseReceiveTimeOnMissing bool `mapstructure:"use_receive_time_on_missing"`
+	// PropagateTraceContext sets the W3C trace context of requests, taken from their traceparent header,
+	// on the log records produced from them that do not already belong to a trace.
+	PropagateTraceContext bool `mapstructure:"propagate_trace_context"`
+	// StrictSchema rejects events whose time is not a number, or which have keys other than time, host,
+	// source, sourcetype, index, event and fields.
+	StrictSchema bool `mapstructure:"strict_schema"`
+	// Fields configures how the fields of log events are converted.
+	Fields FieldsConfig `mapstructure:"fields"`
+	// PartialSuccess passes the logs of each resource of a request to the next consumer separately, so that
+	// resources refused with a permanent error do not fail the others.
+	PartialSuccess bool `mapstructure:"partial_success"`
+	// ParseJSONEvents sets the body of log records to the structured map held by events which are strings
+	// holding a JSON object, instead of the string.
+	ParseJSONEvents bool `mapstructure:"parse_json_events"`
+	// Severity configures setting the severity of log records from a field of events.
+	Severity SeverityConfig `mapstructure:"severity"`
+	// RawEvent configures attaching the original JSON of each received event to the produced log record.
+	RawEvent RawEventConfig `mapstructure:"raw_event"`
+	// SourceTypeRename maps the sourcetypes of received events to the sourcetypes they are renamed to, like the
+	// Splunk rename setting of sourcetypes.
+	SourceTypeRename map[string]string `mapstructure:"sourcetype_rename"`
+	// Multiline defines, per sourcetype, how consecutive events of a request are merged into a single log record.
+	Multiline map[string]MultilineConfig `mapstructure:"multiline"`
+	// Metrics configures how metric events are converted.
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	// Traces configures which events hold spans, passed to the traces pipeline.
+	Traces TracesConfig `mapstructure:"traces"`
+	// Profiling configures recognizing the events holding the profiling data of Splunk APM agents.
+	Profiling ProfilingConfig `mapstructure:"profiling"`
+	// OtelFidelity restores the log records encoded in the otel.fidelity field of events by Splunk HEC exporters
+	// with otel_fidelity enabled. It should only be enabled when clients are trusted, as restored log records keep
+	// the resource, attributes and severity they were encoded with.
+	OtelFidelity bool `mapstructure:"otel_fidelity"`
+	// MaxDecompressedSize is the maximum size in bytes of decompressed gzip or zstd request bodies. Zero means no limit.
+	MaxDecompressedSize int64 `mapstructure:"max_decompressed_size"`
+	// MaxContentLength is the maximum size in bytes of request bodies, as sent over the wire. Zero means no limit.
+	MaxContentLength int64 `mapstructure:"max_content_length"`
+	// MaxEventSize is the maximum size in bytes of the string events and raw events converted to log records. Zero
+	// means no limit.
+	MaxEventSize int `mapstructure:"max_event_size"`
+	// OversizedEvents is what is done with the events larger than MaxEventSize: "truncate" truncates them, marking
+	// their log records with the com.splunk.truncated attribute, "reject" rejects them as invalid events. Default is
+	// "truncate".
+	OversizedEvents string `mapstructure:"oversized_events"`
+	// MaxEventsPerBatch is the maximum number of log records passed at once to the next consumer. Larger requests
+	// are passed in several batches. Zero means no limit.
+	MaxEventsPerBatch int `mapstructure:"max_events_per_batch"`
+	// ResponseCompression configures gzip compression of ack and health responses.
+	ResponseCompression ResponseCompressionConfig `mapstructure:"response_compression"`
+	// Scope configures the instrumentation scope set on the produced logs.
+	Scope ScopeConfig `mapstructure:"scope"`
+	// Tenant configures extracting the tenant of requests from a header set by trusted gateways.
+	Tenant TenantConfig `mapstructure:"tenant"`
+	// ChannelAttribute is the resource attribute the HEC channel of requests is recorded in, such as
+	// 'com.splunk.hec.channel'. The channel is not recorded when empty.
+	ChannelAttribute string `mapstructure:"channel_attribute"`
+	// HeadersToAttributes maps the names of request headers to the resource attributes their value is recorded in,
+	// such as X-Forwarded-For to client.address.
+	HeadersToAttributes map[string]string `mapstructure:"headers_to_attributes"`
+	// ClientCertificate configures recording the identity of the client certificate requests are sent with.
+	ClientCertificate ClientCertificateConfig `mapstructure:"client_certificate"`
+	// Routing configures setting the route of events, chosen according to their sourcetype, as a resource attribute.
+	Routing RoutingConfig `mapstructure:"routing"`
+	// Tokens lists the HEC tokens accepted by the receiver. When not empty, requests
+	// not authorized with one of them are rejected.
+	Tokens []TokenConfig `mapstructure:"tokens"`
+	// TokensFile configures reading more HEC tokens from a file, which is reloaded when it changes.
+	TokensFile TokensFileConfig `mapstructure:"tokens_file"`
+	// BlackholeIndexes lists the indexes whose events are acknowledged but dropped, instead of being passed to
+	// the next consumer, like events sent to the nullQueue of Splunk.
+	BlackholeIndexes []string `mapstructure:"blackhole_indexes"`
+	// RateLimit configures limiting the rate of events received per HEC token or channel.
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	// AdmissionControl configures shedding requests while the pipeline refuses data.
+	AdmissionControl AdmissionControlConfig `mapstructure:"admission_control"`
+	// Ack configures HEC indexer acknowledgment.
+	Ack AckConfig `mapstructure:"ack"`
+	// InvalidEvents is what is done with the invalid events of requests: "reject" fails their request, "skip" passes
+	// the valid events of their request to the next consumer, answering the request with success and the positions of
+	// the skipped events. Default is "reject".
+	InvalidEvents string `mapstructure:"invalid_events"`
+	// Dedup configures suppressing the duplicate events resent by clients.
+	Dedup DedupConfig `mapstructure:"dedup"`
+	// Heartbeat configures emitting a log record when no data is received for a while.
+	Heartbeat HeartbeatConfig `mapstructure:"heartbeat"`
+	// Replay, when set, makes the receiver replay recorded HEC requests from disk instead of listening for requests.
+	Replay *ReplayConfig `mapstructure:"replay"`
+}
+
+// UnixSocketConfig defines the Unix domain socket the HEC endpoints are served on.
+type UnixSocketConfig struct {
+	// Path of the socket, such as '/var/run/splunkhec.sock'. Disabled when empty.
+	Path string `mapstructure:"path"`
+}
+
+// HostnameConfig defines how the host of events is normalized, preventing the
+// same host from producing different resources.
+type HostnameConfig struct {
+	// Lowercase converts hosts to lower case.
+	Lowercase bool `mapstructure:"lowercase"`
+	// StripDomain removes the domain from fully qualified host names, keeping the first label.
+	StripDomain bool `mapstructure:"strip_domain"`
+	// ReverseDNS configures resolving hosts sent as IP addresses to their name.
+	ReverseDNS ReverseDNSConfig `mapstructure:"reverse_dns"`
+}
+
+// ReverseDNSConfig defines how hosts sent as IP addresses are resolved to their name.
+type ReverseDNSConfig struct {
+	// Enabled replaces hosts sent as IP addresses with the name they resolve to.
+	// Hosts failing to resolve are kept as is.
+	Enabled bool `mapstructure:"enabled"`
+	// CacheTTL is the duration resolutions are cached for, default is 5m.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// FieldsConfig defines how the fields of log events are converted. Fields not listed are set as log record attributes.
+type FieldsConfig struct {
+	// ResourceAttributes lists the fields set as resource attributes instead of log record attributes.
+	ResourceAttributes []string `mapstructure:"resource_attributes"`
+	// Drop lists the fields which are not converted.
+	Drop []string `mapstructure:"drop"`
+	// Flatten converts the nested objects of fields to fields keyed by their path joined with dots, as done by
+	// Splunk, instead of rejecting their events.
+	Flatten bool `mapstructure:"flatten"`
+}
+
+// SeverityConfig defines how the severity of log records is set from a field of events.
+type SeverityConfig struct {
+	// Field is the field of events holding their severity, such as 'level'. Severity is not set when empty.
+	Field string `mapstructure:"field"`
+	// Mapping maps field values, case insensitively, to severity numbers between 1 (TRACE) and 24 (FATAL4).
+	// It extends the default mapping of common level names and syslog severities.
+	Mapping map[string]int `mapstructure:"mapping"`
+}
+
+// HostLookupConfig defines the lookup file mapping hosts to the resource attributes they are enriched with.
+type HostLookupConfig struct {
+	// Path of the lookup file, a CSV file with a "host" column and a column per attribute if its extension
+	// is ".csv", a JSON object mapping hosts to objects of attributes otherwise. Disabled when empty.
+	Path string `mapstructure:"path"`
+	// ReloadInterval is the interval the file is checked for changes at, default is 1m. Zero disables reloading.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+// TimestampConfig defines how the times of events are interpreted.
+type TimestampConfig struct {
+	// Unit of numeric times: "s", "ms", "us", "ns", or "auto" to detect the unit of each time from its magnitude.
+	// Default is "s", as defined by Splunk.
+	Unit string `mapstructure:"unit"`
+	// Layout is the Go time layout of times sent as strings which are not numbers, such as "2006-01-02T15:04:05Z07:00".
+	// Such times are rejected when empty.
+	Layout string `mapstructure:"layout"`
+	// MaxFuture is how far ahead of the time it is received the time of an event can be. Later times are out of
+	// range. No limit when zero, the default.
+	MaxFuture time.Duration `mapstructure:"max_future"`
+	// MaxPast is how far before the time it is received the time of an event can be. Earlier times are out of
+	// range. No limit when zero, the default.
+	MaxPast time.Duration `mapstructure:"max_past"`
+	// OutOfRange is what is done with events whose time is out of range: "reject" fails their request, "clamp"
+	// sets their time to the time they are received. Default is "reject".
+	OutOfRange string `mapstructure:"out_of_range"`
+	// Extract configures extracting the time of events without time from their text.
+	Extract TimestampExtractConfig `mapstructure:"extract"`
+	// NanosecondsField is the field of events holding their time as integer epoch nanoseconds, overriding their
+	// time, whose float seconds lose the precision of the nanoseconds. Disabled when empty, the default.
+	NanosecondsField string `mapstructure:"nanoseconds_field"`
+}
+
+// TimestampExtractConfig defines how the time of events is extracted from their text, similarly to the Splunk
+// TIME_PREFIX and TIME_FORMAT settings.
+type TimestampExtractConfig struct {
+	// Pattern is the regular expression matching the time in the text of events, held by its first capturing group,
+	// or by the whole match when it has none. Times are not extracted when empty, the default.
+	Pattern string `mapstructure:"pattern"`
+	// Layout is the Go time layout of extracted times, such as "Jan _2 15:04:05". Times without year are set in
+	// the year they are received.
+	Layout string `mapstructure:"layout"`
+	// Location is the IANA name of the time zone of extracted times without time zone. Default is "UTC".
+	Location string `mapstructure:"location"`
+}
+
+// RawEventConfig defines how the original JSON of received events is preserved.
+type RawEventConfig struct {
+	// Enabled attaches the original JSON of each event as the "splunk.raw_event" log record attribute.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxSize is the maximum size in bytes of a preserved event. Larger events are converted without the attribute.
+	MaxSize int `mapstructure:"max_size"`
+}
+
+// MultilineConfig defines how consecutive events of a sourcetype are merged,
+// similarly to the Splunk LINE_BREAKER and SHOULD_LINEMERGE settings. Events
+// are merged within a single request only.
+type MultilineConfig struct {
+	// LineStartPattern is the regular expression matching the first line of an event.
+	// Lines not matching it are appended to the preceding event.
+	LineStartPattern string `mapstructure:"line_start_pattern"`
+	// MaxLines is the maximum number of lines merged into a single event, default is 0 for no limit.
+	MaxLines int `mapstructure:"max_lines"`
+}
+
+// MetricsConfig defines how metric events are converted.
+type MetricsConfig struct {
+	// ResourceDimensions lists the dimensions of metric events, taken from their fields, that are
+	// set as resource attributes instead of data point attributes.
+	ResourceDimensions []string `mapstructure:"resource_dimensions"`
+	// Types declares the type of metrics, which are converted to gauges unless a rule matches them.
+	// Rules are evaluated in order, the first one matching a metric setting its type.
+	Types []MetricTypeConfig `mapstructure:"types"`
+}
+
+// TracesConfig defines which events hold spans, in the format of the spans sent by the Splunk HEC exporter.
+type TracesConfig struct {
+	// SourceTypes of the events holding spans. Events of any sourcetype shaped like spans hold spans when empty.
+	SourceTypes []string `mapstructure:"sourcetypes"`
+}
+
+// ProfilingConfig defines which events hold the profiling data of Splunk APM agents, converted to log records of
+// the otel.profiling instrumentation scope.
+type ProfilingConfig struct {
+	// Enabled recognizes the events holding profiling data. Such events are otherwise converted as regular events.
+	Enabled bool `mapstructure:"enabled"`
+	// SourceTypes of the events holding profiling data, default is otel.profiling.
+	SourceTypes []string `mapstructure:"sourcetypes"`
+	// ResourceAttributes lists the fields of profiling events set as resource attributes, along with the ones of
+	// Fields, default is service.name and deployment.environment.
+	ResourceAttributes []string `mapstructure:"resource_attributes"`
+}
+
+// MetricTypeConfig declares the type of the metrics sent with a sourcetype, or whose name matches a pattern.
+type MetricTypeConfig struct {
+	// SourceType of the events of the metrics. Any sourcetype when empty.
+	SourceType string `mapstructure:"sourcetype"`
+	// MetricNamePattern is the regular expression matching the name of the metrics, such as '\.count$'.
+	// Any name when empty.
+	MetricNamePattern string `mapstructure:"metric_name_pattern"`
+	// Type of the metrics: "gauge", "cumulative" for monotonic cumulative sums, or "delta" for monotonic delta sums.
+	Type string `mapstructure:"type"`
+}
+
+// ResponseCompressionConfig defines how responses are compressed for clients accepting gzip encoding.
+type ResponseCompressionConfig struct {
+	// Enabled compresses ack and health responses when the client sends "Accept-Encoding: gzip".
+	Enabled bool `mapstructure:"enabled"`
+	// MinSize is the size in bytes below which responses are sent uncompressed, default is 1024.
+	MinSize int `mapstructure:"min_size"`
+}
+
+// ScopeConfig defines the instrumentation scope set on the produced logs.
+type ScopeConfig struct {
+	// Name of the instrumentation scope, default is 'otelcol/splunkhecreceiver'.
+	Name string `mapstructure:"name"`
+	// Version of the instrumentation scope, default is the collector build version.
+	Version string `mapstructure:"version"`
+}
+
+// TenantConfig defines how the tenant of a request is extracted from a header set by a trusted gateway.
+type TenantConfig struct {
+	// Header holding the tenant, default is 'X-Scope-OrgID'.
+	Header string `mapstructure:"header"`
+	// TrustedProxies lists the CIDRs of the gateways allowed to set the tenant header.
+	// The header of requests coming from any other address is ignored. Tenant
+	// extraction is disabled when empty.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// ResourceAttribute is the resource attribute the tenant is stored in, default is 'tenant.id'.
+	ResourceAttribute string `mapstructure:"resource_attribute"`
+}
+
+// RoutingConfig defines how the route of events is chosen according to their sourcetype. Routes are
+// set as a resource attribute, which routing components such as the routing connector route data on.
+type RoutingConfig struct {
+	// Attribute is the resource attribute the route is set on, default is 'com.splunk.route'.
+	Attribute string `mapstructure:"attribute"`
+	// Routes are evaluated in order, the first one matching the sourcetype of events setting their route.
+	Routes []RouteConfig `mapstructure:"routes"`
+	// DefaultRoute is the route of events whose sourcetype matches no route. No route is set when empty.
+	DefaultRoute string `mapstructure:"default_route"`
+}
+
+// ClientCertificateConfig defines the resource attributes the identity of verified client certificates is recorded in.
+type ClientCertificateConfig struct {
+	// CommonNameAttribute is the resource attribute the subject common name of the certificate is recorded in,
+	// such as 'tls.client.subject.common_name'. Not recorded when empty.
+	CommonNameAttribute string `mapstructure:"common_name_attribute"`
+	// SubjectAltNamesAttribute is the resource attribute the DNS names, email addresses, IP addresses and URIs
+	// of the certificate are recorded in, as a slice. Not recorded when empty.
+	SubjectAltNamesAttribute string `mapstructure:"subject_alt_names_attribute"`
+}
+
+// RouteConfig defines the route of the events whose sourcetype matches a pattern.
+type RouteConfig struct {
+	// SourceTypePattern is the regular expression matching the sourcetype of the routed events.
+	SourceTypePattern string `mapstructure:"sourcetype_pattern"`
+	// Route set on the matching events.
+	Route string `mapstructure:"route"`
+}
+
+// TokenConfig defines a HEC token accepted by the receiver.
+type TokenConfig struct {
+	// Token is the value clients send in the "Authorization: Splunk <token>" header.
+	Token configopaque.String `mapstructure:"token"`
+	// Index set on the events sent with the token that do not specify one.
+	Index string `mapstructure:"index"`
+	// SourceType set on the events sent with the token that do not specify one.
+	SourceType string `mapstructure:"sourcetype"`
+	// Indexes lists the indexes events sent with the token can specify. Any index is allowed when empty.
+	Indexes []string `mapstructure:"indexes"`
+	// Disabled rejects the requests sent with the token, without removing it from the configuration.
+	Disabled bool `mapstructure:"disabled"`
+}
+
+// TokensFileConfig defines the file the HEC tokens accepted by the receiver are read from, along with the ones of
+// Tokens.
+type TokensFileConfig struct {
+	// Path of the file, holding a YAML or JSON list of tokens with the keys of Tokens. Disabled when empty.
+	Path string `mapstructure:"path"`
+	// ReloadInterval is the interval the file is checked for changes at, default is 1m. Zero disables reloading.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+// RateLimitConfig defines the rate of events each HEC token or channel can send.
+type RateLimitConfig struct {
+	// Key the rate is limited by: "token", "channel" or "tenant", the tenant extracted according to Tenant.
+	// Default is "token".
+	Key string `mapstructure:"key"`
+	// EventsPerSecond is the sustained rate of events allowed per key. Zero disables rate limiting.
+	EventsPerSecond float64 `mapstructure:"events_per_second"`
+	// Burst is the number of events a key can send at once, default is events_per_second.
+	Burst int `mapstructure:"burst"`
+	// Tenants overrides the rate of some tenants when the rate is limited by tenant.
+	Tenants map[string]TenantRateLimitConfig `mapstructure:"tenants"`
+}
+
+// TenantRateLimitConfig defines the rate of events a tenant can send.
+type TenantRateLimitConfig struct {
+	// EventsPerSecond is the sustained rate of events allowed to the tenant. Zero means no limit.
+	EventsPerSecond float64 `mapstructure:"events_per_second"`
+	// Burst is the number of events the tenant can send at once, default is events_per_second.
+	Burst int `mapstructure:"burst"`
+}
+
+// AdmissionControlConfig defines how requests are shed while the pipeline is saturated.
+type AdmissionControlConfig struct {
+	// ShedDuration is the duration data requests are rejected for, without being decoded, once the
+	// next consumer refuses data with a retryable error, or a request gets no slot among
+	// max_concurrent_requests in time. Zero disables shedding.
+	ShedDuration time.Duration `mapstructure:"shed_duration"`
+	// MaxConcurrentRequests is the maximum number of data requests decoded concurrently. Zero means no limit.
+	MaxConcurrentRequests int `mapstructure:"max_concurrent_requests"`
+	// QueueTimeout is the duration requests over max_concurrent_requests wait for another request to complete
+	// before being rejected. Zero rejects them right away.
+	QueueTimeout time.Duration `mapstructure:"queue_timeout"`
+	// RetryAfter, when set, answers the requests whose data the next consumer refuses with a retryable error
+	// with a 503 status and a Retry-After header of this duration, instead of a 500 status, so that clients back
+	// off. Zero, the default, disables it.
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+}
+
+// AckConfig defines how HEC indexer acknowledgment is served.
+type AckConfig struct {
+	// Enabled requires a channel on requests and returns an ack ID for each request whose data is accepted
+	// by the next consumer, which clients can then query. Default is false.
+	Enabled bool `mapstructure:"enabled"`
+	// Path of the ack endpoint, default is '/services/collector/ack'.
+	Path string `mapstructure:"path"`
+	// StorageID is the ID of the storage extension the ack state is persisted in, so that
+	// ack IDs survive restarts. The state is kept in memory only when not set.
+	StorageID *component.ID `mapstructure:"storage"`
+	// MaxPendingAcksPerChannel is the maximum number of ack IDs of a channel not queried yet. Requests sent on a
+	// channel reaching it are rejected until its ack IDs are queried. Default is 100000. Zero means no limit.
+	MaxPendingAcksPerChannel int `mapstructure:"max_pending_acks_per_channel"`
+	// MaxIdleTime is the time after which the channels on which no data was sent and whose ack IDs were not
+	// queried are forgotten, along with their ack IDs. Default is 10m. Zero means channels are never forgotten.
+	MaxIdleTime time.Duration `mapstructure:"max_idle_time"`
+}
+
+// HTTP2Config defines the settings of HTTP/2 connections.
+type HTTP2Config struct {
+	// MaxConcurrentStreams is the maximum number of concurrent requests per connection, default is 250.
+	MaxConcurrentStreams uint32 `mapstructure:"max_concurrent_streams"`
+	// MaxReadFrameSize is the maximum size in bytes of the frames read from clients, default is 1MiB.
+	MaxReadFrameSize uint32 `mapstructure:"max_read_frame_size"`
+}
+
+// DedupConfig defines how the duplicate events resent by clients after a timeout are suppressed.
+type DedupConfig struct {
+	// TTL is how long accepted events are remembered for. Events sent again on the same channel with the same
+	// body and time during the TTL are dropped. Disabled when zero, the default.
+	TTL time.Duration `mapstructure:"ttl"`
+	// MaxEntries is the maximum number of events remembered, the oldest ones being forgotten first. Default is 100000.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// HeartbeatConfig defines the liveness log records emitted by the receiver when idle, or periodically.
+type HeartbeatConfig struct {
+	// Interval without received data after which a heartbeat log record is emitted to the logs pipeline,
+	// and then again every interval while no data is received. If nothing or 0 is set, heartbeat is not enabled.
+	Interval time.Duration `mapstructure:"interval"`
+	// Periodic emits a heartbeat log record every interval, whether data is received or not, instead of only
+	// while no data is received.
+	Periodic bool `mapstructure:"periodic"`
+	// Index is the index of heartbeat log records. No index is set when empty, the default.
+	Index string `mapstructure:"index"`
+	// SourceType is the sourcetype of heartbeat log records. Default is "splunk_hec_receiver:heartbeat".
+	SourceType string `mapstructure:"sourcetype"`
+}
+
+// ReplayConfig defines how recorded HEC requests are replayed through the receiver.
+type ReplayConfig struct {
+	// Directory containing the recorded request payloads, one request per file.
+	// Files are replayed in lexical order. Files with a ".raw" extension are
+	// sent to the raw endpoint, all others to the event endpoint.
+	Directory string `mapstructure:"directory"`
+	// Interval to wait between two replayed requests. Default is 0, replaying as fast as possible.
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (c *Config) Validate() error {
+	if err := c.validatePaths(); err != nil {
+		return err
+	}
+	if _, err := newCharset(c.Encoding, c.SanitizeInvalidUTF8); err != nil {
+		return err
+	}
+	// The server ignores CORS settings without origins.
+	if c.CORS != nil && len(c.CORS.AllowedOrigins) == 0 && (len(c.CORS.AllowedHeaders) > 0 || c.CORS.MaxAge != 0) {
+		return errMissingCORSOrigins
+	}
+	if _, ok := mappingProfiles[c.Profile]; c.Profile != "" && !ok {
+		return fmt.Errorf("unknown profile %q", c.Profile)
+	}
+	for _, field := range c.RecordMetadata {
+		if field != host && field != source && field != sourcetype && field != index {
+			return fmt.Errorf("record_metadata %q must be one of host, source, sourcetype or index", field)
+		}
+	}
+	if _, ok := timeUnitSecondsIn[c.Timestamp.Unit]; !ok && c.Timestamp.Unit != "" && c.Timestamp.Unit != timeUnitAuto {
+		return fmt.Errorf("timestamp unit %q must be one of s, ms, us, ns or auto", c.Timestamp.Unit)
+	}
+	if c.Timestamp.MaxFuture < 0 || c.Timestamp.MaxPast < 0 {
+		return errNegativeTimestampSkew
+	}
+	if c.Timestamp.OutOfRange != "" && c.Timestamp.OutOfRange != outOfRangeReject && c.Timestamp.OutOfRange != outOfRangeClamp {
+		return fmt.Errorf("timestamp out_of_range %q must be one of reject or clamp", c.Timestamp.OutOfRange)
+	}
+	if _, err := newTimestampExtractor(c.Timestamp.Extract); err != nil {
+		return err
+	}
+	if c.HostLookup.ReloadInterval < 0 {
+		return errNegativeLookupReload
+	}
+	if c.Hostname.ReverseDNS.Enabled && c.Hostname.ReverseDNS.CacheTTL <= 0 {
+		return errInvalidReverseDNSTTL
+	}
+	for _, fields := range [][]string{c.Fields.ResourceAttributes, c.Fields.Drop} {
+		for _, field := range fields {
+			if field == "" {
+				return errEmptyFieldName
+			}
+		}
+	}
+	if len(c.Severity.Mapping) > 0 && c.Severity.Field == "" {
+		return errMissingSeverityField
+	}
+	if _, err := newSeverityMapper(c.Severity); err != nil {
+		return err
+	}
+	if c.RawEvent.Enabled && c.RawEvent.MaxSize <= 0 {
+		return errInvalidRawEventMaxSize
+	}
+	for from, to := range c.SourceTypeRename {
+		if from == "" || to == "" {
+			return errEmptySourceTypeRename
+		}
+	}
+	for sourceType, multiline := range c.Multiline {
+		if multiline.LineStartPattern == "" {
+			return fmt.Errorf("multiline line_start_pattern must be specified for sourcetype %q", sourceType)
+		}
+		if multiline.MaxLines < 0 {
+			return fmt.Errorf("multiline max_lines must not be negative for sourcetype %q", sourceType)
+		}
+	}
+	if _, err := newMultilineRules(c.Multiline); err != nil {
+		return err
+	}
+	if _, err := newLineBreaker(c.LineBreaker); err != nil {
+		return err
+	}
+	for _, dimension := range c.Metrics.ResourceDimensions {
+		if dimension == "" {
+			return errEmptyResourceDimension
+		}
+	}
+	if _, err := newMetricTypeRules(c); err != nil {
+		return err
+	}
+	for _, sourceType := range c.Traces.SourceTypes {
+		if sourceType == "" {
+			return errEmptyTracesSourceType
+		}
+	}
+	for _, fields := range [][]string{c.Profiling.SourceTypes, c.Profiling.ResourceAttributes} {
+		for _, field := range fields {
+			if field == "" {
+				return errEmptyProfilingField
+			}
+		}
+	}
+	if c.MaxDecompressedSize < 0 {
+		return errNegativeDecompressed
+	}
+	if c.MaxContentLength < 0 {
+		return errNegativeContentLength
+	}
+	if c.MaxEventSize < 0 {
+		return errNegativeMaxEventSize
+	}
+	if c.OversizedEvents != "" && c.OversizedEvents != oversizedEventsTruncate && c.OversizedEvents != oversizedEventsReject {
+		return fmt.Errorf("oversized_events %q must be one of truncate or reject", c.OversizedEvents)
+	}
+	if c.MaxEventsPerBatch < 0 {
+		return errNegativeEventsPerBatch
+	}
+	if c.ResponseCompression.MinSize < 0 {
+		return errNegativeCompressionMin
+	}
+	if len(c.Tenant.TrustedProxies) > 0 {
+		if c.Tenant.Header == "" {
+			return errMissingTenantHeader
+		}
+		if c.Tenant.ResourceAttribute == "" {
+			return errMissingTenantAttribute
+		}
+		if _, err := parseCIDRs(c.Tenant.TrustedProxies); err != nil {
+			return err
+		}
+	}
+	for header, attribute := range c.HeadersToAttributes {
+		if header == "" || attribute == "" {
+			return errEmptyHeaderAttribute
+		}
+	}
+	if c.ClientCertificate.CommonNameAttribute != "" || c.ClientCertificate.SubjectAltNamesAttribute != "" {
+		if c.TLSSetting == nil || c.TLSSetting.ClientCAFile == "" {
+			return errMissingClientCA
+		}
+	}
+	if len(c.Routing.Routes) > 0 || c.Routing.DefaultRoute != "" {
+		if c.Routing.Attribute == "" {
+			return errMissingRouteAttribute
+		}
+		if _, err := newSourceTypeRouter(c); err != nil {
+			return err
+		}
+	}
+	if err := validateTokens(c.Tokens); err != nil {
+		return err
+	}
+	if c.TokensFile.ReloadInterval < 0 {
+		return errNegativeTokensReload
+	}
+	for _, index := range c.BlackholeIndexes {
+		if index == "" {
+			return errEmptyBlackholeIndex
+		}
+	}
+	switch c.RateLimit.Key {
+	case "", rateLimitKeyToken, rateLimitKeyChannel:
+		if len(c.RateLimit.Tenants) > 0 {
+			return errRateLimitTenantsKey
+		}
+	case rateLimitKeyTenant:
+		if len(c.Tenant.TrustedProxies) == 0 {
+			return errRateLimitTenantProxies
+		}
+	default:
+		return fmt.Errorf("rate_limit key %q must be one of token, channel or tenant", c.RateLimit.Key)
+	}
+	if c.RateLimit.EventsPerSecond < 0 || c.RateLimit.Burst < 0 {
+		return errNegativeRateLimit
+	}
+	for _, limit := range c.RateLimit.Tenants {
+		if limit.EventsPerSecond < 0 || limit.Burst < 0 {
+			return errNegativeRateLimit
+		}
+	}
+	if c.AdmissionControl.ShedDuration < 0 {
+		return errNegativeShedDuration
+	}
+	if c.AdmissionControl.MaxConcurrentRequests < 0 || c.AdmissionControl.QueueTimeout < 0 {
+		return errNegativeConcurrency
+	}
+	if c.AdmissionControl.RetryAfter < 0 {
+		return errNegativeRetryAfter
+	}
+	if c.Ack.StorageID != nil && !c.Ack.Enabled {
+		return errAckStorageDisabled
+	}
+	if c.Ack.MaxPendingAcksPerChannel < 0 || c.Ack.MaxIdleTime < 0 {
+		return errNegativeAckLimits
+	}
+	if c.ReadTimeout < 0 || c.ReadHeaderTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		return errNegativeServerTimeout
+	}
+	if c.MaxHeaderBytes < 0 {
+		return errNegativeMaxHeaderBytes
+	}
+	if c.HTTP2.MaxReadFrameSize != 0 && (c.HTTP2.MaxReadFrameSize < minHTTP2FrameSize || c.HTTP2.MaxReadFrameSize > maxHTTP2FrameSize) {
+		return errInvalidHTTP2FrameSize
+	}
+	if c.InvalidEvents != "" && c.InvalidEvents != invalidEventsReject && c.InvalidEvents != invalidEventsSkip {
+		return fmt.Errorf("invalid_events %q must be one of reject or skip", c.InvalidEvents)
+	}
+	if c.Dedup.TTL < 0 {
+		return errNegativeDedupTTL
+	}
+	if c.Dedup.TTL > 0 && c.Dedup.MaxEntries <= 0 {
+		return errInvalidDedupEntries
+	}
+	if c.Heartbeat.Interval < 0 {
+		return errNegativeHeartbeat
+	}
+	if c.Replay != nil {
+		if c.Replay.Directory == "" {
+			return errMissingReplayDirectory
+		}
+		if c.Replay.Interval < 0 {
+			return errNegativeReplayInterval
+		}
+	}
+	return nil
+}
+
+// validatePaths checks the paths of the endpoints are absolute and distinct.
+func (c *Config) validatePaths() error {
+	paths := []struct {
+		name  string
+		value string
+	}{
+		{"event_path", c.EventPath},
+		{"raw_path", c.RawPath},
+		{"health_path", c.HealthPath},
+		{"ack path", c.Ack.Path},
+	}
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if path.value == "" && path.name == "event_path" {
+			continue
+		}
+		if !strings.HasPrefix(path.value, "/") {
+			return fmt.Errorf("%s %q must start with /", path.name, path.value)
+		}
+		if seen[path.value] {
+			return errDuplicatePath
+		}
+		seen[path.value] = true
+	}
+	return nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// validateTokens checks tokens are valid and unique.
+func validateTokens(tokens []TokenConfig) error {
+	seenTokens := make(map[configopaque.String]bool, len(tokens))
+	for _, token := range tokens {
+		if token.Token == "" {
+			return errEmptyToken
+		}
+		if seenTokens[token.Token] {
+			return errDuplicateToken
+		}
+		seenTokens[token.Token] = true
+		if token.Index != "" && !token.allowsIndex(token.Index) {
+			return fmt.Errorf("token index %q must be one of its indexes", token.Index)
+		}
+	}
+	return nil
 }
@@ -4,8 +4,10 @@
 package splunkhecreceiver
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +26,7 @@ func TestLoadConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 
+	ackStorageID := component.NewIDWithName("file_storage", "acks")
 	tests := []struct {
 		id       component.ID
 		expected component.Config
@@ -37,19 +40,178 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				HTTPServerSettings: confighttp.HTTPServerSettings{
 					Endpoint: "localhost:8088",
+					CORS: &confighttp.CORSSettings{
+						AllowedOrigins: []string{"https://*.example.com"},
+						AllowedHeaders: []string{"X-Custom-Header"},
+						MaxAge:         7200,
+					},
+				},
+				UnixSocket: UnixSocketConfig{
+					Path: "/var/run/splunkhec.sock",
+				},
+				ReadTimeout:       time.Minute,
+				ReadHeaderTimeout: 10 * time.Second,
+				WriteTimeout:      30 * time.Second,
+				IdleTimeout:       2 * time.Minute,
+				MaxHeaderBytes:    65536,
+				DisableKeepAlives: true,
+				HTTP2: HTTP2Config{
+					MaxConcurrentStreams: 100,
+					MaxReadFrameSize:     65536,
 				},
 				AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 					AccessTokenPassthrough: true,
 				},
-				RawPath:    "/foo",
-				Splitting:  SplittingStrategyLine,
-				HealthPath: "/bar",
+				EventPath:           "/baz",
+				RawPath:             "/foo",
+				Encoding:            "iso-8859-1",
+				SanitizeInvalidUTF8: true,
+				Splitting:           SplittingStrategyLine,
+				LineBreaker:         `([\r\n]+)\d{4}-\d{2}-\d{2}`,
+				HealthPath:          "/bar",
 				HecToOtelAttrs: splunk.HecToOtelAttrs{
 					Source:     "file.name",
 					SourceType: "foobar",
 					Index:      "myindex",
 					Host:       "myhostfield",
 				},
+				Profile:        "ecs",
+				RecordMetadata: []string{"source"},
+				Hostname: HostnameConfig{
+					Lowercase:   true,
+					StripDomain: true,
+					ReverseDNS: ReverseDNSConfig{
+						Enabled:  true,
+						CacheTTL: time.Minute,
+					},
+				},
+				HostLookup: HostLookupConfig{
+					Path:           "/etc/otel/hosts.csv",
+					ReloadInterval: 30 * time.Second,
+				},
+				Timestamp: TimestampConfig{
+					Unit:       "auto",
+					Layout:     "2006-01-02T15:04:05Z07:00",
+					MaxFuture:  24 * time.Hour,
+					MaxPast:    7 * 24 * time.Hour,
+					OutOfRange: "clamp",
+					Extract: TimestampExtractConfig{
+						Pattern:  `^(\w{3} [ \d]\d \d{2}:\d{2}:\d{2})`,
+						Layout:   "Jan _2 15:04:05",
+						Location: "Europe/Paris",
+					},
+					NanosecondsField: "time_ns",
+				},
+				UseReceiveTimeOnMissing: true,
+				PropagateTraceContext:   true,
+				StrictSchema:            true,
+				Fields: FieldsConfig{
+					ResourceAttributes: []string{"k8s.pod.name"},
+					Drop:               []string{"debug"},
+					Flatten:            true,
+				},
+				PartialSuccess:  true,
+				ParseJSONEvents: true,
+				Severity: SeverityConfig{
+					Field:   "level",
+					Mapping: map[string]int{"verbose": 5},
+				},
+				RawEvent: RawEventConfig{
+					Enabled: true,
+					MaxSize: 1024,
+				},
+				MaxDecompressedSize: 1048576,
+				MaxContentLength:    838860800,
+				MaxEventSize:        65536,
+				OversizedEvents:     "reject",
+				MaxEventsPerBatch:   5000,
+				ResponseCompression: ResponseCompressionConfig{
+					Enabled: true,
+					MinSize: 512,
+				},
+				Scope: ScopeConfig{
+					Name:    "custom",
+					Version: "1.0.0",
+				},
+				Tenant: TenantConfig{
+					Header:            "X-Tenant",
+					TrustedProxies:    []string{"10.0.0.0/8"},
+					ResourceAttribute: "tenant.name",
+				},
+				ChannelAttribute: "com.splunk.hec.channel",
+				HeadersToAttributes: map[string]string{
+					"X-Forwarded-For": "client.address",
+					"X-Tenant":        "tenant.id",
+				},
+				Routing: RoutingConfig{
+					Attribute:    "route",
+					Routes:       []RouteConfig{{SourceTypePattern: "^cisco:", Route: "security"}},
+					DefaultRoute: "default",
+				},
+				Tokens: []TokenConfig{
+					{Token: "00000000-0000-0000-0000-000000000001", Index: "main", SourceType: "app"},
+					{Token: "00000000-0000-0000-0000-000000000002", Indexes: []string{"audit"}, Disabled: true},
+				},
+				TokensFile: TokensFileConfig{
+					Path:           "/etc/otel/hec_tokens.yaml",
+					ReloadInterval: 10 * time.Second,
+				},
+				SourceTypeRename: map[string]string{"httpevent": "app:payments:access"},
+				Multiline: map[string]MultilineConfig{
+					"java": {
+						LineStartPattern: `^\d{4}-\d{2}-\d{2}`,
+						MaxLines:         100,
+					},
+				},
+				Metrics: MetricsConfig{
+					ResourceDimensions: []string{"k8s.pod.name"},
+					Types: []MetricTypeConfig{
+						{SourceType: "statsd", MetricNamePattern: `\.count$`, Type: "delta"},
+						{MetricNamePattern: "_total$", Type: "cumulative"},
+					},
+				},
+				Traces: TracesConfig{
+					SourceTypes: []string{"otel:span"},
+				},
+				Profiling: ProfilingConfig{
+					Enabled:            true,
+					SourceTypes:        []string{"otel.profiling", "pyroscope"},
+					ResourceAttributes: []string{"service.name"},
+				},
+				OtelFidelity:     true,
+				BlackholeIndexes: []string{"debug"},
+				RateLimit: RateLimitConfig{
+					Key:             "tenant",
+					EventsPerSecond: 1000,
+					Burst:           5000,
+					Tenants: map[string]TenantRateLimitConfig{
+						"premium": {EventsPerSecond: 10000, Burst: 50000},
+					},
+				},
+				AdmissionControl: AdmissionControlConfig{
+					ShedDuration:          5 * time.Second,
+					MaxConcurrentRequests: 16,
+					QueueTimeout:          100 * time.Millisecond,
+					RetryAfter:            10 * time.Second,
+				},
+				Ack: AckConfig{
+					Enabled:                  true,
+					Path:                     "/ack",
+					StorageID:                &ackStorageID,
+					MaxPendingAcksPerChannel: 1000,
+					MaxIdleTime:              time.Minute,
+				},
+				InvalidEvents: "skip",
+				Dedup: DedupConfig{
+					TTL:        10 * time.Minute,
+					MaxEntries: 5000,
+				},
+				Heartbeat: HeartbeatConfig{
+					Interval:   time.Minute,
+					Periodic:   true,
+					Index:      "_internal",
+					SourceType: "collector:heartbeat",
+				},
 			},
 		},
 		{
@@ -62,22 +224,79 @@ func TestLoadConfig(t *testing.T) {
 							CertFile: "/test.crt",
 							KeyFile:  "/test.key",
 						},
+						ClientCAFile: "/ca.crt",
 					},
 				},
+				ClientCertificate: ClientCertificateConfig{
+					CommonNameAttribute:      "tls.client.subject.common_name",
+					SubjectAltNamesAttribute: "tls.client.subject.alt_names",
+				},
 				AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 					AccessTokenPassthrough: false,
 				},
-				RawPath:    "/services/collector/raw",
-				Splitting:  SplittingStrategyLine,
-				HealthPath: "/services/collector/health",
+				ReadHeaderTimeout: 20 * time.Second,
+				WriteTimeout:      20 * time.Second,
+				RawPath:           "/services/collector/raw",
+				Splitting:         SplittingStrategyLine,
+				HealthPath:        "/services/collector/health",
 				HecToOtelAttrs: splunk.HecToOtelAttrs{
 					Source:     "com.splunk.source",
 					SourceType: "com.splunk.sourcetype",
 					Index:      "com.splunk.index",
 					Host:       "host.name",
 				},
+				Hostname: HostnameConfig{
+					ReverseDNS: ReverseDNSConfig{
+						CacheTTL: 5 * time.Minute,
+					},
+				},
+				HostLookup: HostLookupConfig{
+					ReloadInterval: time.Minute,
+				},
+				RawEvent: RawEventConfig{
+					MaxSize: 65536,
+				},
+				ResponseCompression: ResponseCompressionConfig{
+					MinSize: 1024,
+				},
+				Scope: ScopeConfig{
+					Name: "otelcol/splunkhecreceiver",
+				},
+				Tenant: TenantConfig{
+					Header:            "X-Scope-OrgID",
+					ResourceAttribute: "tenant.id",
+				},
+				Routing: RoutingConfig{
+					Attribute: "com.splunk.route",
+				},
+				TokensFile: TokensFileConfig{
+					ReloadInterval: time.Minute,
+				},
+				Profiling: ProfilingConfig{
+					SourceTypes:        []string{"otel.profiling"},
+					ResourceAttributes: []string{"service.name", "deployment.environment"},
+				},
+				Ack: AckConfig{
+					Path:                     "/services/collector/ack",
+					MaxPendingAcksPerChannel: 100000,
+					MaxIdleTime:              10 * time.Minute,
+				},
+				Dedup: DedupConfig{
+					MaxEntries: 100000,
+				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "replay"),
+			expected: func() component.Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Replay = &ReplayConfig{
+					Directory: "/recorded",
+					Interval:  100 * time.Millisecond,
+				}
+				return cfg
+			}(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,3 +313,499 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    error
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name: "client_certificate_without_client_ca",
+			modify: func(cfg *Config) {
+				cfg.ClientCertificate.CommonNameAttribute = "tls.client.subject.common_name"
+			},
+			err: errMissingClientCA,
+		},
+		{
+			name: "empty_sourcetype_rename",
+			modify: func(cfg *Config) {
+				cfg.SourceTypeRename = map[string]string{"httpevent": ""}
+			},
+			err: errEmptySourceTypeRename,
+		},
+		{
+			name: "empty_header_attribute",
+			modify: func(cfg *Config) {
+				cfg.HeadersToAttributes = map[string]string{"X-Tenant": ""}
+			},
+			err: errEmptyHeaderAttribute,
+		},
+		{
+			name: "client_certificate_with_client_ca",
+			modify: func(cfg *Config) {
+				cfg.TLSSetting = &configtls.TLSServerSetting{ClientCAFile: "/ca.crt"}
+				cfg.ClientCertificate.SubjectAltNamesAttribute = "tls.client.subject.alt_names"
+			},
+		},
+		{
+			name: "negative_max_concurrent_requests",
+			modify: func(cfg *Config) {
+				cfg.AdmissionControl.MaxConcurrentRequests = -1
+			},
+			err: errNegativeConcurrency,
+		},
+		{
+			name: "negative_retry_after",
+			modify: func(cfg *Config) {
+				cfg.AdmissionControl.RetryAfter = -time.Second
+			},
+			err: errNegativeRetryAfter,
+		},
+		{
+			name: "negative_queue_timeout",
+			modify: func(cfg *Config) {
+				cfg.AdmissionControl.QueueTimeout = -time.Second
+			},
+			err: errNegativeConcurrency,
+		},
+		{
+			name: "negative_rate_limit",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.EventsPerSecond = -1
+			},
+			err: errNegativeRateLimit,
+		},
+		{
+			name: "negative_rate_limit_burst",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.EventsPerSecond = 10
+				cfg.RateLimit.Burst = -1
+			},
+			err: errNegativeRateLimit,
+		},
+		{
+			name: "invalid_rate_limit_key",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.Key = "host"
+			},
+			err: errors.New(`rate_limit key "host" must be one of token, channel or tenant`),
+		},
+		{
+			name: "rate_limit_tenant_key_without_proxies",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.Key = "tenant"
+			},
+			err: errRateLimitTenantProxies,
+		},
+		{
+			name: "rate_limit_tenants_without_tenant_key",
+			modify: func(cfg *Config) {
+				cfg.RateLimit.Tenants = map[string]TenantRateLimitConfig{"premium": {EventsPerSecond: 10}}
+			},
+			err: errRateLimitTenantsKey,
+		},
+		{
+			name: "negative_tenant_rate_limit",
+			modify: func(cfg *Config) {
+				cfg.Tenant.TrustedProxies = []string{"10.0.0.0/8"}
+				cfg.RateLimit.Key = "tenant"
+				cfg.RateLimit.Tenants = map[string]TenantRateLimitConfig{"premium": {EventsPerSecond: -1}}
+			},
+			err: errNegativeRateLimit,
+		},
+		{
+			name: "ack_storage_without_ack",
+			modify: func(cfg *Config) {
+				storageID := component.NewIDWithName("file_storage", "acks")
+				cfg.Ack.StorageID = &storageID
+			},
+			err: errAckStorageDisabled,
+		},
+		{
+			name: "ack_negative_idle_time",
+			modify: func(cfg *Config) {
+				cfg.Ack.MaxIdleTime = -time.Minute
+			},
+			err: errNegativeAckLimits,
+		},
+		{
+			name: "empty_blackhole_index",
+			modify: func(cfg *Config) {
+				cfg.BlackholeIndexes = []string{"debug", ""}
+			},
+			err: errEmptyBlackholeIndex,
+		},
+		{
+			name: "negative_host_lookup_reload_interval",
+			modify: func(cfg *Config) {
+				cfg.HostLookup.ReloadInterval = -time.Second
+			},
+			err: errNegativeLookupReload,
+		},
+		{
+			name: "invalid_timestamp_unit",
+			modify: func(cfg *Config) {
+				cfg.Timestamp.Unit = "min"
+			},
+			err: errors.New(`timestamp unit "min" must be one of s, ms, us, ns or auto`),
+		},
+		{
+			name: "empty_field_name",
+			modify: func(cfg *Config) {
+				cfg.Fields.Drop = []string{""}
+			},
+			err: errEmptyFieldName,
+		},
+		{
+			name: "severity_missing_field",
+			modify: func(cfg *Config) {
+				cfg.Severity.Mapping = map[string]int{"verbose": 5}
+			},
+			err: errMissingSeverityField,
+		},
+		{
+			name: "severity_invalid_number",
+			modify: func(cfg *Config) {
+				cfg.Severity = SeverityConfig{Field: "level", Mapping: map[string]int{"verbose": 25}}
+			},
+			err: errors.New(`severity mapping of "verbose" must be between 1 and 24`),
+		},
+		{
+			name: "raw_event_invalid_max_size",
+			modify: func(cfg *Config) {
+				cfg.RawEvent = RawEventConfig{Enabled: true}
+			},
+			err: errInvalidRawEventMaxSize,
+		},
+		{
+			name: "negative_response_compression_min_size",
+			modify: func(cfg *Config) {
+				cfg.ResponseCompression.MinSize = -1
+			},
+			err: errNegativeCompressionMin,
+		},
+		{
+			name: "tenant_missing_header",
+			modify: func(cfg *Config) {
+				cfg.Tenant.TrustedProxies = []string{"10.0.0.0/8"}
+				cfg.Tenant.Header = ""
+			},
+			err: errMissingTenantHeader,
+		},
+		{
+			name: "tenant_missing_attribute",
+			modify: func(cfg *Config) {
+				cfg.Tenant.TrustedProxies = []string{"10.0.0.0/8"}
+				cfg.Tenant.ResourceAttribute = ""
+			},
+			err: errMissingTenantAttribute,
+		},
+		{
+			name: "negative_max_decompressed_size",
+			modify: func(cfg *Config) {
+				cfg.MaxDecompressedSize = -1
+			},
+			err: errNegativeDecompressed,
+		},
+		{
+			name: "invalid_reverse_dns_cache_ttl",
+			modify: func(cfg *Config) {
+				cfg.Hostname.ReverseDNS.Enabled = true
+				cfg.Hostname.ReverseDNS.CacheTTL = 0
+			},
+			err: errInvalidReverseDNSTTL,
+		},
+		{
+			name: "empty_resource_dimension",
+			modify: func(cfg *Config) {
+				cfg.Metrics.ResourceDimensions = []string{""}
+			},
+			err: errEmptyResourceDimension,
+		},
+		{
+			name: "cors_without_origins",
+			modify: func(cfg *Config) {
+				cfg.CORS = &confighttp.CORSSettings{AllowedHeaders: []string{"X-Custom-Header"}}
+			},
+			err: errMissingCORSOrigins,
+		},
+		{
+			name: "empty_traces_sourcetype",
+			modify: func(cfg *Config) {
+				cfg.Traces.SourceTypes = []string{""}
+			},
+			err: errEmptyTracesSourceType,
+		},
+		{
+			name: "empty_profiling_sourcetype",
+			modify: func(cfg *Config) {
+				cfg.Profiling.SourceTypes = []string{""}
+			},
+			err: errEmptyProfilingField,
+		},
+		{
+			name: "invalid_metric_type",
+			modify: func(cfg *Config) {
+				cfg.Metrics.Types = []MetricTypeConfig{{Type: "counter"}}
+			},
+			err: errors.New(`metrics type "counter" of rule 0 must be one of gauge, cumulative or delta`),
+		},
+		{
+			name: "negative_max_content_length",
+			modify: func(cfg *Config) {
+				cfg.MaxContentLength = -1
+			},
+			err: errNegativeContentLength,
+		},
+		{
+			name: "negative_max_event_size",
+			modify: func(cfg *Config) {
+				cfg.MaxEventSize = -1
+			},
+			err: errNegativeMaxEventSize,
+		},
+		{
+			name: "negative_max_events_per_batch",
+			modify: func(cfg *Config) {
+				cfg.MaxEventsPerBatch = -1
+			},
+			err: errNegativeEventsPerBatch,
+		},
+		{
+			name: "negative_tokens_reload_interval",
+			modify: func(cfg *Config) {
+				cfg.TokensFile.ReloadInterval = -time.Second
+			},
+			err: errNegativeTokensReload,
+		},
+		{
+			name: "negative_shed_duration",
+			modify: func(cfg *Config) {
+				cfg.AdmissionControl.ShedDuration = -time.Second
+			},
+			err: errNegativeShedDuration,
+		},
+		{
+			name: "token_index_not_allowed",
+			modify: func(cfg *Config) {
+				cfg.Tokens = []TokenConfig{{Token: "tok", Index: "main", Indexes: []string{"audit"}}}
+			},
+			err: errors.New(`token index "main" must be one of its indexes`),
+		},
+		{
+			name: "invalid_record_metadata",
+			modify: func(cfg *Config) {
+				cfg.RecordMetadata = []string{"source", "time"}
+			},
+			err: errors.New(`record_metadata "time" must be one of host, source, sourcetype or index`),
+		},
+		{
+			name: "missing_route_attribute",
+			modify: func(cfg *Config) {
+				cfg.Routing = RoutingConfig{DefaultRoute: "default"}
+			},
+			err: errMissingRouteAttribute,
+		},
+		{
+			name: "empty_route",
+			modify: func(cfg *Config) {
+				cfg.Routing.Routes = []RouteConfig{{SourceTypePattern: "^cisco:"}}
+			},
+			err: errors.New("routing route 0 must specify a route"),
+		},
+		{
+			name: "empty_token",
+			modify: func(cfg *Config) {
+				cfg.Tokens = []TokenConfig{{Index: "main"}}
+			},
+			err: errEmptyToken,
+		},
+		{
+			name: "duplicate_token",
+			modify: func(cfg *Config) {
+				cfg.Tokens = []TokenConfig{{Token: "a"}, {Token: "a", Index: "main"}}
+			},
+			err: errDuplicateToken,
+		},
+		{
+			name: "negative_heartbeat_interval",
+			modify: func(cfg *Config) {
+				cfg.Heartbeat.Interval = -time.Second
+			},
+			err: errNegativeHeartbeat,
+		},
+		{
+			name: "negative_timestamp_max_past",
+			modify: func(cfg *Config) {
+				cfg.Timestamp.MaxPast = -time.Hour
+			},
+			err: errNegativeTimestampSkew,
+		},
+		{
+			name: "missing_timestamp_extract_layout",
+			modify: func(cfg *Config) {
+				cfg.Timestamp.Extract.Pattern = `^\S+`
+			},
+			err: errMissingExtractLayout,
+		},
+		{
+			name: "negative_idle_timeout",
+			modify: func(cfg *Config) {
+				cfg.IdleTimeout = -time.Second
+			},
+			err: errNegativeServerTimeout,
+		},
+		{
+			name: "negative_max_header_bytes",
+			modify: func(cfg *Config) {
+				cfg.MaxHeaderBytes = -1
+			},
+			err: errNegativeMaxHeaderBytes,
+		},
+		{
+			name: "small_http2_frame_size",
+			modify: func(cfg *Config) {
+				cfg.HTTP2.MaxReadFrameSize = 1024
+			},
+			err: errInvalidHTTP2FrameSize,
+		},
+		{
+			name: "negative_dedup_ttl",
+			modify: func(cfg *Config) {
+				cfg.Dedup.TTL = -time.Minute
+			},
+			err: errNegativeDedupTTL,
+		},
+		{
+			name: "dedup_no_entries",
+			modify: func(cfg *Config) {
+				cfg.Dedup.TTL = time.Minute
+				cfg.Dedup.MaxEntries = 0
+			},
+			err: errInvalidDedupEntries,
+		},
+		{
+			name: "duplicate_path",
+			modify: func(cfg *Config) {
+				cfg.EventPath = cfg.RawPath
+			},
+			err: errDuplicatePath,
+		},
+		{
+			name: "replay_missing_directory",
+			modify: func(cfg *Config) {
+				cfg.Replay = &ReplayConfig{}
+			},
+			err: errMissingReplayDirectory,
+		},
+		{
+			name: "replay_negative_interval",
+			modify: func(cfg *Config) {
+				cfg.Replay = &ReplayConfig{Directory: "/recorded", Interval: -time.Second}
+			},
+			err: errNegativeReplayInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			assert.Equal(t, tt.err, cfg.Validate())
+		})
+	}
+}
+
+func TestValidateConfigInvalidOutOfRange(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timestamp.OutOfRange = "drop"
+	assert.EqualError(t, cfg.Validate(), `timestamp out_of_range "drop" must be one of reject or clamp`)
+}
+
+func TestValidateConfigUnsupportedEncoding(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = "klingon"
+	assert.EqualError(t, cfg.Validate(), `unsupported encoding "klingon"`)
+}
+
+func TestValidateConfigTimestampExtract(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timestamp.Extract = TimestampExtractConfig{Pattern: `^(\S+`, Layout: time.RFC3339}
+	assert.ErrorContains(t, cfg.Validate(), "invalid timestamp extract pattern")
+	cfg.Timestamp.Extract = TimestampExtractConfig{Pattern: `^\S+`, Layout: time.RFC3339, Location: "Mars/Olympus_Mons"}
+	assert.ErrorContains(t, cfg.Validate(), "invalid timestamp extract location")
+}
+
+func TestValidateConfigLineBreaker(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.LineBreaker = `\n`
+	assert.EqualError(t, cfg.Validate(), `line_breaker "\\n" must have a capturing group`)
+}
+
+func TestValidateConfigOversizedEvents(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.OversizedEvents = "drop"
+	assert.EqualError(t, cfg.Validate(), `oversized_events "drop" must be one of truncate or reject`)
+}
+
+func TestValidateConfigInvalidEvents(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.InvalidEvents = "ignore"
+	assert.EqualError(t, cfg.Validate(), `invalid_events "ignore" must be one of reject or skip`)
+}
+
+func TestValidateConfigRelativePath(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.EventPath = "ingest/hec/event"
+	assert.EqualError(t, cfg.Validate(), `event_path "ingest/hec/event" must start with /`)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.HealthPath = ""
+	assert.EqualError(t, cfg.Validate(), `health_path "" must start with /`)
+}
+
+func TestValidateConfigInvalidTrustedProxy(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Tenant.TrustedProxies = []string{"10.0.0.1"}
+	assert.ErrorContains(t, cfg.Validate(), `invalid trusted proxy "10.0.0.1"`)
+}
+
+func TestValidateConfigUnknownProfile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Profile = "unknown"
+	assert.EqualError(t, cfg.Validate(), `unknown profile "unknown"`)
+}
+
+func TestValidateConfigInvalidMultiline(t *testing.T) {
+	tests := []struct {
+		name      string
+		multiline MultilineConfig
+		err       string
+	}{
+		{
+			name: "missing_pattern",
+			err:  `multiline line_start_pattern must be specified for sourcetype "java"`,
+		},
+		{
+			name:      "invalid_pattern",
+			multiline: MultilineConfig{LineStartPattern: "["},
+			err:       `invalid multiline line_start_pattern for sourcetype "java"`,
+		},
+		{
+			name:      "negative_max_lines",
+			multiline: MultilineConfig{LineStartPattern: "^\\S", MaxLines: -1},
+			err:       `multiline max_lines must not be negative for sourcetype "java"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Multiline = map[string]MultilineConfig{"java": tt.multiline}
+			assert.ErrorContains(t, cfg.Validate(), tt.err)
+		})
+	}
+}
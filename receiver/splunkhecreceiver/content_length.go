@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+var errContentTooLarge = errors.New("request body exceeds max_content_length")
+
+type contentLimiterKey struct{}
+
+// limitContentLength returns next limiting the size of request bodies, as
+// sent over the wire, to max_content_length. Requests announcing a larger
+// body are rejected before it is read, the others once it is read past the
+// limit. It wraps the handler decompressing request bodies.
+func (r *splunkReceiver) limitContentLength(next http.Handler) http.Handler {
+	if r.config.MaxContentLength <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > r.config.MaxContentLength {
+			ctx := req.Context()
+			if r.logsConsumer == nil {
+				ctx = r.obsrecv.StartMetricsOp(ctx)
+			} else {
+				ctx = r.obsrecv.StartLogsOp(ctx)
+			}
+			r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, contentTooLargeRespBody, 0, errContentTooLarge)
+			return
+		}
+		limiter := &maxSizeReader{reader: req.Body, remaining: r.config.MaxContentLength, err: errContentTooLarge}
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{limiter, req.Body}
+		next.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), contentLimiterKey{}, limiter)))
+	})
+}
+
+// contentTooLarge returns whether the body of req was read past max_content_length.
+func contentTooLarge(req *http.Request) bool {
+	limiter, ok := req.Context().Value(contentLimiterKey{}).(*maxSizeReader)
+	return ok && limiter.exceeded
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_maxContentLength(t *testing.T) {
+	small := `{"event":"foo"}`
+	large := `{"event":"` + strings.Repeat("x", 64) + `"}`
+	tests := []struct {
+		name          string
+		path          string
+		encoding      string
+		body          []byte
+		unknownLength bool
+		wantStatus    int
+		wantBody      []byte
+		wantLogs      int
+	}{
+		{
+			name:       "within_limit",
+			path:       "/services/collector/event",
+			body:       []byte(small),
+			wantStatus: http.StatusOK,
+			wantLogs:   1,
+		},
+		{
+			name:       "content_length_too_large",
+			path:       "/services/collector/event",
+			body:       []byte(large),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   contentTooLargeRespBody,
+		},
+		{
+			name:          "chunked_too_large",
+			path:          "/services/collector/event",
+			body:          []byte(small + large),
+			unknownLength: true,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+			wantBody:      contentTooLargeRespBody,
+		},
+		{
+			name:          "raw_chunked_too_large",
+			path:          "/services/collector/raw",
+			body:          []byte(strings.Repeat("line\n", 16)),
+			unknownLength: true,
+			wantStatus:    http.StatusRequestEntityTooLarge,
+			wantBody:      contentTooLargeRespBody,
+		},
+		{
+			// The limit applies to the compressed body.
+			name:          "gzip_within_limit",
+			path:          "/services/collector/event",
+			encoding:      gzipEncoding,
+			body:          compress(t, gzipEncoding, `{"event":"`+strings.Repeat("x", 256)+`"}`),
+			unknownLength: true,
+			wantStatus:    http.StatusOK,
+			wantLogs:      1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Endpoint = "localhost:0" // Actually not creating the endpoint
+			config.MaxContentLength = 48
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, r.Shutdown(context.Background()))
+			}()
+
+			req := httptest.NewRequest("POST", "http://localhost:0"+tt.path, bytes.NewReader(tt.body))
+			if tt.unknownLength {
+				req.Body = io.NopCloser(bytes.NewReader(tt.body))
+				req.ContentLength = -1
+			}
+			if tt.encoding != "" {
+				req.Header.Set(httpContentEncodingHeader, tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.server.Handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != nil {
+				assert.Equal(t, string(tt.wantBody), w.Body.String())
+			}
+			assert.Equal(t, tt.wantLogs, sink.LogRecordCount())
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// hecCORSHeaders are the request headers of HEC clients, allowed in
+// cross-origin requests in addition to the configured ones.
+var hecCORSHeaders = []string{authorizationHeader, channelHeader, httpContentEncodingHeader}
+
+// corsSettings returns the CORS settings of the server, allowing the headers
+// browser-based HEC clients send without listing them in the configuration.
+func corsSettings(config *Config) *confighttp.CORSSettings {
+	if config.CORS == nil || len(config.CORS.AllowedOrigins) == 0 {
+		return config.CORS
+	}
+	settings := *config.CORS
+	settings.AllowedHeaders = append(append([]string(nil), config.CORS.AllowedHeaders...), hecCORSHeaders...)
+	return &settings
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func TestCORSSettings(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	assert.Nil(t, corsSettings(config))
+
+	config.CORS = &confighttp.CORSSettings{AllowedOrigins: []string{"https://example.com"}, AllowedHeaders: []string{"X-Custom-Header"}}
+	settings := corsSettings(config)
+	assert.Equal(t, []string{"X-Custom-Header", authorizationHeader, channelHeader, httpContentEncodingHeader}, settings.AllowedHeaders)
+	assert.Equal(t, []string{"X-Custom-Header"}, config.CORS.AllowedHeaders)
+}
+
+func Test_splunkhecReceiver_CORS(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.CORS = &confighttp.CORSSettings{AllowedOrigins: []string{"https://*.example.com"}}
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, "http://"+config.Endpoint+"/services/collector", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "authorization,x-splunk-request-channel")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	resp := preflight("https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	resp = preflight("https://evil.com")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+const zstdEncoding = "zstd"
+
+var errDecompressedTooLarge = errors.New("decompressed body exceeds max_decompressed_size")
+
+// serverOptions makes the HTTP server decompress gzip and zstd request bodies
+// with the decoders of the receiver, and answer bodies it cannot decompress
+// with HEC responses.
+func (r *splunkReceiver) serverOptions() []confighttp.ToServerOption {
+	return []confighttp.ToServerOption{
+		confighttp.WithDecoder(gzipEncoding, r.gzipDecoder),
+		confighttp.WithDecoder(zstdEncoding, r.zstdDecoder),
+		confighttp.WithErrorHandler(r.handleDecompressionError),
+	}
+}
+
+func (r *splunkReceiver) handleDecompressionError(resp http.ResponseWriter, req *http.Request, errorMsg string, _ int) {
+	ctx := req.Context()
+	if r.logsConsumer == nil {
+		ctx = r.obsrecv.StartMetricsOp(ctx)
+	} else {
+		ctx = r.obsrecv.StartLogsOp(ctx)
+	}
+	err := errors.New(errorMsg)
+	if contentTooLarge(req) {
+		r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, contentTooLargeRespBody, 0, errContentTooLarge)
+		return
+	}
+	switch req.Header.Get(httpContentEncodingHeader) {
+	case gzipEncoding:
+		r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, 0, err)
+	case zstdEncoding:
+		r.failRequest(ctx, resp, http.StatusBadRequest, errZstdReaderRespBody, 0, err)
+	default:
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, 0, errInvalidEncoding)
+	}
+}
+
+// decodedBody reads the decompressed body of a request, keeping track of the
+// failure to read it, if any, as decoders may not report it.
+type decodedBody struct {
+	io.ReadCloser
+	req *http.Request
+	err error
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		b.err = err
+	}
+	return n, err
+}
+
+// tooLarge returns the response to the request when reading its body failed
+// because it is larger than max_content_length, or decompressed to more than
+// max_decompressed_size, and nil otherwise.
+func (b *decodedBody) tooLarge() []byte {
+	switch {
+	case contentTooLarge(b.req):
+		return contentTooLargeRespBody
+	case errors.Is(b.err, errDecompressedTooLarge):
+		return decompressedTooLargeBody
+	}
+	return nil
+}
+
+// decodeBody returns a reader of the body of req decompressed according to
+// encoding, which is expected to be supported. Bodies are usually already
+// decompressed by the HTTP server, in which case encoding is empty. The
+// returned response body describes the failure to initialize the
+// decompression, if any. The body is transcoded to UTF-8 from the configured
+// encoding.
+func (r *splunkReceiver) decodeBody(encoding string, req *http.Request) (*decodedBody, []byte, error) {
+	var body io.ReadCloser
+	switch encoding {
+	case gzipEncoding:
+		var err error
+		if body, err = r.gzipDecoder(req.Body); err != nil {
+			return nil, errGzipReaderRespBody, err
+		}
+	case zstdEncoding:
+		var err error
+		if body, err = r.zstdDecoder(req.Body); err != nil {
+			return nil, errZstdReaderRespBody, err
+		}
+	default:
+		body = io.NopCloser(req.Body)
+	}
+	return &decodedBody{ReadCloser: r.charset.transcode(body), req: req}, nil, nil
+}
+
+func isSupportedEncoding(encoding string) bool {
+	return encoding == "" || encoding == gzipEncoding || encoding == zstdEncoding
+}
+
+func (r *splunkReceiver) gzipDecoder(body io.ReadCloser) (io.ReadCloser, error) {
+	reader := r.gzipReaderPool.Get().(*gzip.Reader)
+	if err := reader.Reset(body); err != nil {
+		r.gzipReaderPool.Put(reader)
+		return nil, err
+	}
+	return r.limitDecompressed(reader, func() { r.gzipReaderPool.Put(reader) }), nil
+}
+
+func (r *splunkReceiver) zstdDecoder(body io.ReadCloser) (io.ReadCloser, error) {
+	decoder := r.zstdDecoderPool.Get().(*zstd.Decoder)
+	if err := decoder.Reset(body); err != nil {
+		r.zstdDecoderPool.Put(decoder)
+		return nil, err
+	}
+	return r.limitDecompressed(decoder, func() { r.zstdDecoderPool.Put(decoder) }), nil
+}
+
+func newZstdDecoder() interface{} {
+	// The options are valid, so creating the decoder cannot fail.
+	decoder, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	return decoder
+}
+
+// limitDecompressed returns reader limited to max_decompressed_size, calling
+// release once closed.
+func (r *splunkReceiver) limitDecompressed(reader io.Reader, release func()) io.ReadCloser {
+	if r.config.MaxDecompressedSize > 0 {
+		reader = &maxSizeReader{reader: reader, remaining: r.config.MaxDecompressedSize, err: errDecompressedTooLarge}
+	}
+	return &releasingReader{Reader: reader, release: release}
+}
+
+// releasingReader calls release when closed, returning the decoder it reads
+// from to its pool.
+type releasingReader struct {
+	io.Reader
+	release func()
+}
+
+func (r *releasingReader) Close() error {
+	r.release()
+	return nil
+}
+
+// maxSizeReader fails with err once more than the allowed number of bytes is
+// read from reader.
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+	err       error
+	exceeded  bool
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.exceeded {
+		return 0, m.err
+	}
+	if m.remaining <= 0 {
+		// Probe for a single extra byte to tell a body of exactly the
+		// maximum size from a larger one.
+		var probe [1]byte
+		n, err := m.reader.Read(probe[:])
+		if n > 0 {
+			m.exceeded = true
+			return 0, m.err
+		}
+		return 0, err
+	}
+	if int64(len(p)) > m.remaining {
+		p = p[:m.remaining]
+	}
+	n, err := m.reader.Read(p)
+	m.remaining -= int64(n)
+	return n, err
+}
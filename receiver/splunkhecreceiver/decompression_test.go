@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func compress(t *testing.T, encoding string, body string) []byte {
+	var buf bytes.Buffer
+	switch encoding {
+	case gzipEncoding:
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	case zstdEncoding:
+		writer, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(body))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	default:
+		buf.WriteString(body)
+	}
+	return buf.Bytes()
+}
+
+func Test_splunkhecReceiver_decompression(t *testing.T) {
+	event := `{"event":"foo"}`
+	tests := []struct {
+		name       string
+		path       string
+		encoding   string
+		body       []byte
+		wantStatus int
+		wantBody   []byte
+		wantLogs   int
+	}{
+		{
+			name:       "gzip",
+			path:       "/services/collector/event",
+			encoding:   gzipEncoding,
+			body:       compress(t, gzipEncoding, event),
+			wantStatus: http.StatusOK,
+			wantBody:   okRespBody,
+			wantLogs:   1,
+		},
+		{
+			name:       "zstd",
+			path:       "/services/collector/event",
+			encoding:   zstdEncoding,
+			body:       compress(t, zstdEncoding, event),
+			wantStatus: http.StatusOK,
+			wantBody:   okRespBody,
+			wantLogs:   1,
+		},
+		{
+			name:       "zstd_raw",
+			path:       "/services/collector/raw",
+			encoding:   zstdEncoding,
+			body:       compress(t, zstdEncoding, "foo\nbar"),
+			wantStatus: http.StatusOK,
+			wantLogs:   2,
+		},
+		{
+			name:       "bad_zstd",
+			path:       "/services/collector/event",
+			encoding:   zstdEncoding,
+			body:       []byte(event),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, 0),
+		},
+		{
+			name:       "uncompressed_not_limited",
+			path:       "/services/collector/event",
+			body:       []byte(`{"event":"` + strings.Repeat("x", 64) + `"}`),
+			wantStatus: http.StatusOK,
+			wantBody:   okRespBody,
+			wantLogs:   1,
+		},
+		{
+			name:       "gzip_too_large",
+			path:       "/services/collector/event",
+			encoding:   gzipEncoding,
+			body:       compress(t, gzipEncoding, `{"event":"`+strings.Repeat("x", 64)+`"}`),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   decompressedTooLargeBody,
+		},
+		{
+			name:       "zstd_too_large",
+			path:       "/services/collector/event",
+			encoding:   zstdEncoding,
+			body:       compress(t, zstdEncoding, event+event+event+event),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   decompressedTooLargeBody,
+		},
+		{
+			name:       "raw_too_large",
+			path:       "/services/collector/raw",
+			encoding:   gzipEncoding,
+			body:       compress(t, gzipEncoding, strings.Repeat("foo\n", 16)),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   decompressedTooLargeBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Endpoint = "localhost:0" // Actually not creating the endpoint
+			config.MaxDecompressedSize = int64(len(event) * 2)
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				require.NoError(t, r.Shutdown(context.Background()))
+			}()
+
+			req := httptest.NewRequest("POST", "http://localhost:0"+tt.path, bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set(httpContentEncodingHeader, tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			r.server.Handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != nil {
+				assert.Equal(t, string(tt.wantBody), w.Body.String())
+			}
+			assert.Equal(t, tt.wantLogs, sink.LogRecordCount())
+		})
+	}
+}
+
+func Test_maxSizeReader(t *testing.T) {
+	reader := &maxSizeReader{reader: strings.NewReader("0123"), remaining: 4, err: errDecompressedTooLarge}
+	got, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "0123", string(got))
+	assert.False(t, reader.exceeded)
+
+	reader = &maxSizeReader{reader: strings.NewReader("01234"), remaining: 4, err: errDecompressedTooLarge}
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, errDecompressedTooLarge)
+	assert.True(t, reader.exceeded)
+}
+
+func Test_splunkhecReceiver_serverDecompression(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = addr
+	config.MaxDecompressedSize = 32
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantStatus int
+		wantBody   []byte
+	}{
+		{
+			name:       "zstd",
+			encoding:   zstdEncoding,
+			body:       compress(t, zstdEncoding, `{"event":"foo"}`),
+			wantStatus: http.StatusOK,
+			wantBody:   okRespBody,
+		},
+		{
+			name:       "zstd_too_large",
+			encoding:   zstdEncoding,
+			body:       compress(t, zstdEncoding, `{"event":"`+strings.Repeat("x", 64)+`"}`),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantBody:   decompressedTooLargeBody,
+		},
+		{
+			name:       "bad_gzip",
+			encoding:   gzipEncoding,
+			body:       []byte(`{"event":"foo"}`),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   errGzipReaderRespBody,
+		},
+		{
+			name:       "unsupported_encoding",
+			encoding:   "superzipper",
+			body:       []byte(`{"event":"foo"}`),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   invalidEncodingRespBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "http://"+addr+"/services/collector/event", bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set(httpContentEncodingHeader, tt.encoding)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, string(tt.wantBody), string(body))
+		})
+	}
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// dedupCache remembers the events accepted during the configured TTL, so that
+// the events forwarders resend after a timeout are suppressed. Events are
+// identified by a hash of their channel, metadata, time, body and fields.
+type dedupCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu       sync.Mutex
+	expiries map[uint64]time.Time
+	// queue holds the remembered keys in the order they expire.
+	queue []dedupEntry
+}
+
+type dedupEntry struct {
+	key    uint64
+	expiry time.Time
+}
+
+func newDedupCache(config *Config) *dedupCache {
+	if config.Dedup.TTL == 0 {
+		return nil
+	}
+	return &dedupCache{
+		ttl:        config.Dedup.TTL,
+		maxEntries: config.Dedup.MaxEntries,
+		expiries:   map[uint64]time.Time{},
+	}
+}
+
+// dedupJSON encodes the body and fields of events with sorted keys, so that
+// an event sent again has the same key.
+var dedupJSON = jsoniter.Config{UseNumber: true, SortMapKeys: true}.Froze()
+
+// dedupKey returns the key identifying event sent on channel, from its
+// metadata, time, body and fields, so that the same line sent at the same time
+// from different hosts or sources is not taken for a duplicate.
+func dedupKey(channel string, event *splunk.Event) (uint64, error) {
+	body, err := dedupJSON.Marshal(event.Event)
+	if err != nil {
+		return 0, err
+	}
+	fields, err := dedupJSON.Marshal(event.Fields)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	var b [8]byte
+	// Values are prefixed with their length, so that they cannot run into each other.
+	write := func(value []byte) {
+		binary.BigEndian.PutUint64(b[:], uint64(len(value)))
+		_, _ = h.Write(b[:])
+		_, _ = h.Write(value)
+	}
+	for _, value := range []string{channel, event.Host, event.Source, event.SourceType, event.Index} {
+		write([]byte(value))
+	}
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(event.Time))
+	_, _ = h.Write(b[:])
+	write(body)
+	write(fields)
+	return h.Sum64(), nil
+}
+
+// seen reports whether the event identified by key was accepted during the
+// TTL preceding now.
+func (d *dedupCache) seen(key uint64, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	expiry, ok := d.expiries[key]
+	return ok && now.Before(expiry)
+}
+
+// remember remembers the events identified by keys, accepted at now, evicting
+// the expired events and the oldest ones over the maximum number of entries.
+func (d *dedupCache) remember(keys []uint64, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	expiry := now.Add(d.ttl)
+	for _, key := range keys {
+		d.expiries[key] = expiry
+		d.queue = append(d.queue, dedupEntry{key: key, expiry: expiry})
+	}
+	for len(d.queue) > 0 && (!now.Before(d.queue[0].expiry) || len(d.expiries) > d.maxEntries) {
+		entry := d.queue[0]
+		d.queue = d.queue[1:]
+		// Keys remembered again have a later expiry, set by a later entry.
+		if d.expiries[entry.key].Equal(entry.expiry) {
+			delete(d.expiries, entry.key)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_dedupKey(t *testing.T) {
+	key := func(channel string, event *splunk.Event) uint64 {
+		k, err := dedupKey(channel, event)
+		require.NoError(t, err)
+		return k
+	}
+	event := &splunk.Event{Event: map[string]interface{}{"message": "foo", "level": "info"}, Time: 1.5e9, Host: "h", Fields: map[string]interface{}{"a": "1", "b": "2"}}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, key("ch", event), key("ch", &splunk.Event{Event: map[string]interface{}{"level": "info", "message": "foo"}, Time: 1.5e9, Host: "h", Fields: map[string]interface{}{"b": "2", "a": "1"}}))
+	}
+	assert.NotEqual(t, key("ch", event), key("other", event))
+	for _, other := range []*splunk.Event{
+		{Event: event.Event, Time: 1.5e9, Host: "other", Fields: event.Fields},
+		{Event: event.Event, Time: 1.5e9, Host: "h", Source: "s", Fields: event.Fields},
+		{Event: event.Event, Time: 1.5e9, Host: "h", SourceType: "st", Fields: event.Fields},
+		{Event: event.Event, Time: 1.5e9, Host: "h", Index: "i", Fields: event.Fields},
+		{Event: event.Event, Time: 1.5e9, Host: "h", Fields: map[string]interface{}{"a": "1"}},
+	} {
+		assert.NotEqual(t, key("ch", event), key("ch", other))
+	}
+	// Metadata cannot run into each other.
+	assert.NotEqual(t, key("ch", &splunk.Event{Event: "foo", Host: "ab"}), key("cha", &splunk.Event{Event: "foo", Host: "b"}))
+	assert.NotEqual(t, key("ch", event), key("ch", &splunk.Event{Event: map[string]interface{}{"message": "bar"}, Time: 1.5e9, Host: "h", Fields: event.Fields}))
+	assert.NotEqual(t, key("ch", event), key("ch", &splunk.Event{Event: event.Event, Time: 1.5e9 + 1, Host: "h", Fields: event.Fields}))
+}
+
+func Test_dedupCache(t *testing.T) {
+	d := newDedupCache(&Config{Dedup: DedupConfig{TTL: time.Minute, MaxEntries: 2}})
+	now := time.Unix(1.5e9, 0)
+
+	d.remember([]uint64{1, 2}, now)
+	assert.True(t, d.seen(1, now.Add(59*time.Second)))
+	assert.True(t, d.seen(2, now.Add(59*time.Second)))
+	assert.False(t, d.seen(3, now))
+	assert.False(t, d.seen(1, now.Add(time.Minute)))
+
+	// Remembering a key again extends its TTL.
+	d.remember([]uint64{1}, now.Add(30*time.Second))
+	assert.True(t, d.seen(1, now.Add(80*time.Second)))
+
+	// The oldest keys are evicted over max_entries.
+	d.remember([]uint64{3}, now.Add(40*time.Second))
+	assert.Len(t, d.expiries, 2)
+	assert.False(t, d.seen(2, now.Add(40*time.Second)))
+	assert.True(t, d.seen(3, now.Add(40*time.Second)))
+
+	// Expired keys are evicted.
+	d.remember(nil, now.Add(time.Hour))
+	assert.Empty(t, d.expiries)
+	assert.Empty(t, d.queue)
+
+	assert.Nil(t, newDedupCache(&Config{}))
+}
+
+func Test_splunkhecReceiver_dedup(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Dedup.TTL = time.Minute
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(channel string, body string) int {
+		req := httptest.NewRequest("POST", "http://localhost/services/collector?channel="+channel, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.handleReq(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("ch1", `{"event":"foo","time":1500000000}{"event":"bar","time":1500000000}`))
+	assert.Equal(t, 2, sink.LogRecordCount())
+
+	// Resent events are dropped, new ones and the ones sent on another channel are not.
+	assert.Equal(t, http.StatusOK, send("ch1", `{"event":"foo","time":1500000000}{"event":"baz","time":1500000000}`))
+	assert.Equal(t, 3, sink.LogRecordCount())
+	assert.Equal(t, http.StatusOK, send("ch1", `{"event":"bar","time":1500000000}`))
+	assert.Equal(t, 3, sink.LogRecordCount())
+	assert.Equal(t, http.StatusOK, send("ch2", `{"event":"foo","time":1500000000}`))
+	assert.Equal(t, 4, sink.LogRecordCount())
+
+	// The same line sent at the same time by different hosts is not a duplicate.
+	assert.Equal(t, http.StatusOK, send("", `{"event":"line","time":1500000000,"host":"h1"}{"event":"line","time":1500000000,"host":"h2"}`))
+	assert.Equal(t, 6, sink.LogRecordCount())
+	assert.Equal(t, http.StatusOK, send("", `{"event":"line","time":1500000000,"host":"h2"}`))
+	assert.Equal(t, 6, sink.LogRecordCount())
+
+	// Events of failed requests are not remembered, so that they can be retried.
+	r.logsConsumer = consumertest.NewErr(errors.New("busy"))
+	assert.Equal(t, http.StatusInternalServerError, send("ch1", `{"event":"retried","time":1500000000}`))
+	r.logsConsumer = sink
+	assert.Equal(t, http.StatusOK, send("ch1", `{"event":"retried","time":1500000000}`))
+	assert.Equal(t, 7, sink.LogRecordCount())
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// hecJSON decodes numbers as json.Number, so they are converted without
+// going through float64 and losing the precision of large integers.
+var hecJSON = jsoniter.Config{UseNumber: true}.Froze()
+
+// strictHECJSON decodes events as hecJSON does, rejecting unknown keys.
+var strictHECJSON = jsoniter.Config{UseNumber: true, DisallowUnknownFields: true}.Froze()
+
+var errNonNumericTime = errors.New("time must be a number")
+
+// hecEvent is the JSON representation of an event sent to the event endpoint.
+type hecEvent struct {
+	Time       interface{}            `json:"time,omitempty"`
+	Host       string                 `json:"host"`
+	Source     string                 `json:"source,omitempty"`
+	SourceType string                 `json:"sourcetype,omitempty"`
+	Index      string                 `json:"index,omitempty"`
+	Event      interface{}            `json:"event"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+}
+
+// validateSchema checks that e, decoded from raw, strictly follows the HEC
+// event schema: its time, if any, is a number, and it has no unknown key.
+// Metadata are strings and fields are flat objects whatever the schema mode.
+func (e *hecEvent) validateSchema(raw []byte) error {
+	if _, ok := e.Time.(json.Number); !ok && e.Time != nil {
+		return errNonNumericTime
+	}
+	var strict hecEvent
+	if err := strictHECJSON.Unmarshal(raw, &strict); err != nil {
+		return fmt.Errorf("invalid event keys: %w", err)
+	}
+	return nil
+}
+
+// toEvent returns the splunk event represented by e. Numbers of log events are
+// converted to int64 when they are integers in its range, to float64 when they
+// are not integers, and kept as strings when they overflow. Numbers of metric
+// events are converted to float64, Splunk storing metric values as doubles.
+// The time of e is converted to epoch seconds by timestamps.
+func (e *hecEvent) toEvent(event *splunk.Event, timestamps *timestampParser) error {
+	*event = splunk.Event{
+		Host:       e.Host,
+		Source:     e.Source,
+		SourceType: e.SourceType,
+		Index:      e.Index,
+		Event:      e.Event,
+		Fields:     e.Fields,
+	}
+	switch t := e.Time.(type) {
+	case json.Number:
+		time, err := t.Float64()
+		if err != nil {
+			return err
+		}
+		event.Time = timestamps.fromNumber(time)
+	case string:
+		time, err := timestamps.fromString(t)
+		if err != nil {
+			return err
+		}
+		event.Time = time
+	}
+
+	convert := convertLogNumber
+	if event.IsMetric() {
+		convert = convertMetricNumber
+	}
+	event.Event = convertNumbers(event.Event, convert)
+	for k, v := range event.Fields {
+		event.Fields[k] = convertNumbers(v, convert)
+	}
+	return nil
+}
+
+// parseJSONObject returns the map represented by event when it is a string
+// holding a JSON object, and event as is otherwise.
+func parseJSONObject(event interface{}) interface{} {
+	s, ok := event.(string)
+	if !ok || !strings.HasPrefix(strings.TrimSpace(s), "{") {
+		return event
+	}
+	var object map[string]interface{}
+	if err := hecJSON.UnmarshalFromString(s, &object); err != nil {
+		return event
+	}
+	return convertNumbers(object, convertLogNumber)
+}
+
+// convertNumbers replaces the json.Number values held by value with their conversion.
+func convertNumbers(value interface{}, convert func(json.Number) interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		return convert(v)
+	case map[string]interface{}:
+		for k, elt := range v {
+			v[k] = convertNumbers(elt, convert)
+		}
+	case []interface{}:
+		for i, elt := range v {
+			v[i] = convertNumbers(elt, convert)
+		}
+	}
+	return value
+}
+
+func convertLogNumber(n json.Number) interface{} {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i
+	}
+	if !isInteger(n) {
+		if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+			return f
+		}
+	}
+	return string(n)
+}
+
+func convertMetricNumber(n json.Number) interface{} {
+	if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+		return f
+	}
+	return string(n)
+}
+
+// isInteger returns whether n is written as an integer, without fraction nor exponent.
+func isInteger(n json.Number) bool {
+	for _, c := range n {
+		if c == '.' || c == 'e' || c == 'E' {
+			return false
+		}
+	}
+	return true
+}
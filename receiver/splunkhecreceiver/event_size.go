@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+const (
+	oversizedEventsTruncate = "truncate"
+	oversizedEventsReject   = "reject"
+
+	// truncatedAttr and originalLengthAttr are the attributes marking the
+	// log records whose body is truncated, with the original length in bytes
+	// of their body.
+	truncatedAttr      = "com.splunk.truncated"
+	originalLengthAttr = "com.splunk.original_length"
+)
+
+var errEventTooLarge = errors.New("event too large")
+
+// checkEventSize checks the size of the string event held by event is within
+// max_event_size. Larger events are truncated, marking them in their fields,
+// unless they are rejected, which is reported by returning false.
+func checkEventSize(event *splunk.Event, config *Config) bool {
+	s, ok := event.Event.(string)
+	if !ok || config.MaxEventSize == 0 || len(s) <= config.MaxEventSize {
+		return true
+	}
+	if config.OversizedEvents == oversizedEventsReject {
+		return false
+	}
+	event.Event = truncateString(s, config.MaxEventSize)
+	if event.Fields == nil {
+		event.Fields = map[string]interface{}{}
+	}
+	event.Fields[truncatedAttr] = true
+	event.Fields[originalLengthAttr] = int64(len(s))
+	return true
+}
+
+// setRawBody sets the body of a log record converted from a raw event,
+// truncating it to max_event_size, unless it is rejected with
+// errEventTooLarge.
+func setRawBody(logRecord plog.LogRecord, body string, config *Config) error {
+	if config.MaxEventSize > 0 && len(body) > config.MaxEventSize {
+		if config.OversizedEvents == oversizedEventsReject {
+			return errEventTooLarge
+		}
+		logRecord.Attributes().PutBool(truncatedAttr, true)
+		logRecord.Attributes().PutInt(originalLengthAttr, int64(len(body)))
+		body = truncateString(body, config.MaxEventSize)
+	}
+	logRecord.Body().SetStr(body)
+	return nil
+}
+
+// truncateString returns the longest prefix of s of at most maxSize bytes,
+// not splitting UTF-8 encoded characters.
+func truncateString(s string, maxSize int) string {
+	if len(s) <= maxSize {
+		return s
+	}
+	i := maxSize
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return s[:i]
+}
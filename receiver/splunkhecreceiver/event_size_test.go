@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		s       string
+		maxSize int
+		want    string
+	}{
+		{s: "hello", maxSize: 10, want: "hello"},
+		{s: "hello", maxSize: 5, want: "hello"},
+		{s: "hello", maxSize: 3, want: "hel"},
+		{s: "héllo", maxSize: 2, want: "h"},
+		{s: "héllo", maxSize: 3, want: "hé"},
+		{s: "日本", maxSize: 2, want: ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, truncateString(tt.s, tt.maxSize))
+	}
+}
+
+func Test_splunkhecReceiver_maxEventSize(t *testing.T) {
+	tests := []struct {
+		name            string
+		oversizedEvents string
+		path            string
+		body            string
+		status          int
+		respBody        string
+		bodies          []interface{}
+	}{
+		{
+			name:     "truncate",
+			path:     "/services/collector",
+			body:     `{"event":"short"}{"event":"much too long"}{"event":{"key":"structured events are not limited"}}`,
+			status:   http.StatusOK,
+			respBody: `{"text":"Success","code":0}`,
+			bodies:   []interface{}{"short", "much too", map[string]interface{}{"key": "structured events are not limited"}},
+		},
+		{
+			name:            "reject",
+			oversizedEvents: oversizedEventsReject,
+			path:            "/services/collector",
+			body:            `{"event":"short"}{"event":"much too long"}`,
+			status:          http.StatusBadRequest,
+			respBody:        `{"text":"Event is too large","code":113,"invalid-event-number":1}`,
+		},
+		{
+			name:     "raw_truncate",
+			path:     "/services/collector/raw",
+			body:     "short\nmuch too long\n",
+			status:   http.StatusOK,
+			respBody: `{"text":"Success","code":0}`,
+			bodies:   []interface{}{"short", "much too"},
+		},
+		{
+			name:            "raw_reject",
+			oversizedEvents: oversizedEventsReject,
+			path:            "/services/collector/raw",
+			body:            "short\nmuch too long\n",
+			status:          http.StatusBadRequest,
+			respBody:        `{"text":"Event is too large","code":113,"invalid-event-number":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.MaxEventSize = 8
+			config.OversizedEvents = tt.oversizedEvents
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "http://localhost"+tt.path, strings.NewReader(tt.body))
+			if strings.HasSuffix(tt.path, "/raw") {
+				r.handleRawReq(w, req)
+			} else {
+				r.handleReq(w, req)
+			}
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.respBody, w.Body.String())
+
+			var bodies []interface{}
+			for _, ld := range sink.AllLogs() {
+				records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+				for i := 0; i < records.Len(); i++ {
+					record := records.At(i)
+					bodies = append(bodies, record.Body().AsRaw())
+					truncated, ok := record.Attributes().Get(truncatedAttr)
+					if record.Body().Str() != "much too" {
+						assert.False(t, ok)
+						continue
+					}
+					require.True(t, ok)
+					assert.True(t, truncated.Bool())
+					originalLength, ok := record.Attributes().Get(originalLengthAttr)
+					require.True(t, ok)
+					assert.Equal(t, int64(len("much too long")), originalLength.Int())
+				}
+			}
+			assert.Equal(t, tt.bodies, bodies)
+		})
+	}
+}
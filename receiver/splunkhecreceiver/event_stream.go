@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import "io"
+
+// eventStreamReader reads the events of a request body as a stream of
+// concatenated JSON values, the format expected by Splunk. Bodies holding
+// JSON arrays of events, as sent by some clients, are unwrapped: the brackets
+// of the arrays and the commas separating their events are replaced by
+// spaces. Concatenated and newline delimited events are read as is.
+type eventStreamReader struct {
+	reader io.Reader
+	// depth is the nesting level within the current event.
+	depth int
+	// inArray is set within a top-level array of events.
+	inArray  bool
+	inString bool
+	escaped  bool
+}
+
+func newEventStreamReader(reader io.Reader) *eventStreamReader {
+	return &eventStreamReader{reader: reader}
+}
+
+func (r *eventStreamReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	for i, c := range p[:n] {
+		if r.inString {
+			switch {
+			case r.escaped:
+				r.escaped = false
+			case c == '\\':
+				r.escaped = true
+			case c == '"':
+				r.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			r.inString = true
+		case '{':
+			r.depth++
+		case '[':
+			if r.depth == 0 && !r.inArray {
+				r.inArray = true
+				p[i] = ' '
+			} else {
+				r.depth++
+			}
+		case '}':
+			r.depth--
+		case ']':
+			if r.depth == 0 && r.inArray {
+				r.inArray = false
+				p[i] = ' '
+			} else {
+				r.depth--
+			}
+		case ',':
+			if r.depth == 0 && r.inArray {
+				p[i] = ' '
+			}
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestEventStreamReader(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "concatenated",
+			body: `{"event":"a"}{"event":"b"}`,
+			want: `{"event":"a"}{"event":"b"}`,
+		},
+		{
+			name: "ndjson",
+			body: "{\"event\":\"a\"}\r\n{\"event\":\"b\"}\n",
+			want: "{\"event\":\"a\"}\r\n{\"event\":\"b\"}\n",
+		},
+		{
+			name: "array",
+			body: "[\n\t{\"event\":\"a\"},\n\t{\"event\":\"b\"}\n]",
+			want: " \n\t{\"event\":\"a\"} \n\t{\"event\":\"b\"}\n ",
+		},
+		{
+			name: "nested_values",
+			body: `[{"event":["a",{"b":[1,2]}],"fields":{"c":"d"}},{"event":"e"}]`,
+			want: ` {"event":["a",{"b":[1,2]}],"fields":{"c":"d"}} {"event":"e"} `,
+		},
+		{
+			name: "strings_with_delimiters",
+			body: `[{"event":"[a], {b} \"c,]\\"},{"event":"d"}]`,
+			want: ` {"event":"[a], {b} \"c,]\\"} {"event":"d"} `,
+		},
+		{
+			name: "several_arrays",
+			body: `[{"event":"a"}] [{"event":"b"}]{"event":"c"}`,
+			want: ` {"event":"a"}   {"event":"b"} {"event":"c"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := io.ReadAll(newEventStreamReader(strings.NewReader(tt.body)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+
+			// The state is kept across reads.
+			got, err = io.ReadAll(newEventStreamReader(iotest.OneByteReader(strings.NewReader(tt.body))))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_splunkhecReceiver_eventStreams(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantEvents []string
+	}{
+		{
+			name:       "concatenated",
+			body:       `{"event":"a"}{"event":"b"}`,
+			wantStatus: http.StatusOK,
+			wantEvents: []string{"a", "b"},
+		},
+		{
+			name:       "ndjson",
+			body:       "{\"event\":\"a\"}\n{\"event\":\"b\"}\n",
+			wantStatus: http.StatusOK,
+			wantEvents: []string{"a", "b"},
+		},
+		{
+			name:       "array",
+			body:       " [ {\"event\":\"a\"} ,\r\n {\"event\":\"b\"} ] ",
+			wantStatus: http.StatusOK,
+			wantEvents: []string{"a", "b"},
+		},
+		{
+			name:       "empty_array",
+			body:       ` [ ] `,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"text":"No data","code":5}`,
+		},
+		{
+			name:       "blank",
+			body:       " \r\n",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"text":"No data","code":5}`,
+		},
+		{
+			name:       "invalid_event_in_array",
+			body:       `[{"event":"a"},{"event":}]`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"text":"Invalid data format","code":6,"invalid-event-number":1}`,
+		},
+		{
+			name:       "commas_between_objects",
+			body:       `{"event":"a"},{"event":"b"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"text":"Invalid data format","code":6,"invalid-event-number":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			w := httptest.NewRecorder()
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(tt.body)))
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+				return
+			}
+			var events []string
+			for _, ld := range sink.AllLogs() {
+				records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+				for i := 0; i < records.Len(); i++ {
+					events = append(events, records.At(i).Body().Str())
+				}
+			}
+			assert.Equal(t, tt.wantEvents, events)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_hecEvent_toEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		json      string
+		want      splunk.Event
+		wantError bool
+	}{
+		{
+			name: "log_numbers",
+			json: `{"time":1.5,"event":{"id":9007199254740993,"ratio":0.5,"exp":1e3,"big":123456789012345678901234567890,"list":[1,2.5]},"fields":{"n":-42}}`,
+			want: splunk.Event{
+				Time: 1.5,
+				Event: map[string]interface{}{
+					"id":    int64(9007199254740993),
+					"ratio": 0.5,
+					"exp":   1000.0,
+					"big":   "123456789012345678901234567890",
+					"list":  []interface{}{int64(1), 2.5},
+				},
+				Fields: map[string]interface{}{"n": int64(-42)},
+			},
+		},
+		{
+			name: "log_number_body",
+			json: `{"time":"2","event":12}`,
+			want: splunk.Event{Time: 2, Event: int64(12)},
+		},
+		{
+			name: "metric_numbers",
+			json: `{"event":"metric","fields":{"metric_name:m":5,"dim":7}}`,
+			want: splunk.Event{
+				Event:  "metric",
+				Fields: map[string]interface{}{"metric_name:m": 5.0, "dim": 7.0},
+			},
+		},
+		{
+			name: "overflowing_float",
+			json: `{"event":1e400}`,
+			want: splunk.Event{Event: "1e400"},
+		},
+		{
+			name:      "invalid_time",
+			json:      `{"time":"soon","event":"foo"}`,
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hecMsg hecEvent
+			require.NoError(t, hecJSON.Unmarshal([]byte(tt.json), &hecMsg))
+			var event splunk.Event
+			err := hecMsg.toEvent(&event, newTimestampParser(TimestampConfig{}))
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, event)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_numbers(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	logsSink := new(consumertest.LogsSink)
+	logsRcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, logsSink)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	logsRcv.(*splunkReceiver).handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/event",
+		strings.NewReader(`{"event":{"id":9007199254740993},"fields":{"ratio":0.5}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 1, logsSink.LogRecordCount())
+	record := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	id, ok := record.Body().Map().Get("id")
+	require.True(t, ok)
+	assert.Equal(t, int64(9007199254740993), id.Int())
+	ratio, ok := record.Attributes().Get("ratio")
+	require.True(t, ok)
+	assert.Equal(t, 0.5, ratio.Double())
+
+	metricsSink := new(consumertest.MetricsSink)
+	metricsRcv, err := newMetricsReceiver(receivertest.NewNopCreateSettings(), *config, metricsSink)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	metricsRcv.(*splunkReceiver).handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/event",
+		strings.NewReader(`{"event":"metric","fields":{"metric_name:m":5}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 1, metricsSink.DataPointCount())
+	dp := metricsSink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+	assert.Equal(t, 5.0, dp.DoubleValue())
+}
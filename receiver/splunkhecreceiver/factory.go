@@ -5,7 +5,9 @@ package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
@@ -22,15 +24,45 @@ import (
 const (
 	// Default endpoints to bind to.
 	defaultEndpoint = ":8088"
+	// Default timeouts for reading the headers of requests and writing responses.
+	defaultServerTimeout = 20 * time.Second
+	// Default duration reverse DNS resolutions of hosts are cached for.
+	defaultReverseDNSCacheTTL = 5 * time.Minute
+	// Default interval the host lookup file is checked for changes at.
+	defaultHostLookupReloadInterval = time.Minute
+	// Default interval the tokens file is checked for changes at.
+	defaultTokensReloadInterval = time.Minute
+	// Default maximum size of the original event JSON preserved on log records.
+	defaultRawEventMaxSize = 64 * 1024
+	// Default size below which responses are not compressed.
+	defaultResponseCompressionMinSize = 1024
+	// Default instrumentation scope name of the produced logs.
+	defaultScopeName = "otelcol/splunkhecreceiver"
+	// Default header and resource attribute holding the tenant of a request.
+	defaultTenantHeader    = "X-Scope-OrgID"
+	defaultTenantAttribute = "tenant.id"
+	// Default resource attribute holding the route of events.
+	defaultRouteAttribute = "com.splunk.route"
+	// Default path of the indexer acknowledgment endpoint.
+	defaultAckPath = "/services/collector/ack"
+	// Default maximum number of ack IDs of a channel not queried yet.
+	defaultAckMaxPendingPerChannel = 100000
+	// Default time after which idle ack channels are forgotten, the maxIdleTime of Splunk HEC.
+	defaultAckMaxIdleTime = 10 * time.Minute
+	// Default maximum number of events remembered to suppress duplicates.
+	defaultDedupMaxEntries = 100000
 )
 
 // NewFactory creates a factory for Splunk HEC receiver.
 func NewFactory() receiver.Factory {
+	_ = view.Register(MetricViews()...)
+
 	return receiver.NewFactory(
 		metadata.Type,
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
-		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability))
 }
 
 // CreateDefaultConfig creates the default configuration for Splunk HEC receiver.
@@ -46,9 +78,50 @@ func createDefaultConfig() component.Config {
 			Index:      splunk.DefaultIndexLabel,
 			Host:       conventions.AttributeHostName,
 		},
-		RawPath:    splunk.DefaultRawPath,
-		HealthPath: splunk.DefaultHealthPath,
-		Splitting:  SplittingStrategyLine,
+		ReadHeaderTimeout: defaultServerTimeout,
+		WriteTimeout:      defaultServerTimeout,
+		RawPath:           splunk.DefaultRawPath,
+		HealthPath:        splunk.DefaultHealthPath,
+		Splitting:         SplittingStrategyLine,
+		Hostname: HostnameConfig{
+			ReverseDNS: ReverseDNSConfig{
+				CacheTTL: defaultReverseDNSCacheTTL,
+			},
+		},
+		HostLookup: HostLookupConfig{
+			ReloadInterval: defaultHostLookupReloadInterval,
+		},
+		RawEvent: RawEventConfig{
+			MaxSize: defaultRawEventMaxSize,
+		},
+		ResponseCompression: ResponseCompressionConfig{
+			MinSize: defaultResponseCompressionMinSize,
+		},
+		Scope: ScopeConfig{
+			Name: defaultScopeName,
+		},
+		Tenant: TenantConfig{
+			Header:            defaultTenantHeader,
+			ResourceAttribute: defaultTenantAttribute,
+		},
+		Routing: RoutingConfig{
+			Attribute: defaultRouteAttribute,
+		},
+		TokensFile: TokensFileConfig{
+			ReloadInterval: defaultTokensReloadInterval,
+		},
+		Profiling: ProfilingConfig{
+			SourceTypes:        []string{profilingScopeName},
+			ResourceAttributes: defaultProfilingResourceAttributes,
+		},
+		Ack: AckConfig{
+			Path:                     defaultAckPath,
+			MaxPendingAcksPerChannel: defaultAckMaxPendingPerChannel,
+			MaxIdleTime:              defaultAckMaxIdleTime,
+		},
+		Dedup: DedupConfig{
+			MaxEntries: defaultDedupMaxEntries,
+		},
 	}
 }
 
@@ -94,4 +167,25 @@ func createLogsReceiver(
 	return r, nil
 }
 
+// createTracesReceiver creates a traces receiver based on provided config.
+func createTracesReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Traces,
+) (receiver.Traces, error) {
+	var err error
+	var recv receiver.Traces
+	rCfg := cfg.(*Config)
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		recv, err = newTracesReceiver(params, *rCfg, consumer)
+		return recv
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*splunkReceiver).tracesConsumer = consumer
+	return r, nil
+}
+
 var receivers = sharedcomponent.NewSharedComponents()
@@ -35,6 +35,11 @@ func TestCreateReceiver(t *testing.T) {
 	mReceiver, err := createMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, mockMetricsConsumer)
 	assert.Nil(t, err, "receiver creation failed")
 	assert.NotNil(t, mReceiver, "receiver creation failed")
+
+	mockTracesConsumer := consumertest.NewNop()
+	tReceiver, err := createTracesReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, mockTracesConsumer)
+	assert.Nil(t, err, "receiver creation failed")
+	assert.Equal(t, lReceiver, tReceiver)
 }
 
 func TestFactoryType(t *testing.T) {
@@ -59,6 +64,15 @@ func TestCreateNilNextConsumerLogs(t *testing.T) {
 	assert.Nil(t, mReceiver, "receiver creation failed")
 }
 
+func TestCreateNilNextConsumerTraces(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:1"
+
+	tReceiver, err := newTracesReceiver(receivertest.NewNopCreateSettings(), *cfg, nil)
+	assert.EqualError(t, err, "nil tracesConsumer")
+	assert.Nil(t, tReceiver, "receiver creation failed")
+}
+
 func TestMultipleLogsReceivers(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Endpoint = "localhost:1"
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// headersCustomizer returns the customizer recording the headers of req
+// configured by headersToAttributes as resource attributes, or nil when req
+// has none of them. Headers sent several times are recorded as their values
+// joined with commas, like a single header.
+func headersCustomizer(headersToAttributes map[string]string, req *http.Request) func(resource pcommon.Resource) {
+	if len(headersToAttributes) == 0 {
+		return nil
+	}
+	headers := make([]string, 0, len(headersToAttributes))
+	for header := range headersToAttributes {
+		if len(req.Header.Values(header)) > 0 {
+			headers = append(headers, header)
+		}
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	// Headers are recorded in a stable order, the last one winning when
+	// several are mapped to the same attribute.
+	sort.Strings(headers)
+	return func(resource pcommon.Resource) {
+		for _, header := range headers {
+			resource.Attributes().PutStr(headersToAttributes[header], strings.Join(req.Header.Values(header), ", "))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestHeadersCustomizer(t *testing.T) {
+	tests := []struct {
+		name                string
+		headersToAttributes map[string]string
+		headers             http.Header
+		want                map[string]any
+	}{
+		{
+			name: "mapped_headers",
+			headersToAttributes: map[string]string{
+				"X-Forwarded-For": "client.address",
+				"x-tenant":        "tenant.id",
+				"X-Missing":       "missing",
+			},
+			headers: http.Header{
+				"X-Forwarded-For": {"10.0.0.1", "10.0.0.2"},
+				"X-Tenant":        {"acme"},
+				"X-Other":         {"other"},
+			},
+			want: map[string]any{
+				"client.address": "10.0.0.1, 10.0.0.2",
+				"tenant.id":      "acme",
+			},
+		},
+		{
+			name:                "same_attribute",
+			headersToAttributes: map[string]string{"X-Real-Ip": "client.address", "X-Forwarded-For": "client.address"},
+			headers:             http.Header{"X-Forwarded-For": {"10.0.0.1"}, "X-Real-Ip": {"10.0.0.2"}},
+			want:                map[string]any{"client.address": "10.0.0.2"},
+		},
+		{
+			name:                "no_mapped_header",
+			headersToAttributes: map[string]string{"X-Tenant": "tenant.id"},
+			headers:             http.Header{"X-Other": {"other"}},
+		},
+		{
+			name:    "disabled",
+			headers: http.Header{"X-Tenant": {"acme"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", nil)
+			req.Header = tt.headers
+			customize := headersCustomizer(tt.headersToAttributes, req)
+			if tt.want == nil {
+				assert.Nil(t, customize)
+				return
+			}
+			require.NotNil(t, customize)
+			resource := pcommon.NewResource()
+			customize(resource)
+			assert.Equal(t, tt.want, resource.Attributes().AsRaw())
+		})
+	}
+}
+
+func Test_splunkhecReceiver_headersToAttributes(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.HeadersToAttributes = map[string]string{"X-Tenant": "tenant.id"}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`))
+	req.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	r.handleReq(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "http://localhost/services/collector/raw", strings.NewReader("bar"))
+	req.Header.Set("X-Tenant", "acme")
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, sink.AllLogs(), 2)
+	for _, ld := range sink.AllLogs() {
+		tenant, ok := ld.ResourceLogs().At(0).Resource().Attributes().Get("tenant.id")
+		require.True(t, ok)
+		assert.Equal(t, "acme", tenant.Str())
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// healthStatus returns the status code and body answering health checks. The
+// receiver is unhealthy until it is started and once it is shutting down, as
+// well as while the next consumer of the pipeline refuses data, so that load
+// balancers and forwarders stop sending requests that would be rejected.
+func (r *splunkReceiver) healthStatus() (int, []byte) {
+	switch {
+	case !r.ready.Load():
+		return http.StatusServiceUnavailable, notReadyRespBody
+	case r.pipelineBlocked.Load():
+		return http.StatusServiceUnavailable, queuesFullRespBody
+	default:
+		return http.StatusOK, healthyRespBody
+	}
+}
+
+// recordConsumeResult tracks whether the next consumer accepts data. Permanent
+// errors are caused by the data itself, so they do not make the pipeline
+// unhealthy.
+func (r *splunkReceiver) recordConsumeResult(err error) {
+	blocked := err != nil && !consumererror.IsPermanent(err)
+	r.pipelineBlocked.Store(blocked)
+	if blocked {
+		r.startShedding()
+	}
+}
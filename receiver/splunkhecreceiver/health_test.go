@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func Test_splunkhecReceiver_healthReadiness(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	var consumeErr error
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error { return consumeErr })
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	assertHealth := func(path string, status int, body []byte) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.handleHealthReq(w, httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil))
+		resp := w.Result()
+		respBytes, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode)
+		assert.Equal(t, string(body), string(respBytes))
+	}
+	sendEvent := func() {
+		w := httptest.NewRecorder()
+		r.handleReq(w, httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`)))
+	}
+
+	assertHealth("/services/collector/health", http.StatusServiceUnavailable, notReadyRespBody)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assertHealth("/services/collector/health", http.StatusOK, healthyRespBody)
+	assertHealth("/services/collector/health/1.0", http.StatusOK, healthyRespBody)
+
+	consumeErr = errors.New("queue is full")
+	sendEvent()
+	assertHealth("/services/collector/health", http.StatusServiceUnavailable, queuesFullRespBody)
+	assertHealth("/services/collector/health/1.0", http.StatusServiceUnavailable, queuesFullRespBody)
+
+	consumeErr = nil
+	sendEvent()
+	assertHealth("/services/collector/health", http.StatusOK, healthyRespBody)
+
+	consumeErr = consumererror.NewPermanent(errors.New("bad data"))
+	sendEvent()
+	assertHealth("/services/collector/health", http.StatusOK, healthyRespBody)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assertHealth("/services/collector/health", http.StatusServiceUnavailable, notReadyRespBody)
+}
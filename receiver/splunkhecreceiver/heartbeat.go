@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+const (
+	heartbeatSource     = "otelcol"
+	heartbeatSourceType = "splunk_hec_receiver:heartbeat"
+	heartbeatBody       = "Splunk HEC receiver alive, zero events received"
+	// heartbeatAliveBody is the body of the heartbeats emitted while data is
+	// received, when emitting heartbeats periodically.
+	heartbeatAliveBody = "Splunk HEC receiver alive"
+	heartbeatIdleAttr  = "splunk.hec.idle_duration_seconds"
+)
+
+// markReceived records that data was just received, postponing the next heartbeat.
+func (r *splunkReceiver) markReceived() {
+	r.lastReceived.Store(time.Now().UnixNano())
+}
+
+// heartbeat emits a heartbeat log record every time the configured interval
+// elapses without any data being received, or every interval when periodic,
+// until ctx is cancelled.
+func (r *splunkReceiver) heartbeat(ctx context.Context) {
+	interval := r.config.Heartbeat.Interval
+	lastHeartbeat := time.Now()
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-timer.C:
+			idleSince := time.Unix(0, r.lastReceived.Load())
+			if idleSince.Before(lastHeartbeat) {
+				idleSince = lastHeartbeat
+			}
+			if idle := now.Sub(idleSince); idle < interval && !r.config.Heartbeat.Periodic {
+				timer.Reset(interval - idle)
+				continue
+			}
+			ld := newHeartbeatLogs(r.config, now, now.Sub(time.Unix(0, r.lastReceived.Load())))
+			err := r.logsConsumer.ConsumeLogs(ctx, ld)
+			r.recordConsumeResult(err)
+			if err != nil {
+				r.settings.Logger.Warn("Failed to emit heartbeat", zap.Error(err))
+			}
+			lastHeartbeat = now
+			timer.Reset(interval)
+		}
+	}
+}
+
+func newHeartbeatLogs(config *Config, now time.Time, idle time.Duration) plog.Logs {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknownhost"
+	}
+	sourceType := config.Heartbeat.SourceType
+	if sourceType == "" {
+		sourceType = heartbeatSourceType
+	}
+	body := heartbeatBody
+	if idle < config.Heartbeat.Interval {
+		body = heartbeatAliveBody
+	}
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	appendSplunkMetadata(rl, config.HecToOtelAttrs, host, heartbeatSource, sourceType, config.Heartbeat.Index)
+	sl := rl.ScopeLogs().AppendEmpty()
+	setScope(sl, config)
+	logRecord := sl.LogRecords().AppendEmpty()
+	logRecord.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+	logRecord.Body().SetStr(body)
+	logRecord.Attributes().PutDouble(heartbeatIdleAttr, idle.Seconds())
+	return ld
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func Test_splunkhecReceiver_heartbeat(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.Heartbeat.Interval = 10 * time.Millisecond
+	sink := new(consumertest.LogsSink)
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	sourcetype, ok := rl.Resource().Attributes().Get(config.HecToOtelAttrs.SourceType)
+	require.True(t, ok)
+	assert.Equal(t, heartbeatSourceType, sourcetype.Str())
+	assert.Equal(t, defaultScopeName, rl.ScopeLogs().At(0).Scope().Name())
+	lr := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, heartbeatBody, lr.Body().Str())
+	idle, ok := lr.Attributes().Get(heartbeatIdleAttr)
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, idle.Double(), config.Heartbeat.Interval.Seconds())
+}
+
+func Test_splunkhecReceiver_periodicHeartbeat(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.Heartbeat = HeartbeatConfig{
+		Interval:   10 * time.Millisecond,
+		Periodic:   true,
+		Index:      "_internal",
+		SourceType: "collector:heartbeat",
+	}
+	sink := new(consumertest.LogsSink)
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	// Data received continuously does not stop periodic heartbeats.
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.(*splunkReceiver).markReceived()
+			}
+		}
+	}()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	close(stop)
+	<-done
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	sourcetype, ok := rl.Resource().Attributes().Get(config.HecToOtelAttrs.SourceType)
+	require.True(t, ok)
+	assert.Equal(t, "collector:heartbeat", sourcetype.Str())
+	index, ok := rl.Resource().Attributes().Get(config.HecToOtelAttrs.Index)
+	require.True(t, ok)
+	assert.Equal(t, "_internal", index.Str())
+	assert.Equal(t, heartbeatAliveBody, rl.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func Test_splunkhecReceiver_heartbeatDisabled(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Nil(t, r.(*splunkReceiver).cancelHeartbeat)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// hostLookupHostColumn is the column of CSV lookup files holding the hosts.
+const hostLookupHostColumn = "host"
+
+var errMissingHostColumn = errors.New(`host lookup CSV file must have a "host" column`)
+
+// hostLookup enriches resources with the attributes of their host read from a
+// lookup file, which is reloaded when it changes.
+type hostLookup struct {
+	path          string
+	hostAttribute string
+	logger        *zap.Logger
+
+	mu         sync.RWMutex
+	modTime    time.Time
+	attributes map[string]map[string]string
+}
+
+func newHostLookup(config *Config, logger *zap.Logger) *hostLookup {
+	if config.HostLookup.Path == "" {
+		return nil
+	}
+	return &hostLookup{
+		path:          config.HostLookup.Path,
+		hostAttribute: config.HecToOtelAttrs.Host,
+		logger:        logger,
+	}
+}
+
+// load reads the lookup file if it changed since it was last read.
+func (h *hostLookup) load() error {
+	info, err := os.Stat(h.path)
+	if err != nil {
+		return err
+	}
+	h.mu.RLock()
+	unchanged := info.ModTime().Equal(h.modTime)
+	h.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	file, err := os.Open(h.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var attributes map[string]map[string]string
+	if strings.EqualFold(filepath.Ext(h.path), ".csv") {
+		attributes, err = readHostLookupCSV(file)
+	} else {
+		err = json.NewDecoder(file).Decode(&attributes)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read host lookup file %q: %w", h.path, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.modTime = info.ModTime()
+	h.attributes = attributes
+	return nil
+}
+
+// reload reloads the lookup file every interval until ctx is done. The
+// previous attributes are kept when the file cannot be read.
+func (h *hostLookup) reload(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.load(); err != nil {
+				h.logger.Warn("Failed to reload the host lookup file, keeping the previous one", zap.Error(err))
+			}
+		}
+	}
+}
+
+// enrich sets the attributes of the host of resource on it. Attributes
+// already set on resource are kept.
+func (h *hostLookup) enrich(resource pcommon.Resource) {
+	host, ok := resource.Attributes().Get(h.hostAttribute)
+	if !ok {
+		return
+	}
+	h.mu.RLock()
+	attributes := h.attributes[host.AsString()]
+	h.mu.RUnlock()
+	for k, v := range attributes {
+		if _, exists := resource.Attributes().Get(k); !exists {
+			resource.Attributes().PutStr(k, v)
+		}
+	}
+}
+
+// readHostLookupCSV reads a CSV lookup file whose header names the host
+// column and the attributes set by the other columns.
+func readHostLookupCSV(reader io.Reader) (map[string]map[string]string, error) {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errMissingHostColumn
+	}
+	header := records[0]
+	hostColumn := -1
+	for i, name := range header {
+		if name == hostLookupHostColumn {
+			hostColumn = i
+		}
+	}
+	if hostColumn < 0 {
+		return nil, errMissingHostColumn
+	}
+	attributes := make(map[string]map[string]string, len(records)-1)
+	for _, record := range records[1:] {
+		hostAttributes := make(map[string]string, len(header)-1)
+		for i, value := range record {
+			if i != hostColumn && value != "" {
+				hostAttributes[header[i]] = value
+			}
+		}
+		attributes[record[hostColumn]] = hostAttributes
+	}
+	return attributes, nil
+}
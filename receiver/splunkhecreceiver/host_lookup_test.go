@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+)
+
+func Test_hostLookup_load(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "csv",
+			file:    "hosts.csv",
+			content: "cloud.provider,host,cloud.region\naws,web01,us-east-1\ngcp,db01,\n",
+		},
+		{
+			name:    "json",
+			file:    "hosts.json",
+			content: `{"web01":{"cloud.provider":"aws","cloud.region":"us-east-1"},"db01":{"cloud.provider":"gcp"}}`,
+		},
+		{
+			name:    "csv_without_host_column",
+			file:    "hosts.csv",
+			content: "name,cloud.provider\nweb01,aws\n",
+			wantErr: `host lookup CSV file must have a "host" column`,
+		},
+		{
+			name:    "invalid_json",
+			file:    "hosts.json",
+			content: `["web01"]`,
+			wantErr: "failed to read host lookup file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+			config := createDefaultConfig().(*Config)
+			config.HostLookup.Path = path
+			lookup := newHostLookup(config, zap.NewNop())
+
+			err := lookup.load()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]map[string]string{
+				"web01": {"cloud.provider": "aws", "cloud.region": "us-east-1"},
+				"db01":  {"cloud.provider": "gcp"},
+			}, lookup.attributes)
+
+			resource := pcommon.NewResource()
+			resource.Attributes().PutStr("host.name", "web01")
+			resource.Attributes().PutStr("cloud.region", "eu-west-1")
+			lookup.enrich(resource)
+			assert.Equal(t, map[string]interface{}{
+				"host.name":      "web01",
+				"cloud.provider": "aws",
+				"cloud.region":   "eu-west-1",
+			}, resource.Attributes().AsRaw())
+		})
+	}
+}
+
+func Test_splunkhecReceiver_hostLookupReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"web01":{"host.id":"i-1"}}`), 0600))
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0"
+	config.HostLookup = HostLookupConfig{Path: path, ReloadInterval: 10 * time.Millisecond}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	hostID := func() interface{} {
+		sink.Reset()
+		w := httptest.NewRecorder()
+		r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo","host":"web01"}`)))
+		require.Equal(t, http.StatusOK, w.Code)
+		return sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().AsRaw()["host.id"]
+	}
+	assert.Equal(t, "i-1", hostID())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"web01":{"host.id":"i-2"}}`), 0600))
+	// Ensure the modification time changes on file systems with coarse timestamps.
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	assert.Eventually(t, func() bool { return hostID() == "i-2" }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCachedHosts bounds the number of reverse DNS resolutions kept in cache.
+const maxCachedHosts = 10000
+
+// hostNormalizer normalizes the host of events according to the hostname configuration.
+type hostNormalizer struct {
+	config     HostnameConfig
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+	now        func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]resolvedHost
+}
+
+type resolvedHost struct {
+	name    string
+	expires time.Time
+}
+
+func newHostNormalizer(config *Config) *hostNormalizer {
+	hostname := config.Hostname
+	if !hostname.Lowercase && !hostname.StripDomain && !hostname.ReverseDNS.Enabled {
+		return nil
+	}
+	return &hostNormalizer{
+		config:     hostname,
+		lookupAddr: net.DefaultResolver.LookupAddr,
+		now:        time.Now,
+		cache:      map[string]resolvedHost{},
+	}
+}
+
+// normalize returns host resolved to its name if it is an IP address, with
+// its domain stripped and lower cased, as configured.
+func (n *hostNormalizer) normalize(ctx context.Context, host string) string {
+	if host == "" {
+		return host
+	}
+	isIP := net.ParseIP(host) != nil
+	if isIP && n.config.ReverseDNS.Enabled {
+		host = n.resolve(ctx, host)
+		isIP = net.ParseIP(host) != nil
+	}
+	if !isIP && n.config.StripDomain {
+		if i := strings.IndexByte(host, '.'); i > 0 {
+			host = host[:i]
+		}
+	}
+	if n.config.Lowercase {
+		host = strings.ToLower(host)
+	}
+	return host
+}
+
+// resolve returns the name ip resolves to, or ip if it does not resolve.
+// Resolutions, failed ones included, are cached for the configured duration.
+func (n *hostNormalizer) resolve(ctx context.Context, ip string) string {
+	now := n.now()
+	n.mu.Lock()
+	cached, ok := n.cache[ip]
+	n.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.name
+	}
+
+	name := ip
+	if names, err := n.lookupAddr(ctx, ip); err == nil && len(names) > 0 {
+		name = strings.TrimSuffix(names[0], ".")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.cache) >= maxCachedHosts {
+		for key, entry := range n.cache {
+			if !now.Before(entry.expires) {
+				delete(n.cache, key)
+			}
+		}
+		if len(n.cache) >= maxCachedHosts {
+			n.cache = map[string]resolvedHost{}
+		}
+	}
+	n.cache[ip] = resolvedHost{name: name, expires: now.Add(n.config.ReverseDNS.CacheTTL)}
+	return name
+}
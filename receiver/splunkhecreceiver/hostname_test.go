@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+func Test_hostNormalizer(t *testing.T) {
+	lookupAddr := func(_ context.Context, addr string) ([]string, error) {
+		if addr == "10.0.0.1" {
+			return []string{"HOST01.corp.example.com."}, nil
+		}
+		return nil, errors.New("not found")
+	}
+	tests := []struct {
+		name   string
+		config HostnameConfig
+		host   string
+		want   string
+	}{
+		{
+			name:   "lowercase",
+			config: HostnameConfig{Lowercase: true},
+			host:   "HOST01.Corp.example.com",
+			want:   "host01.corp.example.com",
+		},
+		{
+			name:   "strip_domain",
+			config: HostnameConfig{StripDomain: true},
+			host:   "HOST01.corp.example.com",
+			want:   "HOST01",
+		},
+		{
+			name:   "strip_domain_keeps_ip",
+			config: HostnameConfig{StripDomain: true},
+			host:   "10.0.0.2",
+			want:   "10.0.0.2",
+		},
+		{
+			name:   "reverse_dns",
+			config: HostnameConfig{ReverseDNS: ReverseDNSConfig{Enabled: true, CacheTTL: time.Minute}},
+			host:   "10.0.0.1",
+			want:   "HOST01.corp.example.com",
+		},
+		{
+			name:   "reverse_dns_unresolved",
+			config: HostnameConfig{StripDomain: true, ReverseDNS: ReverseDNSConfig{Enabled: true, CacheTTL: time.Minute}},
+			host:   "10.0.0.2",
+			want:   "10.0.0.2",
+		},
+		{
+			name: "all",
+			config: HostnameConfig{
+				Lowercase:   true,
+				StripDomain: true,
+				ReverseDNS:  ReverseDNSConfig{Enabled: true, CacheTTL: time.Minute},
+			},
+			host: "10.0.0.1",
+			want: "host01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newHostNormalizer(&Config{Hostname: tt.config})
+			require.NotNil(t, n)
+			n.lookupAddr = lookupAddr
+			assert.Equal(t, tt.want, n.normalize(context.Background(), tt.host))
+		})
+	}
+
+	assert.Nil(t, newHostNormalizer(&Config{}))
+}
+
+func Test_hostNormalizer_cache(t *testing.T) {
+	n := newHostNormalizer(&Config{Hostname: HostnameConfig{ReverseDNS: ReverseDNSConfig{Enabled: true, CacheTTL: time.Minute}}})
+	now := time.Now()
+	n.now = func() time.Time { return now }
+	lookups := 0
+	n.lookupAddr = func(context.Context, string) ([]string, error) {
+		lookups++
+		return []string{"host01"}, nil
+	}
+
+	assert.Equal(t, "host01", n.normalize(context.Background(), "10.0.0.1"))
+	assert.Equal(t, "host01", n.normalize(context.Background(), "10.0.0.1"))
+	assert.Equal(t, 1, lookups)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, "host01", n.normalize(context.Background(), "10.0.0.1"))
+	assert.Equal(t, 2, lookups)
+}
+
+func Test_splunkhecReceiver_hostname(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Hostname = HostnameConfig{Lowercase: true, StripDomain: true}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	body := `{"event":"first","host":"HOST01"}{"event":"second","host":"host01.corp.example.com"}`
+	w := httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:0/services/collector/event", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:0/services/collector/raw?host=Host01.corp.example.com", strings.NewReader("third")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, sink.AllLogs(), 2)
+	for _, ld := range sink.AllLogs() {
+		require.Equal(t, 1, ld.ResourceLogs().Len())
+		hostName, ok := ld.ResourceLogs().At(0).Resource().Attributes().Get(conventions.AttributeHostName)
+		require.True(t, ok)
+		assert.Equal(t, "host01", hostName.Str())
+	}
+	assert.Equal(t, 3, sink.LogRecordCount())
+}
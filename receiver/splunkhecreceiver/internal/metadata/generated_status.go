@@ -8,6 +8,7 @@ import (
 
 const (
 	Type             = "splunk_hec"
+	TracesStability  = component.StabilityLevelAlpha
 	MetricsStability = component.StabilityLevelBeta
 	LogsStability    = component.StabilityLevelBeta
 )
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handling of the invalid events of requests.
+const (
+	invalidEventsReject = "reject"
+	invalidEventsSkip   = "skip"
+)
+
+type skippedEventsKey struct{}
+
+// skippedEvents tracks the invalid events of a request skipped while its
+// valid events are passed to the next consumer.
+type skippedEvents struct {
+	// respBody reports the first skipped event.
+	respBody []byte
+	// numbers are the positions of the skipped events in the request.
+	numbers []int
+}
+
+// withSkippedEvents returns req tracking its skipped events in skipped.
+func withSkippedEvents(req *http.Request, skipped *skippedEvents) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), skippedEventsKey{}, skipped))
+}
+
+// skippedEventNumbers returns the positions of the invalid events of req
+// skipped while its valid events were passed to the next consumer.
+func skippedEventNumbers(req *http.Request) []int {
+	if skipped, _ := req.Context().Value(skippedEventsKey{}).(*skippedEvents); skipped != nil {
+		return skipped.numbers
+	}
+	return nil
+}
+
+// writeSuccess answers a request whose data was accepted by the next consumer.
+func (r *splunkReceiver) writeSuccess(resp http.ResponseWriter, req *http.Request) error {
+	resp.WriteHeader(http.StatusOK)
+	_, err := resp.Write(r.successRespBody(req))
+	return err
+}
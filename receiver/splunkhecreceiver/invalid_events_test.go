@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_invalidEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		invalidEvents string
+		rawEvent      bool
+		body          string
+		status        int
+		respBody      string
+		bodies        []string
+	}{
+		{
+			name:          "reject",
+			invalidEvents: invalidEventsReject,
+			body:          `{"event":"foo"}{"event":""}{"event":"bar"}`,
+			status:        http.StatusBadRequest,
+			respBody:      `{"text":"Event field cannot be blank","code":13,"invalid-event-number":1}`,
+		},
+		{
+			name:          "skip",
+			invalidEvents: invalidEventsSkip,
+			body:          `{"event":"foo"}{"event":""}{"event":"bar","host":{}}{"event":"baz","fields":{"nested":{"a":1}}}{"event":"qux"}`,
+			status:        http.StatusOK,
+			respBody:      `{"text":"Success","code":0,"skipped-event-numbers":[1,2,3]}`,
+			bodies:        []string{"foo", "qux"},
+		},
+		{
+			name:          "skip_raw_event",
+			invalidEvents: invalidEventsSkip,
+			rawEvent:      true,
+			body:          `{"event":"foo"}{"time":1}{"event":"bar"}`,
+			status:        http.StatusOK,
+			respBody:      `{"text":"Success","code":0,"skipped-event-numbers":[1]}`,
+			bodies:        []string{"foo", "bar"},
+		},
+		{
+			name:          "skip_valid",
+			invalidEvents: invalidEventsSkip,
+			body:          `{"event":"foo"}{"event":"bar"}`,
+			status:        http.StatusOK,
+			respBody:      `{"text":"Success","code":0}`,
+			bodies:        []string{"foo", "bar"},
+		},
+		{
+			name:          "skip_all_invalid",
+			invalidEvents: invalidEventsSkip,
+			body:          `{"event":""}{"time":1}`,
+			status:        http.StatusBadRequest,
+			respBody:      `{"text":"Event field cannot be blank","code":13,"invalid-event-number":0}`,
+		},
+		{
+			name:          "skip_malformed_json",
+			invalidEvents: invalidEventsSkip,
+			body:          `{"event":"foo"}{"event":`,
+			status:        http.StatusBadRequest,
+			respBody:      `{"text":"Invalid data format","code":6,"invalid-event-number":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.InvalidEvents = tt.invalidEvents
+			config.RawEvent.Enabled = tt.rawEvent
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			rcv.(*splunkReceiver).handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.respBody, w.Body.String())
+
+			var bodies []string
+			for _, ld := range sink.AllLogs() {
+				records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+				for i := 0; i < records.Len(); i++ {
+					bodies = append(bodies, records.At(i).Body().Str())
+					if tt.rawEvent {
+						raw, ok := records.At(i).Attributes().Get(rawEventAttr)
+						require.True(t, ok)
+						assert.Contains(t, raw.Str(), records.At(i).Body().Str())
+					}
+				}
+			}
+			assert.Equal(t, tt.bodies, bodies)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_skippedEventsAckDedup(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.InvalidEvents = invalidEventsSkip
+	config.Ack.Enabled = true
+	config.Dedup.TTL = time.Minute
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(body string) (int, string) {
+		req := httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body))
+		req.Header.Set(channelHeader, "ch")
+		w := httptest.NewRecorder()
+		r.handleReq(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	// Requests with skipped events are acknowledged, and their ingested events remembered.
+	status, body := send(`{"event":"foo","time":1}{"event":""}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"text":"Success","code":0,"ackId":0,"skipped-event-numbers":[1]}`, body)
+	assert.Equal(t, 1, sink.LogRecordCount())
+
+	status, body = send(`{"event":"foo","time":1}{"event":""}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"text":"Success","code":0,"ackId":1,"skipped-event-numbers":[1]}`, body)
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+)
+
+// newLineBreaker returns the regular expression breaking raw requests into
+// events, or nil when line_breaker is not set.
+func newLineBreaker(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	lineBreaker, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid line_breaker: %w", err)
+	}
+	if lineBreaker.NumSubexp() == 0 {
+		return nil, fmt.Errorf("line_breaker %q must have a capturing group", pattern)
+	}
+	return lineBreaker, nil
+}
+
+// splitEvents returns a bufio.SplitFunc breaking events where lineBreaker
+// matches, like the Splunk LINE_BREAKER setting: the text matched by its first
+// capturing group is discarded, the text matched before and after the group
+// belonging to the previous and next event. The trailing newlines of the last
+// event are removed, and empty events are skipped.
+func splitEvents(lineBreaker *regexp.Regexp) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		for offset := 0; offset < len(data); offset++ {
+			loc := lineBreaker.FindSubmatchIndex(data[offset:])
+			// A match reaching the end of the data read so far could go
+			// further once more data is read.
+			if loc == nil || (!atEOF && offset+loc[1] == len(data)) {
+				break
+			}
+			start, end := loc[2], loc[3]
+			if start < 0 {
+				start, end = loc[0], loc[1]
+			}
+			start += offset
+			end += offset
+			if end == 0 {
+				// Empty match at the start of the data.
+				continue
+			}
+			if start == 0 {
+				return end, nil, nil
+			}
+			return end, data[:start], nil
+		}
+		if atEOF && len(data) > 0 {
+			if event := bytes.TrimRight(data, "\r\n"); len(event) > 0 {
+				return len(data), event, nil
+			}
+			return len(data), nil, nil
+		}
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestNewLineBreaker(t *testing.T) {
+	lineBreaker, err := newLineBreaker("")
+	require.NoError(t, err)
+	assert.Nil(t, lineBreaker)
+
+	_, err = newLineBreaker(`([\r\n]+`)
+	assert.ErrorContains(t, err, "invalid line_breaker")
+
+	_, err = newLineBreaker(`[\r\n]+`)
+	assert.EqualError(t, err, `line_breaker "[\\r\\n]+" must have a capturing group`)
+}
+
+func TestSplitEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		lineBreaker string
+		data        string
+		want        []string
+	}{
+		{
+			name:        "newlines",
+			lineBreaker: `([\r\n]+)`,
+			data:        "\r\nfoo\r\n\r\nbar\n",
+			want:        []string{"foo", "bar"},
+		},
+		{
+			name:        "stack_trace",
+			lineBreaker: `([\r\n]+)\d{4}-\d{2}-\d{2}`,
+			data:        "2023-01-01 ERROR failed\njava.lang.Exception\n\tat Main.main\n2023-01-02 INFO done",
+			want:        []string{"2023-01-01 ERROR failed\njava.lang.Exception\n\tat Main.main", "2023-01-02 INFO done"},
+		},
+		{
+			name:        "xml_documents",
+			lineBreaker: `</doc>()`,
+			data:        "<doc>\n  <a>1</a>\n</doc><doc>\n  <a>2</a>\n</doc>",
+			want:        []string{"<doc>\n  <a>1</a>\n</doc>", "<doc>\n  <a>2</a>\n</doc>"},
+		},
+		{
+			name:        "no_match",
+			lineBreaker: `(;)`,
+			data:        "foo\nbar\n\n",
+			want:        []string{"foo\nbar"},
+		},
+		{
+			name:        "empty_match",
+			lineBreaker: `(;*)`,
+			data:        "foo;bar",
+			want:        []string{"f", "o", "o", "b", "a", "r"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lineBreaker, err := newLineBreaker(tt.lineBreaker)
+			require.NoError(t, err)
+			// Reading one byte at a time checks events are not broken
+			// before a match is complete.
+			sc := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(tt.data)))
+			sc.Split(splitEvents(lineBreaker))
+			var events []string
+			for sc.Scan() {
+				events = append(events, sc.Text())
+			}
+			require.NoError(t, sc.Err())
+			assert.Equal(t, tt.want, events)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_lineBreaker(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.LineBreaker = `([\r\n]+)\d{4}-\d{2}-\d{2}`
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	body := "2023-01-01 ERROR failed\njava.lang.Exception\n\tat Main.main\n2023-01-02 INFO done\n"
+	rcv.(*splunkReceiver).handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 2, sink.LogRecordCount())
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, "2023-01-01 ERROR failed\njava.lang.Exception\n\tat Main.main", records.At(0).Body().Str())
+	assert.Equal(t, "2023-01-02 INFO done", records.At(1).Body().Str())
+}
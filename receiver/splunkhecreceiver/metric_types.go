@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+const (
+	metricTypeGauge      = "gauge"
+	metricTypeCumulative = "cumulative"
+	metricTypeDelta      = "delta"
+
+	// cumulativeSeriesTTL is the time after which the series of cumulative
+	// sums no point of which was received are forgotten.
+	cumulativeSeriesTTL = time.Hour
+)
+
+// metricTypeRules declares the type of the metrics of metric events, which
+// are otherwise converted to gauges.
+type metricTypeRules []metricTypeRule
+
+type metricTypeRule struct {
+	sourceType string
+	metricName *regexp.Regexp
+	metricType string
+}
+
+// newMetricTypeRules returns the rules configured by config, or nil when no
+// rule is configured.
+func newMetricTypeRules(config *Config) (metricTypeRules, error) {
+	if len(config.Metrics.Types) == 0 {
+		return nil, nil
+	}
+	rules := make(metricTypeRules, 0, len(config.Metrics.Types))
+	for i, rule := range config.Metrics.Types {
+		switch rule.Type {
+		case metricTypeGauge, metricTypeCumulative, metricTypeDelta:
+		default:
+			return nil, fmt.Errorf("metrics type %q of rule %d must be one of gauge, cumulative or delta", rule.Type, i)
+		}
+		var metricName *regexp.Regexp
+		if rule.MetricNamePattern != "" {
+			var err error
+			if metricName, err = regexp.Compile(rule.MetricNamePattern); err != nil {
+				return nil, fmt.Errorf("invalid metrics metric_name_pattern of rule %d: %w", i, err)
+			}
+		}
+		rules = append(rules, metricTypeRule{sourceType: rule.SourceType, metricName: metricName, metricType: rule.Type})
+	}
+	return rules, nil
+}
+
+// typeOf returns the type of the metric named metricName sent with
+// sourceType, set by the first matching rule. Metrics are gauges by default.
+func (m metricTypeRules) typeOf(sourceType string, metricName string) string {
+	for _, rule := range m {
+		if rule.sourceType != "" && rule.sourceType != sourceType {
+			continue
+		}
+		if rule.metricName != nil && !rule.metricName.MatchString(metricName) {
+			continue
+		}
+		return rule.metricType
+	}
+	return metricTypeGauge
+}
+
+// setEmptyNumberDataPoints sets metric to an empty metric of metricType,
+// returning its data points.
+func setEmptyNumberDataPoints(metric pmetric.Metric, metricType string) pmetric.NumberDataPointSlice {
+	if metricType == metricTypeGauge {
+		return metric.SetEmptyGauge().DataPoints()
+	}
+	sum := metric.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	if metricType == metricTypeDelta {
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	} else {
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+	return sum.DataPoints()
+}
+
+// cumulativeStarts tracks the start time of the series of cumulative sums, as
+// HEC metric events carry none: the time of their first point, or of their
+// first point after a reset, their value decreasing.
+type cumulativeStarts struct {
+	mu        sync.Mutex
+	series    map[cumulativeSeriesKey]*cumulativeSeries
+	lastSweep time.Time
+}
+
+type cumulativeSeriesKey struct {
+	resource   [16]byte
+	name       string
+	attributes [16]byte
+}
+
+type cumulativeSeries struct {
+	start pcommon.Timestamp
+	value float64
+	// seen is when a point of the series was last received.
+	seen time.Time
+}
+
+// newCumulativeStarts returns the start times of the cumulative sums declared
+// by rules, or nil when no rule declares any.
+func newCumulativeStarts(rules metricTypeRules) *cumulativeStarts {
+	for _, rule := range rules {
+		if rule.metricType == metricTypeCumulative {
+			return &cumulativeStarts{series: make(map[cumulativeSeriesKey]*cumulativeSeries)}
+		}
+	}
+	return nil
+}
+
+// set sets the start time of the points of the cumulative sums of md,
+// received at now, and forgets the series not received for a while.
+func (c *cumulativeStarts) set(md pmetric.Metrics, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resource := pdatautil.MapHash(rm.Resource().Attributes())
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.Type() != pmetric.MetricTypeSum || metric.Sum().AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+					continue
+				}
+				points := metric.Sum().DataPoints()
+				for l := 0; l < points.Len(); l++ {
+					point := points.At(l)
+					value := point.DoubleValue()
+					if point.ValueType() == pmetric.NumberDataPointValueTypeInt {
+						value = float64(point.IntValue())
+					}
+					key := cumulativeSeriesKey{resource: resource, name: metric.Name(), attributes: pdatautil.MapHash(point.Attributes())}
+					series, ok := c.series[key]
+					if !ok || value < series.value {
+						series = &cumulativeSeries{start: point.Timestamp()}
+						c.series[key] = series
+					}
+					series.value = value
+					series.seen = now
+					point.SetStartTimestamp(series.start)
+				}
+			}
+		}
+	}
+	if now.Sub(c.lastSweep) < cumulativeSeriesTTL {
+		return
+	}
+	c.lastSweep = now
+	for key, series := range c.series {
+		if now.Sub(series.seen) >= cumulativeSeriesTTL {
+			delete(c.series, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// multilineRule merges the events of a sourcetype that do not start a new
+// event into the preceding one.
+type multilineRule struct {
+	lineStart *regexp.Regexp
+	maxLines  int
+}
+
+func newMultilineRules(cfg map[string]MultilineConfig) (map[string]*multilineRule, error) {
+	rules := make(map[string]*multilineRule, len(cfg))
+	for sourceType, ruleCfg := range cfg {
+		lineStart, err := regexp.Compile(ruleCfg.LineStartPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiline line_start_pattern for sourcetype %q: %w", sourceType, err)
+		}
+		rules[sourceType] = &multilineRule{lineStart: lineStart, maxLines: ruleCfg.MaxLines}
+	}
+	return rules, nil
+}
+
+// continues returns whether line is to be appended to an event already made of lines lines.
+func (m *multilineRule) continues(line string, lines int) bool {
+	return !m.lineStart.MatchString(line) && (m.maxLines <= 0 || lines < m.maxLines)
+}
+
+// lineMerger joins, as they are added, the lines continuing a previous line
+// according to its rule.
+type lineMerger struct {
+	rule    *multilineRule
+	pending []string
+}
+
+// add adds line, returning the previous merged line if line does not continue it.
+func (m *lineMerger) add(line string) (string, bool) {
+	if m.rule == nil {
+		return line, true
+	}
+	if len(m.pending) > 0 && m.rule.continues(line, len(m.pending)) {
+		m.pending = append(m.pending, line)
+		return "", false
+	}
+	merged, ok := m.flush()
+	m.pending = append(m.pending, line)
+	return merged, ok
+}
+
+// flush returns the line being merged, if any.
+func (m *lineMerger) flush() (string, bool) {
+	if len(m.pending) == 0 {
+		return "", false
+	}
+	merged := strings.Join(m.pending, "\n")
+	m.pending = m.pending[:0]
+	return merged, true
+}
+
+// mergeMultilineEvents merges the string events of a request continuing the
+// preceding event of the same host, source, sourcetype and index according to
+// the rule of their sourcetype. No event is held back for the next request. The original JSON of merged events, if preserved, is merged as
+// well. The fields and time of the first event are kept.
+func mergeMultilineEvents(rules map[string]*multilineRule, events []*splunk.Event, rawEvents [][]byte) ([]*splunk.Event, [][]byte) {
+	if len(rules) == 0 {
+		return events, rawEvents
+	}
+	merged := events[:0]
+	var mergedRaw [][]byte
+	if rawEvents != nil {
+		mergedRaw = rawEvents[:0]
+	}
+	count := 0
+	for i, event := range events {
+		line, isString := event.Event.(string)
+		if len(merged) > 0 && isString {
+			prev := merged[len(merged)-1]
+			prevLine, prevIsString := prev.Event.(string)
+			rule := rules[event.SourceType]
+			if rule != nil && prevIsString && sameMetadata(prev, event) && rule.continues(line, count) {
+				prev.Event = prevLine + "\n" + line
+				if mergedRaw != nil {
+					mergedRaw[len(mergedRaw)-1] = append(append(mergedRaw[len(mergedRaw)-1], '\n'), rawEvents[i]...)
+				}
+				count += strings.Count(line, "\n") + 1
+				continue
+			}
+		}
+		merged = append(merged, event)
+		if mergedRaw != nil {
+			mergedRaw = append(mergedRaw, rawEvents[i])
+		}
+		count = 1
+		if isString {
+			count += strings.Count(line, "\n")
+		}
+	}
+	return merged, mergedRaw
+}
+
+func sameMetadata(a, b *splunk.Event) bool {
+	return a.Host == b.Host && a.Source == b.Source && a.SourceType == b.SourceType && a.Index == b.Index
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_lineMerger(t *testing.T) {
+	rules, err := newMultilineRules(map[string]MultilineConfig{
+		"java":    {LineStartPattern: `^\d{4}-\d{2}-\d{2}`},
+		"limited": {LineStartPattern: `^\S`, MaxLines: 2},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		rule  *multilineRule
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "no_rule",
+			lines: []string{"a", " b"},
+			want:  []string{"a", " b"},
+		},
+		{
+			name:  "stack_trace",
+			rule:  rules["java"],
+			lines: []string{"2023-01-01 error", "  at foo", "  at bar", "2023-01-02 info"},
+			want:  []string{"2023-01-01 error\n  at foo\n  at bar", "2023-01-02 info"},
+		},
+		{
+			name:  "leading_continuation",
+			rule:  rules["java"],
+			lines: []string{"  at foo", "2023-01-02 info"},
+			want:  []string{"  at foo", "2023-01-02 info"},
+		},
+		{
+			name:  "max_lines",
+			rule:  rules["limited"],
+			lines: []string{"a", " b", " c", " d", "e"},
+			want:  []string{"a\n b", " c\n d", "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merger := lineMerger{rule: tt.rule}
+			var got []string
+			for _, line := range tt.lines {
+				if merged, ok := merger.add(line); ok {
+					got = append(got, merged)
+				}
+			}
+			if merged, ok := merger.flush(); ok {
+				got = append(got, merged)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_mergeMultilineEvents(t *testing.T) {
+	rules, err := newMultilineRules(map[string]MultilineConfig{
+		"java": {LineStartPattern: `^\d{4}-\d{2}-\d{2}`},
+	})
+	require.NoError(t, err)
+
+	events := []*splunk.Event{
+		{SourceType: "java", Host: "a", Event: "2023-01-01 error"},
+		{SourceType: "java", Host: "a", Event: "  at foo"},
+		{SourceType: "java", Host: "b", Event: "  at bar"},
+		{SourceType: "other", Host: "b", Event: "  at baz"},
+		{SourceType: "java", Host: "a", Event: map[string]interface{}{"foo": "bar"}},
+		{SourceType: "java", Host: "a", Event: "  at qux"},
+	}
+	rawEvents := [][]byte{
+		[]byte(`"2023-01-01 error"`),
+		[]byte(`"  at foo"`),
+		[]byte(`"  at bar"`),
+		[]byte(`"  at baz"`),
+		[]byte(`{"foo":"bar"}`),
+		[]byte(`"  at qux"`),
+	}
+
+	merged, mergedRaw := mergeMultilineEvents(rules, events, rawEvents)
+	require.Len(t, merged, 5)
+	assert.Equal(t, "2023-01-01 error\n  at foo", merged[0].Event)
+	assert.Equal(t, "  at bar", merged[1].Event)
+	assert.Equal(t, "  at baz", merged[2].Event)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, merged[3].Event)
+	assert.Equal(t, "  at qux", merged[4].Event)
+	require.Len(t, mergedRaw, 5)
+	assert.Equal(t, "\"2023-01-01 error\"\n\"  at foo\"", string(mergedRaw[0]))
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	outcomeAccepted = "accepted"
+	outcomeRefused  = "refused"
+	outcomeDropped  = "dropped"
+	// outcomeDuplicate is the outcome of the events suppressed as duplicates.
+	outcomeDuplicate = "duplicate"
+	// outcomeInvalid is the outcome of the invalid events skipped.
+	outcomeInvalid = "invalid"
+)
+
+var (
+	tagReceiver, _   = tag.NewKey("receiver")
+	tagStatusCode, _ = tag.NewKey("status_code")
+	tagSourceType, _ = tag.NewKey("sourcetype")
+	tagOutcome, _    = tag.NewKey("outcome")
+
+	statRequests      = stats.Int64("splunk_hec_receiver_requests", "Number of HEC requests, by HTTP status code of their response", stats.UnitDimensionless)
+	statReceivedBytes = stats.Int64("splunk_hec_receiver_received_bytes", "Number of bytes of HEC request bodies, as sent over the wire", stats.UnitBytes)
+	statEvents        = stats.Int64("splunk_hec_receiver_events", "Number of HEC events, by sourcetype and outcome", stats.UnitDimensionless)
+	statEventLatency  = stats.Int64("splunk_hec_receiver_event_latency", "Time between the time of HEC events and the time they are received, by sourcetype", stats.UnitMilliseconds)
+
+	// eventLatencyBounds are the bounds in milliseconds of the buckets of the
+	// event latency histogram, from 10ms to a day.
+	eventLatencyBounds = []float64{10, 50, 100, 500, 1000, 5000, 10000, 30000, 60000, 300000, 900000, 3600000, 21600000, 86400000}
+	// eventLatencyView is created once, as views with distribution
+	// aggregations are only the same view when they are the same instance.
+	eventLatencyView = &view.View{
+		Name:        statEventLatency.Name(),
+		Measure:     statEventLatency,
+		Description: statEventLatency.Description(),
+		TagKeys:     []tag.Key{tagReceiver, tagSourceType},
+		Aggregation: view.Distribution(eventLatencyBounds...),
+	}
+)
+
+// MetricViews returns the metric views of the Splunk HEC receiver.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        statRequests.Name(),
+			Measure:     statRequests,
+			Description: statRequests.Description(),
+			TagKeys:     []tag.Key{tagReceiver, tagStatusCode},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statReceivedBytes.Name(),
+			Measure:     statReceivedBytes,
+			Description: statReceivedBytes.Description(),
+			TagKeys:     []tag.Key{tagReceiver},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        statEvents.Name(),
+			Measure:     statEvents,
+			Description: statEvents.Description(),
+			TagKeys:     []tag.Key{tagReceiver, tagSourceType, tagOutcome},
+			Aggregation: view.Sum(),
+		},
+		eventLatencyView,
+	}
+}
+
+// observeRequests records the status code of the responses to requests, and
+// the size of their bodies, as read by next.
+func (r *splunkReceiver) observeRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body := &countingReader{ReadCloser: req.Body}
+		req.Body = body
+		recorder := &statusRecorder{ResponseWriter: resp, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		mutators := []tag.Mutator{
+			tag.Upsert(tagReceiver, r.settings.ID.String()),
+			tag.Upsert(tagStatusCode, strconv.Itoa(recorder.statusCode)),
+		}
+		_ = stats.RecordWithTags(req.Context(), mutators, statRequests.M(1))
+		if body.count > 0 {
+			_ = stats.RecordWithTags(req.Context(), mutators[:1], statReceivedBytes.M(body.count))
+		}
+	})
+}
+
+// recordEvents records the number of events of each sourcetype passed to the
+// next consumer, as accepted or refused according to consumeErr.
+func (r *splunkReceiver) recordEvents(ctx context.Context, sourceTypes map[string]int, consumeErr error) {
+	outcome := outcomeAccepted
+	if consumeErr != nil {
+		outcome = outcomeRefused
+	}
+	r.recordEventsOutcome(ctx, sourceTypes, outcome)
+}
+
+// recordDroppedEvents records the number of events of each sourcetype dropped
+// as they were sent to a blackhole index.
+func (r *splunkReceiver) recordDroppedEvents(ctx context.Context, sourceTypes map[string]int) {
+	r.recordEventsOutcome(ctx, sourceTypes, outcomeDropped)
+}
+
+func (r *splunkReceiver) recordEventsOutcome(ctx context.Context, sourceTypes map[string]int, outcome string) {
+	for sourceType, count := range sourceTypes {
+		_ = stats.RecordWithTags(ctx, []tag.Mutator{
+			tag.Upsert(tagReceiver, r.settings.ID.String()),
+			tag.Upsert(tagSourceType, sourceType),
+			tag.Upsert(tagOutcome, outcome),
+		}, statEvents.M(int64(count)))
+	}
+}
+
+// latencyRecorder records the latency of the events of a request, the time
+// between their time and the time they are received, caching the tags of
+// their sourcetypes.
+type latencyRecorder struct {
+	ctx        context.Context
+	receiver   string
+	receivedAt time.Time
+	tagged     map[string]context.Context
+}
+
+func (r *splunkReceiver) newLatencyRecorder(ctx context.Context, receivedAt time.Time) *latencyRecorder {
+	return &latencyRecorder{
+		ctx:        ctx,
+		receiver:   r.settings.ID.String(),
+		receivedAt: receivedAt,
+		tagged:     map[string]context.Context{},
+	}
+}
+
+// record records the latency of an event of sourceType with time eventTime.
+// Events dated after the time they are received, sent by clients with a wrong
+// clock, are recorded with no latency.
+func (l *latencyRecorder) record(sourceType string, eventTime time.Time) {
+	latency := l.receivedAt.Sub(eventTime)
+	if latency < 0 {
+		latency = 0
+	}
+	tagged, ok := l.tagged[sourceType]
+	if !ok {
+		tagged, _ = tag.New(l.ctx, tag.Upsert(tagReceiver, l.receiver), tag.Upsert(tagSourceType, sourceType))
+		l.tagged[sourceType] = tagged
+	}
+	stats.Record(tagged, statEventLatency.M(latency.Milliseconds()))
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	count int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	if !s.wroteHeader {
+		s.statusCode = statusCode
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(statusCode)
+}
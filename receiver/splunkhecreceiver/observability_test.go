@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/internal/metadata"
+)
+
+// viewSums returns the sums recorded by the view named name for receiver, keyed by
+// the values of tagKeys.
+func viewSums(t *testing.T, name string, receiver component.ID, tagKeys ...tag.Key) map[string]float64 {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+	sums := make(map[string]float64)
+	for _, row := range rows {
+		values := make(map[tag.Key]string, len(row.Tags))
+		for _, rowTag := range row.Tags {
+			values[rowTag.Key] = rowTag.Value
+		}
+		if values[tagReceiver] != receiver.String() {
+			continue
+		}
+		key := make([]string, 0, len(tagKeys))
+		for _, k := range tagKeys {
+			key = append(key, values[k])
+		}
+		sums[strings.Join(key, "/")] = row.Data.(*view.SumData).Value
+	}
+	return sums
+}
+
+func Test_splunkhecReceiver_observability(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	settings := receivertest.NewNopCreateSettings()
+	settings.ID = component.NewIDWithName(metadata.Type, "observability")
+	var consumeErr error
+	next, err := consumer.NewLogs(func(context.Context, plog.Logs) error { return consumeErr })
+	require.NoError(t, err)
+	rcv, err := newLogsReceiver(settings, *createDefaultConfig().(*Config), next)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	handler := r.observeRequests(http.HandlerFunc(r.handleReq))
+	send := func(method, body string) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "http://localhost/services/collector", strings.NewReader(body)))
+	}
+	events := `{"event":"a","sourcetype":"app"}{"event":"b","sourcetype":"app"}{"event":"c","sourcetype":"db"}`
+	send(http.MethodPost, events)
+	consumeErr = errors.New("refused")
+	send(http.MethodPost, events)
+	send(http.MethodGet, "")
+
+	assert.Equal(t, map[string]float64{"200": 1, "500": 1, "400": 1}, viewSums(t, statRequests.Name(), settings.ID, tagStatusCode))
+	assert.Equal(t, map[string]float64{"": float64(2 * len(events))}, viewSums(t, statReceivedBytes.Name(), settings.ID))
+	assert.Equal(t, map[string]float64{
+		"app/accepted": 2,
+		"db/accepted":  1,
+		"app/refused":  2,
+		"db/refused":   1,
+	}, viewSums(t, statEvents.Name(), settings.ID, tagSourceType, tagOutcome))
+}
+
+func Test_splunkhecReceiver_eventLatency(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	settings := receivertest.NewNopCreateSettings()
+	settings.ID = component.NewIDWithName(metadata.Type, "latency")
+	config := createDefaultConfig().(*Config)
+	config.Timestamp.Extract = TimestampExtractConfig{Pattern: `^\d+`, Layout: "20060102150405"}
+	config.UseReceiveTimeOnMissing = true
+	rcv, err := newLogsReceiver(settings, *config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	now := time.Now()
+	body := fmt.Sprintf(`{"event":"a","sourcetype":"app","time":%d}{"event":"b","sourcetype":"app","time":%d}{"event":"no time","sourcetype":"app"}{"event":"c","sourcetype":"db","time":%d}`,
+		now.Add(-2*time.Minute).Unix(), now.Add(-3*time.Second).Unix(), now.Add(time.Hour).Unix())
+	r.handleReq(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(body)))
+	raw := now.Add(-10*time.Hour).UTC().Format("20060102150405") + " raw\nno time"
+	r.handleRawReq(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost/services/collector/raw?sourcetype=syslog", strings.NewReader(raw)))
+
+	rows, err := view.RetrieveData(statEventLatency.Name())
+	require.NoError(t, err)
+	counts := map[string][]int64{}
+	for _, row := range rows {
+		values := map[tag.Key]string{}
+		for _, rowTag := range row.Tags {
+			values[rowTag.Key] = rowTag.Value
+		}
+		if values[tagReceiver] == settings.ID.String() {
+			counts[values[tagSourceType]] = row.Data.(*view.DistributionData).CountPerBucket
+		}
+	}
+	bucket := func(counts map[int]int64) []int64 {
+		perBucket := make([]int64, len(eventLatencyBounds)+1)
+		for i, count := range counts {
+			perBucket[i] = count
+		}
+		return perBucket
+	}
+	assert.Equal(t, map[string][]int64{
+		// 3s is in the 1s-5s bucket, 2m in the 1m-5m bucket.
+		"app": bucket(map[int]int64{5: 1, 9: 1}),
+		// Events from the future have no latency.
+		"db": bucket(map[int]int64{0: 1}),
+		// 10h is in the 6h-1d bucket.
+		"syslog": bucket(map[int]int64{13: 1}),
+	}, counts)
+}
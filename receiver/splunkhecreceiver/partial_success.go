@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// deliverLogsPartially passes ld to the next consumer one resource at a time,
+// so that a resource refused with a permanent error does not fail the others.
+// groups describes the events converted into each resource of ld. The request
+// is then answered with the position of the first event of the first refused
+// resource, as Splunk HEC does for invalid events.
+func (r *splunkReceiver) deliverLogsPartially(ctx context.Context, ld plog.Logs, groups []resourceGroup, delivery *logsDelivery) {
+	var retryableErr error
+	resources := ld.ResourceLogs()
+	for i := 0; i < resources.Len(); i++ {
+		resourceLogs := plog.NewLogs()
+		resources.At(i).MoveTo(resourceLogs.ResourceLogs().AppendEmpty())
+		group := groups[i]
+		err := r.logsConsumer.ConsumeLogs(ctx, resourceLogs)
+		r.recordEvents(ctx, group.sourceTypes, err)
+		if err != nil && !consumererror.IsPermanent(err) {
+			retryableErr = err
+		}
+		delivery.record(group.firstEvent, err)
+	}
+	r.recordConsumeResult(retryableErr)
+}
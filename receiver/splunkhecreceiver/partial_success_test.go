@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// hostRefusingConsumer refuses the logs of the hosts it holds an error for,
+// and keeps the bodies of the others.
+type hostRefusingConsumer struct {
+	mu     sync.Mutex
+	errs   map[string]error
+	bodies []string
+}
+
+func (c *hostRefusingConsumer) consumer(t *testing.T) consumer.Logs {
+	next, err := consumer.NewLogs(func(_ context.Context, ld plog.Logs) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		require.Equal(t, 1, ld.ResourceLogs().Len())
+		rl := ld.ResourceLogs().At(0)
+		hostName, _ := rl.Resource().Attributes().Get("host.name")
+		if err := c.errs[hostName.Str()]; err != nil {
+			return err
+		}
+		records := rl.ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			c.bodies = append(c.bodies, records.At(i).Body().Str())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return next
+}
+
+func Test_splunkhecReceiver_partialSuccess(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         map[string]error
+		wantStatus   int
+		wantRespBody string
+		wantBodies   []string
+	}{
+		{
+			name:         "accepted",
+			wantStatus:   http.StatusOK,
+			wantRespBody: `{"text":"Success","code":0}`,
+			wantBodies:   []string{"a", "e", "b", "d", "c"},
+		},
+		{
+			name:         "permanent_errors",
+			errs:         map[string]error{"b": consumererror.NewPermanent(errors.New("invalid")), "c": consumererror.NewPermanent(errors.New("invalid"))},
+			wantStatus:   http.StatusBadRequest,
+			wantRespBody: `{"text":"Invalid data format","code":6,"invalid-event-number":2}`,
+			wantBodies:   []string{"a", "e"},
+		},
+		{
+			// Retrying the request would duplicate the accepted resources.
+			name:         "retryable_error",
+			errs:         map[string]error{"b": errors.New("queue is full")},
+			wantStatus:   http.StatusBadRequest,
+			wantRespBody: `{"text":"Invalid data format","code":6,"invalid-event-number":2}`,
+			wantBodies:   []string{"a", "e", "c"},
+		},
+		{
+			name:         "all_retryable_errors",
+			errs:         map[string]error{"a": errors.New("queue is full"), "b": errors.New("queue is full"), "c": errors.New("queue is full")},
+			wantStatus:   http.StatusInternalServerError,
+			wantRespBody: `{"text":"Internal Server Error","code":8}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &hostRefusingConsumer{errs: tt.errs}
+			config := createDefaultConfig().(*Config)
+			config.PartialSuccess = true
+			config.BlackholeIndexes = []string{"debug"}
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, next.consumer(t))
+			require.NoError(t, err)
+
+			// The event sent to a blackhole index is still numbered.
+			body := `{"event":"a","host":"a"}{"event":"x","host":"b","index":"debug"}{"event":"b","host":"b"}` +
+				`{"event":"c","host":"c"}{"event":"d","host":"b"}{"event":"e","host":"a"}`
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(body))
+			w := httptest.NewRecorder()
+			rcv.(*splunkReceiver).handleReq(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantRespBody, w.Body.String())
+			assert.Equal(t, tt.wantBodies, next.bodies)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// Names of the selectable profiles mapping HEC metadata to attributes.
+const (
+	profileSplunk  = "splunk"
+	profileOtel127 = "otel-1.27"
+	profileECS     = "ecs"
+)
+
+var mappingProfiles = map[string]splunk.HecToOtelAttrs{
+	profileSplunk: {
+		Source:     splunk.DefaultSourceLabel,
+		SourceType: splunk.DefaultSourceTypeLabel,
+		Index:      splunk.DefaultIndexLabel,
+		Host:       conventions.AttributeHostName,
+	},
+	profileOtel127: {
+		Source:     "log.file.path",
+		SourceType: splunk.DefaultSourceTypeLabel,
+		Index:      splunk.DefaultIndexLabel,
+		Host:       conventions.AttributeHostName,
+	},
+	profileECS: {
+		Source:     "log.file.path",
+		SourceType: "event.dataset",
+		Index:      "data_stream.namespace",
+		Host:       conventions.AttributeHostName,
+	},
+}
+
+// profileHecToOtelAttrs returns the mapping of the given profile, overridden
+// by the fields of attrs that differ from the default mapping. attrs is
+// returned unchanged when no profile is selected.
+func profileHecToOtelAttrs(profile string, attrs splunk.HecToOtelAttrs) splunk.HecToOtelAttrs {
+	mapping, ok := mappingProfiles[profile]
+	if !ok {
+		return attrs
+	}
+	defaults := mappingProfiles[profileSplunk]
+	if attrs.Source != defaults.Source {
+		mapping.Source = attrs.Source
+	}
+	if attrs.SourceType != defaults.SourceType {
+		mapping.SourceType = attrs.SourceType
+	}
+	if attrs.Index != defaults.Index {
+		mapping.Index = attrs.Index
+	}
+	if attrs.Host != defaults.Host {
+		mapping.Host = attrs.Host
+	}
+	return mapping
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_profileHecToOtelAttrs(t *testing.T) {
+	defaults := createDefaultConfig().(*Config).HecToOtelAttrs
+	tests := []struct {
+		name    string
+		profile string
+		attrs   splunk.HecToOtelAttrs
+		want    splunk.HecToOtelAttrs
+	}{
+		{
+			name:  "no_profile",
+			attrs: defaults,
+			want:  defaults,
+		},
+		{
+			name:    "splunk",
+			profile: profileSplunk,
+			attrs:   defaults,
+			want:    defaults,
+		},
+		{
+			name:    "ecs",
+			profile: profileECS,
+			attrs:   defaults,
+			want: splunk.HecToOtelAttrs{
+				Source:     "log.file.path",
+				SourceType: "event.dataset",
+				Index:      "data_stream.namespace",
+				Host:       "host.name",
+			},
+		},
+		{
+			name:    "otel_overridden",
+			profile: profileOtel127,
+			attrs: splunk.HecToOtelAttrs{
+				Source:     defaults.Source,
+				SourceType: "custom.sourcetype",
+				Index:      defaults.Index,
+				Host:       defaults.Host,
+			},
+			want: splunk.HecToOtelAttrs{
+				Source:     "log.file.path",
+				SourceType: "custom.sourcetype",
+				Index:      "com.splunk.index",
+				Host:       "host.name",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, profileHecToOtelAttrs(tt.profile, tt.attrs))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// profilingScopeName is the instrumentation scope of the log records holding
+// profiling data, as produced by Splunk APM agents. The Splunk HEC exporter
+// sends the logs of this scope as profiling data.
+const profilingScopeName = "otel.profiling"
+
+// defaultProfilingResourceAttributes are the fields of profiling events set
+// as resource attributes by default, identifying the profiled service.
+var defaultProfilingResourceAttributes = []string{
+	conventions.AttributeServiceName,
+	conventions.AttributeDeploymentEnvironment,
+}
+
+// isProfilingEvent reports whether event holds profiling data, such as the
+// base64 encoded pprof of Splunk APM agents, according to its sourcetype.
+func isProfilingEvent(event *splunk.Event, config *Config) bool {
+	return config.Profiling.Enabled && containsString(config.Profiling.SourceTypes, event.SourceType)
+}
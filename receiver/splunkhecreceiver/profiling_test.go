@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_profiling(t *testing.T) {
+	const payload = "H4sIAAAAAAAA/+KSUwgtLkksSVQoyUgtSgUAAAD//w=="
+	body := `{"event":"` + payload + `","sourcetype":"otel.profiling","fields":{"service.name":"checkout","profiling.data.type":"cpu"}}` +
+		`{"event":"regular","sourcetype":"app","fields":{"service.name":"checkout"}}`
+	tests := []struct {
+		name    string
+		enabled bool
+		want    []string
+	}{
+		{name: "enabled", enabled: true, want: []string{profilingScopeName, "otelcol/splunkhecreceiver"}},
+		{name: "disabled", want: []string{"otelcol/splunkhecreceiver", "otelcol/splunkhecreceiver"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Profiling.Enabled = tt.enabled
+			config.ParseJSONEvents = true
+			config.MaxEventSize = 8
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			w := httptest.NewRecorder()
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			ld := sink.AllLogs()[0]
+			require.Equal(t, len(tt.want), ld.ResourceLogs().Len())
+			for i, scopeName := range tt.want {
+				assert.Equal(t, scopeName, ld.ResourceLogs().At(i).ScopeLogs().At(0).Scope().Name())
+			}
+			profile := ld.ResourceLogs().At(0)
+			record := profile.ScopeLogs().At(0).LogRecords().At(0)
+			if !tt.enabled {
+				_, truncated := record.Attributes().Get(truncatedAttr)
+				assert.True(t, truncated)
+				return
+			}
+			assert.Equal(t, payload, record.Body().Str())
+			assert.Equal(t, map[string]interface{}{
+				"service.name":          "checkout",
+				"com.splunk.sourcetype": "otel.profiling",
+			}, profile.Resource().Attributes().AsRaw())
+			assert.Equal(t, map[string]interface{}{"profiling.data.type": "cpu"}, record.Attributes().AsRaw())
+		})
+	}
+}
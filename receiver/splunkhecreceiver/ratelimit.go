@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+const (
+	rateLimitKeyToken   = "token"
+	rateLimitKeyChannel = "channel"
+	rateLimitKeyTenant  = "tenant"
+
+	// rateLimitSweepInterval is the interval buckets which filled up again are forgotten at.
+	rateLimitSweepInterval = time.Minute
+)
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// rateLimiter limits the rate of events received per HEC token, channel or
+// tenant with a token bucket per key. The events of a request are only known once it is
+// decoded, so requests are admitted while their bucket is not empty and their
+// events are charged afterwards, possibly leaving the bucket in debt.
+type rateLimiter struct {
+	key   string
+	limit rateLimit
+	// tenantLimits overrides the limit of tenants, when limiting by tenant.
+	tenantLimits map[string]rateLimit
+	// tenantOf returns the tenant of requests, when limiting by tenant.
+	tenantOf  func(*http.Request) string
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// rateLimit is the sustained rate of events and the burst allowed to a key. A
+// zero rate means no limit.
+type rateLimit struct {
+	rate  float64
+	burst float64
+}
+
+func newRateLimit(eventsPerSecond float64, burst int) rateLimit {
+	if burst == 0 {
+		return rateLimit{rate: eventsPerSecond, burst: math.Max(eventsPerSecond, 1)}
+	}
+	return rateLimit{rate: eventsPerSecond, burst: float64(burst)}
+}
+
+type tokenBucket struct {
+	limit   rateLimit
+	tokens  float64
+	updated time.Time
+}
+
+// newRateLimiter returns the limiter configured by config, or nil when no key
+// is limited. tenantOf returns the tenant of requests.
+func newRateLimiter(config RateLimitConfig, tenantOf func(*http.Request) string) *rateLimiter {
+	key := config.Key
+	if key == "" {
+		key = rateLimitKeyToken
+	}
+	limited := config.EventsPerSecond > 0
+	var tenantLimits map[string]rateLimit
+	if key == rateLimitKeyTenant && len(config.Tenants) > 0 {
+		tenantLimits = make(map[string]rateLimit, len(config.Tenants))
+		for tenant, limit := range config.Tenants {
+			tenantLimits[tenant] = newRateLimit(limit.EventsPerSecond, limit.Burst)
+			limited = limited || limit.EventsPerSecond > 0
+		}
+	}
+	if !limited {
+		return nil
+	}
+	return &rateLimiter{
+		key:          key,
+		limit:        newRateLimit(config.EventsPerSecond, config.Burst),
+		tenantLimits: tenantLimits,
+		tenantOf:     tenantOf,
+		now:          time.Now,
+		buckets:      map[string]*tokenBucket{},
+	}
+}
+
+// admit returns the key of the bucket of req, and whether req is admitted.
+// Rejected requests should be retried after the returned duration, once their
+// bucket holds a token again. A nil limiter admits all requests.
+func (l *rateLimiter) admit(req *http.Request) (string, time.Duration, bool) {
+	if l == nil {
+		return "", 0, true
+	}
+	key := l.keyOf(req)
+	if l.limitOf(key).rate == 0 {
+		return key, 0, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.refill(key)
+	if b.tokens >= 1 {
+		return key, 0, true
+	}
+	return key, time.Duration((1 - b.tokens) / b.limit.rate * float64(time.Second)), false
+}
+
+// charge takes n tokens from the bucket of key, the number of events of an
+// admitted request.
+func (l *rateLimiter) charge(key string, n int) {
+	if l == nil || n == 0 || l.limitOf(key).rate == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(key).tokens -= float64(n)
+}
+
+func (l *rateLimiter) keyOf(req *http.Request) string {
+	switch l.key {
+	case rateLimitKeyChannel:
+		return channel(req)
+	case rateLimitKeyTenant:
+		return l.tenantOf(req)
+	}
+	return strings.TrimPrefix(req.Header.Get(authorizationHeader), splunk.HECTokenHeader+" ")
+}
+
+// limitOf returns the limit of key, overridden for some tenants.
+func (l *rateLimiter) limitOf(key string) rateLimit {
+	if limit, ok := l.tenantLimits[key]; ok {
+		return limit
+	}
+	return l.limit
+}
+
+// refill returns the bucket of key, refilled for the time elapsed since it was
+// last updated. It must be called with l.mu held.
+func (l *rateLimiter) refill(key string) *tokenBucket {
+	now := l.now()
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		limit := l.limitOf(key)
+		b = &tokenBucket{limit: limit, tokens: limit.burst, updated: now}
+		l.buckets[key] = b
+		return b
+	}
+	b.tokens = math.Min(b.limit.burst, b.tokens+now.Sub(b.updated).Seconds()*b.limit.rate)
+	b.updated = now
+	return b
+}
+
+// sweep forgets the buckets which filled up again, as they are equivalent to
+// new ones, so that the buckets of keys no longer in use do not pile up.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*b.limit.rate >= b.limit.burst {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(RateLimitConfig{Key: "channel", EventsPerSecond: 2, Burst: 4}, nil)
+	limiter.now = func() time.Time { return now }
+	req := func(channel string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", nil)
+		r.Header.Set(channelHeader, channel)
+		return r
+	}
+
+	key, _, ok := limiter.admit(req("a"))
+	require.True(t, ok)
+	assert.Equal(t, "a", key)
+	limiter.charge(key, 5)
+
+	// The bucket is in debt, admitting requests again once it holds a token.
+	_, retryAfter, ok := limiter.admit(req("a"))
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+	_, _, ok = limiter.admit(req("b"))
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, _, ok = limiter.admit(req("a"))
+	assert.True(t, ok)
+
+	// Buckets which filled up again are forgotten.
+	now = now.Add(rateLimitSweepInterval)
+	_, _, ok = limiter.admit(req("c"))
+	assert.True(t, ok)
+	assert.Len(t, limiter.buckets, 1)
+
+	assert.Nil(t, newRateLimiter(RateLimitConfig{}, nil))
+}
+
+func TestRateLimiterDefaults(t *testing.T) {
+	limiter := newRateLimiter(RateLimitConfig{EventsPerSecond: 0.5}, nil)
+	assert.Equal(t, rateLimitKeyToken, limiter.key)
+	assert.Equal(t, 1.0, limiter.limit.burst)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", nil)
+	req.Header.Set(authorizationHeader, "Splunk token")
+	assert.Equal(t, "token", limiter.keyOf(req))
+}
+
+func Test_splunkhecReceiver_rateLimit(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.RateLimit = RateLimitConfig{EventsPerSecond: 1, Burst: 2}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(handler http.HandlerFunc, url, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set(authorizationHeader, "Splunk "+token)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	w := send(r.handleReq, "http://localhost/services/collector", "noisy", `{"event":"a"}{"event":"b"}{"event":"c"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = send(r.handleRawReq, "http://localhost/services/collector/raw", "noisy", "d")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, string(rateLimitedRespBody), w.Body.String())
+	assert.Equal(t, "2", w.Header().Get(retryAfterHeader))
+
+	w = send(r.handleRawReq, "http://localhost/services/collector/raw", "quiet", "e")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 4, sink.LogRecordCount())
+}
+
+func Test_splunkhecReceiver_rateLimitTenant(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Tenant.TrustedProxies = []string{"10.0.0.0/8"}
+	config.RateLimit = RateLimitConfig{
+		Key:             rateLimitKeyTenant,
+		EventsPerSecond: 1,
+		Tenants: map[string]TenantRateLimitConfig{
+			"premium":  {EventsPerSecond: 100},
+			"internal": {},
+		},
+	}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func(remoteAddr, tenant string, events int) int {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(strings.Repeat(`{"event":"a"}`, events)))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(defaultTenantHeader, tenant)
+		w := httptest.NewRecorder()
+		r.handleReq(w, req)
+		return w.Code
+	}
+
+	// Tenants behind the gateway share its address, but not their bucket.
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "basic", 2))
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1:1234", "basic", 1))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "other", 1))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "premium", 50))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "premium", 50))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "internal", 1000))
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "internal", 1000))
+
+	// The tenant header of untrusted clients is ignored, their requests sharing a bucket.
+	assert.Equal(t, http.StatusOK, send("192.168.0.1:1234", "premium", 2))
+	assert.Equal(t, http.StatusTooManyRequests, send("192.168.0.1:1234", "internal", 1))
+}
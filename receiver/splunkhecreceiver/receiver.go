@@ -4,6 +4,7 @@
 package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -11,8 +12,12 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -21,7 +26,9 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
@@ -29,61 +36,177 @@ import (
 )
 
 const (
-	defaultServerTimeout = 20 * time.Second
-
-	responseOK                        = "OK"
-	responseHecHealthy                = `{"text": "HEC is healthy", "code": 17}`
+	responseSuccess                   = "Success"
+	responseHecHealthy                = "HEC is healthy"
+	responseHecQueuesFull             = "HEC is unhealthy, queues are full"
+	responseHecNotReady               = "HEC is not ready"
 	responseInvalidMethod             = `Only "POST" method is supported`
-	responseInvalidEncoding           = `"Content-Encoding" must be "gzip" or empty`
-	responseInvalidDataFormat         = `{"text":"Invalid data format","code":6}`
-	responseErrEventRequired          = `{"text":"Event field is required","code":12}`
-	responseErrEventBlank             = `{"text":"Event field cannot be blank","code":13}`
+	responseInvalidEncoding           = `"Content-Encoding" must be "gzip", "zstd" or empty`
+	responseInvalidDataFormat         = "Invalid data format"
+	responseErrEventRequired          = "Event field is required"
+	responseErrEventBlank             = "Event field cannot be blank"
 	responseErrGzipReader             = "Error on gzip body"
+	responseErrZstdReader             = "Error on zstd body"
+	responseDecompressedTooLarge      = "Decompressed body is too large"
+	responseContentTooLarge           = "Content length is too large"
 	responseErrUnmarshalBody          = "Failed to unmarshal message body"
 	responseErrInternalServerError    = "Internal Server Error"
 	responseErrUnsupportedMetricEvent = "Unsupported metric event"
 	responseErrUnsupportedLogEvent    = "Unsupported log event"
-	responseErrHandlingIndexedFields  = `{"text":"Error in handling indexed fields","code":15,"invalid-event-number":%d}`
-	responseNoData                    = `{"text":"No data","code":5}`
+	responseErrHandlingIndexedFields  = "Error in handling indexed fields"
+	responseNoData                    = "No data"
+	responseDataChannelMissing        = "Data channel is missing"
+	responseTokenRequired             = "Token is required"
+	responseInvalidAuthorization      = "Invalid authorization"
+	responseInvalidToken              = "Invalid token"
+	responseTokenDisabled             = "Token disabled"
+	responseIncorrectIndex            = "Incorrect index"
+	responseAckDisabled               = "ACK is disabled"
+	responseServerBusy                = "Server is busy"
+	responseInvalidSchema             = "Event does not match the schema"
+	responseRateLimited               = "Rate limit exceeded"
+	responseTimeOutOfRange            = "Event time is out of range"
+	responseEventTooLarge             = "Event is too large"
 	// Centralizing some HTTP and related string constants.
 	gzipEncoding              = "gzip"
 	httpContentEncodingHeader = "Content-Encoding"
+	httpAcceptEncodingHeader  = "Accept-Encoding"
+)
+
+// Codes returned in the "code" field of the JSON response bodies. Codes below
+// 100 are the ones defined by the Splunk HTTP Event Collector, codes starting
+// at 100 identify failure modes specific to this receiver. Codes are stable
+// and can be relied upon by clients.
+const (
+	hecCodeSuccess                = 0
+	hecCodeTokenDisabled          = 1
+	hecCodeTokenRequired          = 2
+	hecCodeInvalidAuthorization   = 3
+	hecCodeInvalidToken           = 4
+	hecCodeNoData                 = 5
+	hecCodeInvalidDataFormat      = 6
+	hecCodeIncorrectIndex         = 7
+	hecCodeInternalServerError    = 8
+	hecCodeServerBusy             = 9
+	hecCodeDataChannelMissing     = 10
+	hecCodeEventRequired          = 12
+	hecCodeEventBlank             = 13
+	hecCodeAckDisabled            = 14
+	hecCodeHandlingIndexedFields  = 15
+	hecCodeHealthy                = 17
+	hecCodeQueuesFull             = 18
+	hecCodeInvalidMethod          = 100
+	hecCodeInvalidEncoding        = 101
+	hecCodeGzipReader             = 102
+	hecCodeUnmarshalBody          = 103
+	hecCodeUnsupportedMetricEvent = 104
+	hecCodeUnsupportedLogEvent    = 105
+	hecCodeZstdReader             = 106
+	hecCodeDecompressedTooLarge   = 107
+	hecCodeNotReady               = 108
+	hecCodeContentTooLarge        = 109
+	hecCodeInvalidSchema          = 110
+	hecCodeRateLimited            = 111
+	hecCodeTimeOutOfRange         = 112
+	hecCodeEventTooLarge          = 113
 )
 
+// decodeChunkSize is the number of decoded log events converted at once, unless
+// max_events_per_batch is set.
+const decodeChunkSize = 1024
+
 var (
 	errNilNextMetricsConsumer = errors.New("nil metricsConsumer")
 	errNilNextLogsConsumer    = errors.New("nil logsConsumer")
+	errNilNextTracesConsumer  = errors.New("nil tracesConsumer")
 	errEmptyEndpoint          = errors.New("empty endpoint")
 	errInvalidMethod          = errors.New("invalid http method")
 	errInvalidEncoding        = errors.New("invalid encoding")
+	errMissingChannel         = errors.New("missing data channel")
+	errAckChannelFull         = errors.New("too many ack IDs of the data channel not queried")
+	errTimeOutOfRange         = errors.New("event time out of range")
+	errAllEventsInvalid       = errors.New("all events are invalid")
 
-	okRespBody                = initJSONResponse(responseOK)
-	eventRequiredRespBody     = initJSONResponse(responseErrEventRequired)
-	eventBlankRespBody        = initJSONResponse(responseErrEventBlank)
-	invalidEncodingRespBody   = initJSONResponse(responseInvalidEncoding)
-	invalidFormatRespBody     = initJSONResponse(responseInvalidDataFormat)
-	invalidMethodRespBody     = initJSONResponse(responseInvalidMethod)
-	errGzipReaderRespBody     = initJSONResponse(responseErrGzipReader)
-	errUnmarshalBodyRespBody  = initJSONResponse(responseErrUnmarshalBody)
-	errInternalServerError    = initJSONResponse(responseErrInternalServerError)
-	errUnsupportedMetricEvent = initJSONResponse(responseErrUnsupportedMetricEvent)
-	errUnsupportedLogEvent    = initJSONResponse(responseErrUnsupportedLogEvent)
-	noDataRespBody            = initJSONResponse(responseNoData)
+	okRespBody                   = initHecResponse(responseSuccess, hecCodeSuccess)
+	healthyRespBody              = initHecResponse(responseHecHealthy, hecCodeHealthy)
+	queuesFullRespBody           = initHecResponse(responseHecQueuesFull, hecCodeQueuesFull)
+	notReadyRespBody             = initHecResponse(responseHecNotReady, hecCodeNotReady)
+	invalidEncodingRespBody      = initHecResponse(responseInvalidEncoding, hecCodeInvalidEncoding)
+	invalidFormatRespBody        = initHecResponse(responseInvalidDataFormat, hecCodeInvalidDataFormat)
+	invalidMethodRespBody        = initHecResponse(responseInvalidMethod, hecCodeInvalidMethod)
+	errGzipReaderRespBody        = initHecResponse(responseErrGzipReader, hecCodeGzipReader)
+	errZstdReaderRespBody        = initHecResponse(responseErrZstdReader, hecCodeZstdReader)
+	decompressedTooLargeBody     = initHecResponse(responseDecompressedTooLarge, hecCodeDecompressedTooLarge)
+	contentTooLargeRespBody      = initHecResponse(responseContentTooLarge, hecCodeContentTooLarge)
+	errUnmarshalBodyRespBody     = initHecResponse(responseErrUnmarshalBody, hecCodeUnmarshalBody)
+	errInternalServerError       = initHecResponse(responseErrInternalServerError, hecCodeInternalServerError)
+	errUnsupportedMetricEvent    = initHecResponse(responseErrUnsupportedMetricEvent, hecCodeUnsupportedMetricEvent)
+	errUnsupportedLogEvent       = initHecResponse(responseErrUnsupportedLogEvent, hecCodeUnsupportedLogEvent)
+	noDataRespBody               = initHecResponse(responseNoData, hecCodeNoData)
+	dataChannelMissingRespBody   = initHecResponse(responseDataChannelMissing, hecCodeDataChannelMissing)
+	ackDisabledRespBody          = initHecResponse(responseAckDisabled, hecCodeAckDisabled)
+	tokenRequiredRespBody        = initHecResponse(responseTokenRequired, hecCodeTokenRequired)
+	invalidAuthorizationRespBody = initHecResponse(responseInvalidAuthorization, hecCodeInvalidAuthorization)
+	invalidTokenRespBody         = initHecResponse(responseInvalidToken, hecCodeInvalidToken)
+	serverBusyRespBody           = initHecResponse(responseServerBusy, hecCodeServerBusy)
+	tokenDisabledRespBody        = initHecResponse(responseTokenDisabled, hecCodeTokenDisabled)
+	incorrectIndexRespBody       = initHecResponse(responseIncorrectIndex, hecCodeIncorrectIndex)
+	rateLimitedRespBody          = initHecResponse(responseRateLimited, hecCodeRateLimited)
 )
 
+// hecResponse is the JSON body returned by the receiver for health checks, failures and acknowledged requests.
+type hecResponse struct {
+	Text               string  `json:"text"`
+	Code               int     `json:"code"`
+	InvalidEventNumber *int    `json:"invalid-event-number,omitempty"`
+	AckID              *uint64 `json:"ackId,omitempty"`
+	// SkippedEventNumbers are the positions of the invalid events skipped.
+	SkippedEventNumbers []int `json:"skipped-event-numbers,omitempty"`
+}
+
 // splunkReceiver implements the receiver.Metrics for Splunk HEC metric protocol.
 type splunkReceiver struct {
 	settings        receiver.CreateSettings
 	config          *Config
 	logsConsumer    consumer.Logs
 	metricsConsumer consumer.Metrics
+	tracesConsumer  consumer.Traces
 	server          *http.Server
 	shutdownWG      sync.WaitGroup
 	obsrecv         *obsreport.Receiver
 	gzipReaderPool  *sync.Pool
+	zstdDecoderPool *sync.Pool
+	cancelReplay    context.CancelFunc
+	trustedProxies  []*net.IPNet
+	multilineRules  map[string]*multilineRule
+	lineBreaker     *regexp.Regexp
+	router          *sourceTypeRouter
+	metricTypes     metricTypeRules
+	// startTimes tracks the start time of the series of cumulative sums.
+	startTimes      *cumulativeStarts
+	charset         *charset
+	timestamps      *timestampParser
+	timeExtractor   *timestampExtractor
+	cancelHeartbeat context.CancelFunc
+	hostLookup      *hostLookup
+	cancelLookup    context.CancelFunc
+	lastReceived    atomic.Int64
+	acks            *ackManager
+	cancelAcks      context.CancelFunc
+	hosts           *hostNormalizer
+	tokens          *tokenSource
+	cancelTokens    context.CancelFunc
+	blackholes      blackholeSet
+	dedup           *dedupCache
+	rateLimiter     *rateLimiter
+	requestSlots    chan struct{}
+	ready           atomic.Bool
+	pipelineBlocked atomic.Bool
+	shedUntil       atomic.Int64
 }
 
 var _ receiver.Metrics = (*splunkReceiver)(nil)
+var _ receiver.Traces = (*splunkReceiver)(nil)
 
 // newMetricsReceiver creates the Splunk HEC receiver with the given configuration.
 func newMetricsReceiver(
@@ -94,39 +217,11 @@ func newMetricsReceiver(
 	if nextConsumer == nil {
 		return nil, errNilNextMetricsConsumer
 	}
-
-	if config.Endpoint == "" {
-		return nil, errEmptyEndpoint
-	}
-
-	transport := "http"
-	if config.TLSSetting != nil {
-		transport = "https"
-	}
-
-	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
-		ReceiverID:             settings.ID,
-		Transport:              transport,
-		ReceiverCreateSettings: settings,
-	})
+	r, err := newReceiver(settings, config)
 	if err != nil {
 		return nil, err
 	}
-	r := &splunkReceiver{
-		settings:        settings,
-		config:          &config,
-		metricsConsumer: nextConsumer,
-		server: &http.Server{
-			Addr: config.Endpoint,
-			// TODO: Evaluate what properties should be configurable, for now
-			//		set some hard-coded values.
-			ReadHeaderTimeout: defaultServerTimeout,
-			WriteTimeout:      defaultServerTimeout,
-		},
-		obsrecv:        obsrecv,
-		gzipReaderPool: &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
-	}
-
+	r.metricsConsumer = nextConsumer
 	return r, nil
 }
 
@@ -139,10 +234,38 @@ func newLogsReceiver(
 	if nextConsumer == nil {
 		return nil, errNilNextLogsConsumer
 	}
+	r, err := newReceiver(settings, config)
+	if err != nil {
+		return nil, err
+	}
+	r.logsConsumer = nextConsumer
+	return r, nil
+}
 
+// newTracesReceiver creates the Splunk HEC receiver with the given configuration.
+func newTracesReceiver(
+	settings receiver.CreateSettings,
+	config Config,
+	nextConsumer consumer.Traces,
+) (receiver.Traces, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextTracesConsumer
+	}
+	r, err := newReceiver(settings, config)
+	if err != nil {
+		return nil, err
+	}
+	r.tracesConsumer = nextConsumer
+	return r, nil
+}
+
+// newReceiver creates the Splunk HEC receiver shared by the metrics, logs and
+// traces pipelines, without its consumers.
+func newReceiver(settings receiver.CreateSettings, config Config) (*splunkReceiver, error) {
 	if config.Endpoint == "" {
 		return nil, errEmptyEndpoint
 	}
+
 	transport := "http"
 	if config.TLSSetting != nil {
 		transport = "https"
@@ -156,21 +279,68 @@ func newLogsReceiver(
 	if err != nil {
 		return nil, err
 	}
+	trustedProxies, err := parseCIDRs(config.Tenant.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if config.Scope.Version == "" {
+		config.Scope.Version = settings.BuildInfo.Version
+	}
+	config.HecToOtelAttrs = profileHecToOtelAttrs(config.Profile, config.HecToOtelAttrs)
+	multilineRules, err := newMultilineRules(config.Multiline)
+	if err != nil {
+		return nil, err
+	}
+	lineBreaker, err := newLineBreaker(config.LineBreaker)
+	if err != nil {
+		return nil, err
+	}
+	timeExtractor, err := newTimestampExtractor(config.Timestamp.Extract)
+	if err != nil {
+		return nil, err
+	}
+	router, err := newSourceTypeRouter(&config)
+	if err != nil {
+		return nil, err
+	}
+	metricTypes, err := newMetricTypeRules(&config)
+	if err != nil {
+		return nil, err
+	}
+	charset, err := newCharset(config.Encoding, config.SanitizeInvalidUTF8)
+	if err != nil {
+		return nil, err
+	}
 
 	r := &splunkReceiver{
-		settings:     settings,
-		config:       &config,
-		logsConsumer: nextConsumer,
+		settings: settings,
+		config:   &config,
 		server: &http.Server{
-			Addr: config.Endpoint,
-			// TODO: Evaluate what properties should be configurable, for now
-			//		set some hard-coded values.
-			ReadHeaderTimeout: defaultServerTimeout,
-			WriteTimeout:      defaultServerTimeout,
+			Addr:              config.Endpoint,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			WriteTimeout:      config.WriteTimeout,
 		},
-		gzipReaderPool: &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
-		obsrecv:        obsrecv,
+		obsrecv:         obsrecv,
+		gzipReaderPool:  &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
+		zstdDecoderPool: &sync.Pool{New: newZstdDecoder},
+		trustedProxies:  trustedProxies,
+		multilineRules:  multilineRules,
+		lineBreaker:     lineBreaker,
+		router:          router,
+		metricTypes:     metricTypes,
+		startTimes:      newCumulativeStarts(metricTypes),
+		charset:         charset,
+		acks:            newAckManager(&config),
+		hosts:           newHostNormalizer(&config),
+		tokens:          newTokenSource(&config, settings.Logger),
+		blackholes:      newBlackholeSet(&config),
+		dedup:           newDedupCache(&config),
+		requestSlots:    newRequestSlots(&config),
+		timestamps:      newTimestampParser(config.Timestamp),
+		timeExtractor:   timeExtractor,
+		hostLookup:      newHostLookup(&config, settings.Logger),
 	}
+	r.rateLimiter = newRateLimiter(config.RateLimit, r.tenant)
 
 	return r, nil
 }
@@ -178,57 +348,176 @@ func newLogsReceiver(
 // Start tells the receiver to start its processing.
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
-func (r *splunkReceiver) Start(_ context.Context, host component.Host) error {
+func (r *splunkReceiver) Start(ctx context.Context, host component.Host) error {
 	// server.Handler will be nil on initial call, otherwise noop.
 	if r.server != nil && r.server.Handler != nil {
 		return nil
 	}
 
-	var ln net.Listener
-	// set up the listener
-	ln, err := r.config.HTTPServerSettings.ToListener()
-	if err != nil {
-		return fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
-	}
-
 	mx := mux.NewRouter()
 	mx.NewRoute().Path(r.config.HealthPath).HandlerFunc(r.handleHealthReq)
 	mx.NewRoute().Path(r.config.HealthPath + "/1.0").HandlerFunc(r.handleHealthReq).Methods("GET")
+	mx.NewRoute().Path(r.config.Ack.Path).HandlerFunc(r.handleAckReq)
 	if r.logsConsumer != nil {
 		mx.NewRoute().Path(r.config.RawPath).HandlerFunc(r.handleRawReq)
+		mx.NewRoute().Path(r.config.RawPath + "/1.0").HandlerFunc(r.handleRawReq)
+	}
+	if r.config.EventPath != "" {
+		mx.NewRoute().Path(r.config.EventPath).HandlerFunc(r.handleReq)
+		mx.NewRoute().Path(r.config.EventPath + "/1.0").HandlerFunc(r.handleReq)
+	} else {
+		mx.NewRoute().HandlerFunc(r.handleReq)
+	}
+
+	if r.acks != nil {
+		if err := r.acks.start(ctx, host, r.config.Ack, r.settings.TelemetrySettings, r.settings.ID); err != nil {
+			return err
+		}
+		if r.config.Ack.StorageID != nil {
+			var ctx context.Context
+			ctx, r.cancelAcks = context.WithCancel(context.Background())
+			r.shutdownWG.Add(1)
+			go func() {
+				defer r.shutdownWG.Done()
+				r.acks.flushPeriodically(ctx)
+			}()
+		}
+	}
+
+	if r.hostLookup != nil {
+		if err := r.hostLookup.load(); err != nil {
+			return err
+		}
+		if r.config.HostLookup.ReloadInterval > 0 {
+			var ctx context.Context
+			ctx, r.cancelLookup = context.WithCancel(context.Background())
+			r.shutdownWG.Add(1)
+			go func() {
+				defer r.shutdownWG.Done()
+				r.hostLookup.reload(ctx, r.config.HostLookup.ReloadInterval)
+			}()
+		}
+	}
+
+	if r.tokens != nil && r.config.TokensFile.Path != "" {
+		if err := r.tokens.load(); err != nil {
+			return err
+		}
+		if r.config.TokensFile.ReloadInterval > 0 {
+			var ctx context.Context
+			ctx, r.cancelTokens = context.WithCancel(context.Background())
+			r.shutdownWG.Add(1)
+			go func() {
+				defer r.shutdownWG.Done()
+				r.tokens.reload(ctx, r.config.TokensFile.ReloadInterval)
+			}()
+		}
 	}
-	mx.NewRoute().HandlerFunc(r.handleReq)
 
-	r.server, err = r.config.HTTPServerSettings.ToServer(host, r.settings.TelemetrySettings, mx)
+	if r.logsConsumer != nil && r.config.Heartbeat.Interval > 0 {
+		var ctx context.Context
+		ctx, r.cancelHeartbeat = context.WithCancel(context.Background())
+		r.markReceived()
+		r.shutdownWG.Add(1)
+		go func() {
+			defer r.shutdownWG.Done()
+			r.heartbeat(ctx)
+		}()
+	}
+
+	if r.config.Replay != nil {
+		var ctx context.Context
+		ctx, r.cancelReplay = context.WithCancel(context.Background())
+		r.shutdownWG.Add(1)
+		go func() {
+			defer r.shutdownWG.Done()
+			if errReplay := r.replay(ctx, mx); errReplay != nil {
+				host.ReportFatalError(errReplay)
+			}
+		}()
+		return nil
+	}
+
+	handler, err := r.withAuth(host, mx)
 	if err != nil {
 		return err
 	}
+	// Requests are authenticated by handler, not by the server.
+	serverSettings := r.config.HTTPServerSettings
+	serverSettings.Auth = nil
+	serverSettings.CORS = corsSettings(r.config)
 
-	// TODO: Evaluate what properties should be configurable, for now
-	//		set some hard-coded values.
-	r.server.ReadHeaderTimeout = defaultServerTimeout
-	r.server.WriteTimeout = defaultServerTimeout
+	var ln net.Listener
+	// set up the listener
+	ln, err = serverSettings.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+	}
 
-	r.shutdownWG.Add(1)
-	go func() {
-		defer r.shutdownWG.Done()
-		if errHTTP := r.server.Serve(ln); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
-			host.ReportFatalError(errHTTP)
+	r.server, err = serverSettings.ToServer(host, r.settings.TelemetrySettings, handler, r.serverOptions()...)
+	if err != nil {
+		return err
+	}
+	r.server.Handler = r.observeRequests(r.limitContentLength(r.server.Handler))
+
+	if err = configureServer(r.server, r.config); err != nil {
+		_ = ln.Close()
+		return err
+	}
+
+	listeners := []net.Listener{ln}
+	if r.config.UnixSocket.Path != "" {
+		var unixLn net.Listener
+		if unixLn, err = listenUnixSocket(r.config.UnixSocket.Path); err != nil {
+			_ = ln.Close()
+			return err
 		}
-	}()
+		listeners = append(listeners, unixLn)
+	}
+
+	for _, l := range listeners {
+		r.shutdownWG.Add(1)
+		go func(l net.Listener) {
+			defer r.shutdownWG.Done()
+			if errHTTP := r.server.Serve(l); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+				host.ReportFatalError(errHTTP)
+			}
+		}(l)
+	}
+	r.ready.Store(true)
 
 	return err
 }
 
 // Shutdown tells the receiver that should stop reception,
 // giving it a chance to perform any necessary clean-up.
-func (r *splunkReceiver) Shutdown(context.Context) error {
+func (r *splunkReceiver) Shutdown(ctx context.Context) error {
+	r.ready.Store(false)
+	if r.cancelReplay != nil {
+		r.cancelReplay()
+	}
+	if r.cancelHeartbeat != nil {
+		r.cancelHeartbeat()
+	}
+	if r.cancelLookup != nil {
+		r.cancelLookup()
+	}
+	if r.cancelTokens != nil {
+		r.cancelTokens()
+	}
+	if r.cancelAcks != nil {
+		r.cancelAcks()
+	}
 	err := r.server.Close()
 	r.shutdownWG.Wait()
+	if r.acks != nil {
+		err = multierr.Append(err, r.acks.shutdown(ctx))
+	}
 	return err
 }
 
 func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Request) {
+	observedTime := pcommon.NewTimestampFromTime(time.Now())
 	ctx := req.Context()
 	ctx = r.obsrecv.StartLogsOp(ctx)
 
@@ -237,52 +526,146 @@ func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	var token *TokenConfig
+	if r.tokens != nil {
+		var status int
+		var respBody []byte
+		var err error
+		if token, status, respBody, err = r.tokens.authorize(req); token == nil {
+			r.failRequest(ctx, resp, status, respBody, 0, err)
+			return
+		}
+	}
+
+	if retryAfter, shed := r.shedding(); shed {
+		setRetryAfter(resp, retryAfter)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errServerBusy)
+		return
+	}
+
+	rateLimitKey, retryAfter, admitted := r.rateLimiter.admit(req)
+	if !admitted {
+		setRetryAfter(resp, retryAfter)
+		r.failRequest(ctx, resp, http.StatusTooManyRequests, rateLimitedRespBody, 0, errRateLimited)
+		return
+	}
+
+	release, acquired := r.acquireRequestSlot(ctx)
+	if !acquired {
+		setRetryAfter(resp, concurrentRetryAfter)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errTooManyConcurrent)
+		return
+	}
+	defer release()
+
 	encoding := req.Header.Get(httpContentEncodingHeader)
-	if encoding != "" && encoding != gzipEncoding {
+	if !isSupportedEncoding(encoding) {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, 0, errInvalidEncoding)
 		return
 	}
 
+	if r.acks != nil && channel(req) == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, dataChannelMissingRespBody, 0, errMissingChannel)
+		return
+	}
+	if r.acks != nil && r.acks.full(channel(req)) {
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errAckChannelFull)
+		return
+	}
+
 	if req.ContentLength == 0 {
 		r.obsrecv.EndLogsOp(ctx, metadata.Type, 0, nil)
 		r.failRequest(ctx, resp, http.StatusBadRequest, noDataRespBody, 0, nil)
 		return
 	}
 
-	bodyReader := req.Body
-	if encoding == gzipEncoding {
-		reader := r.gzipReaderPool.Get().(*gzip.Reader)
-		err := reader.Reset(bodyReader)
-
-		if err != nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, 0, err)
-			_, _ = io.ReadAll(req.Body)
-			_ = req.Body.Close()
-			return
-		}
-		bodyReader = reader
-		defer r.gzipReaderPool.Put(reader)
+	body, failRespBody, err := r.decodeBody(encoding, req)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, failRespBody, 0, err)
+		_, _ = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		return
 	}
+	defer body.Close()
 
 	resourceCustomizer := r.createResourceCustomizer(req)
-	ld, slLen, err := splunkHecRawToLogData(bodyReader, req.URL.Query(), resourceCustomizer, r.config)
+	query := req.URL.Query()
+	if token != nil {
+		if query.Get(index) == "" && token.Index != "" {
+			query.Set(index, token.Index)
+		}
+		if query.Get(sourcetype) == "" && token.SourceType != "" {
+			query.Set(sourcetype, token.SourceType)
+		}
+	}
+	if !token.allowsIndex(query.Get(index)) {
+		r.failRequest(ctx, resp, http.StatusBadRequest, incorrectIndexRespBody, 0, errIncorrectIndex)
+		return
+	}
+	if query.Has(sourcetype) {
+		query.Set(sourcetype, r.renameSourceType(query.Get(sourcetype)))
+	}
+	if r.hosts != nil && query.Has(host) {
+		query.Set(host, r.hosts.normalize(ctx, query.Get(host)))
+	}
+	ld, slLen, err := splunkHecRawToLogData(body, query, resourceCustomizer, r.config, r.multilineRules[query.Get(sourcetype)], r.lineBreaker, r.timeExtractor, observedTime)
 	if err != nil {
+		if tooLargeRespBody := body.tooLarge(); tooLargeRespBody != nil {
+			r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, tooLargeRespBody, slLen, err)
+			return
+		}
+		if errors.Is(err, errEventTooLarge) {
+			r.failRequest(ctx, resp, http.StatusBadRequest, invalidEventRespBody(responseEventTooLarge, hecCodeEventTooLarge, slLen), slLen, err)
+			return
+		}
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, slLen, err)
 		return
 	}
-	consumerErr := r.logsConsumer.ConsumeLogs(ctx, ld)
+	r.rateLimiter.charge(rateLimitKey, slLen)
+	if r.timeExtractor != nil {
+		latencies := r.newLatencyRecorder(ctx, observedTime.AsTime())
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			// Log records without extracted time have no timestamp, or the
+			// observed timestamp when using the receive time on missing.
+			if ts := records.At(i).Timestamp(); ts != 0 && ts != observedTime {
+				latencies.record(query.Get(sourcetype), ts.AsTime())
+			}
+		}
+	}
+	if r.blackholes.drops(query.Get(index)) {
+		r.recordDroppedEvents(ctx, map[string]int{query.Get(sourcetype): slLen})
+		_ = req.Body.Close()
+		r.acceptDropped(ctx, resp, req, slLen)
+		return
+	}
+	r.setTraceContext(req, ld)
+	r.markReceived()
+	consumed, consumerErr := r.consumeLogBatches(ctx, ld)
+	r.recordConsumeResult(consumerErr)
+	r.recordEvents(ctx, map[string]int{query.Get(sourcetype): slLen}, consumerErr)
 
-	_ = bodyReader.Close()
+	_ = req.Body.Close()
 
-	if consumerErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, slLen, consumerErr)
-	} else {
+	switch {
+	case consumerErr != nil && consumed > 0:
+		// Retrying the request would duplicate the batches already accepted.
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, consumed), slLen, consumerErr)
+	case consumerErr != nil:
+		status, failRespBody := r.consumeFailure(resp, consumerErr)
+		r.failRequest(ctx, resp, status, failRespBody, slLen, consumerErr)
+	default:
+		resp.Header().Add("Content-Type", "application/json")
 		resp.WriteHeader(http.StatusOK)
+		if _, err = resp.Write(r.successRespBody(req)); err != nil {
+			r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(err))
+		}
 		r.obsrecv.EndLogsOp(ctx, metadata.Type, slLen, nil)
 	}
 }
 
 func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
+	observedTime := pcommon.NewTimestampFromTime(time.Now())
 	ctx := req.Context()
 	if r.logsConsumer == nil {
 		ctx = r.obsrecv.StartMetricsOp(ctx)
@@ -295,125 +678,521 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	var token *TokenConfig
+	if r.tokens != nil {
+		var status int
+		var respBody []byte
+		var err error
+		if token, status, respBody, err = r.tokens.authorize(req); token == nil {
+			r.failRequest(ctx, resp, status, respBody, 0, err)
+			return
+		}
+	}
+
+	if retryAfter, shed := r.shedding(); shed {
+		setRetryAfter(resp, retryAfter)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errServerBusy)
+		return
+	}
+
+	rateLimitKey, retryAfter, admitted := r.rateLimiter.admit(req)
+	if !admitted {
+		setRetryAfter(resp, retryAfter)
+		r.failRequest(ctx, resp, http.StatusTooManyRequests, rateLimitedRespBody, 0, errRateLimited)
+		return
+	}
+
+	release, acquired := r.acquireRequestSlot(ctx)
+	if !acquired {
+		setRetryAfter(resp, concurrentRetryAfter)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errTooManyConcurrent)
+		return
+	}
+	defer release()
+
 	encoding := req.Header.Get(httpContentEncodingHeader)
-	if encoding != "" && encoding != gzipEncoding {
+	if !isSupportedEncoding(encoding) {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, 0, errInvalidEncoding)
 		return
 	}
 
-	bodyReader := req.Body
-	if encoding == gzipEncoding {
-		reader := r.gzipReaderPool.Get().(*gzip.Reader)
-		err := reader.Reset(bodyReader)
-		if err != nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, 0, err)
-			return
-		}
-		bodyReader = reader
-		defer r.gzipReaderPool.Put(reader)
+	if r.acks != nil && channel(req) == "" {
+		r.failRequest(ctx, resp, http.StatusBadRequest, dataChannelMissingRespBody, 0, errMissingChannel)
+		return
+	}
+	if r.acks != nil && r.acks.full(channel(req)) {
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, serverBusyRespBody, 0, errAckChannelFull)
+		return
 	}
 
+	body, failRespBody, err := r.decodeBody(encoding, req)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, failRespBody, 0, err)
+		return
+	}
+	defer body.Close()
+
 	if req.ContentLength == 0 {
 		r.failRequest(ctx, resp, http.StatusBadRequest, noDataRespBody, 0, nil)
 		return
 	}
 
-	dec := jsoniter.NewDecoder(bodyReader)
+	dec := hecJSON.NewDecoder(newEventStreamReader(body))
 
+	// Log events are converted by chunks while decoding the body, so the memory
+	// used by a request is bounded by its converted logs rather than by its
+	// decoded events as well. With max_events_per_batch, the chunks are batches
+	// passed to the next consumer as soon as they are converted, bounding the
+	// memory used by a request by the size of a batch.
+	var converter *logsConverter
+	delivery := &logsDelivery{}
+	chunkSize := decodeChunkSize
+	if r.logsConsumer != nil {
+		converter = newLogsConverter(r.settings.Logger, r.createResourceCustomizer(req), r.config, observedTime)
+		converter.positions = map[*splunk.Event]int{}
+		if r.config.MaxEventsPerBatch > 0 {
+			chunkSize = r.config.MaxEventsPerBatch
+		}
+	}
 	var events []*splunk.Event
+	var rawEvents [][]byte
+	var spans []*splunk.Event
+	numEvents := 0
+	sourceTypes := make(map[string]int)
+	spanSourceTypes := make(map[string]int)
+	droppedSourceTypes := make(map[string]int)
+	duplicateSourceTypes := make(map[string]int)
+	query := req.URL.Query()
+	// Accepted events are remembered once the request is answered with success,
+	// so that requests failing to be consumed can be retried.
+	var dedupKeys []uint64
+	if r.dedup != nil {
+		recorder := &statusRecorder{ResponseWriter: resp, statusCode: http.StatusOK}
+		resp = recorder
+		defer func() {
+			if recorder.statusCode == http.StatusOK {
+				r.dedup.remember(dedupKeys, time.Now())
+			}
+		}()
+	}
+
+	// Invalid events fail their request, unless they are skipped so that the
+	// valid events of the request are passed to the next consumer.
+	var skipped *skippedEvents
+	if r.config.InvalidEvents == invalidEventsSkip {
+		skipped = &skippedEvents{}
+		req = withSkippedEvents(req, skipped)
+	}
+	latencies := r.newLatencyRecorder(ctx, observedTime.AsTime())
+	rawLen := 0
+	// invalidEvent fails the request because of the event being decoded,
+	// returning false, or skips the event, returning true.
+	invalidEvent := func(respBody []byte, err error) bool {
+		if skipped == nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, respBody, numEvents, err)
+			return false
+		}
+		if len(skipped.numbers) == 0 {
+			skipped.respBody = respBody
+		}
+		skipped.numbers = append(skipped.numbers, numEvents)
+		r.settings.Logger.Debug("Skipping invalid HEC event", zap.Int("event_number", numEvents), zap.Error(err))
+		if r.config.RawEvent.Enabled {
+			rawEvents = rawEvents[:rawLen]
+		}
+		numEvents++
+		return true
+	}
 
 	for dec.More() {
+		var hecMsg hecEvent
+		var err, schemaErr error
+		rawLen = len(rawEvents)
+		// Events are decoded in two steps when they are skipped, so that the
+		// events following an invalid one can still be decoded.
+		if r.config.RawEvent.Enabled || r.config.StrictSchema || skipped != nil {
+			var raw jsoniter.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				if tooLargeRespBody := body.tooLarge(); tooLargeRespBody != nil {
+					r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, tooLargeRespBody, numEvents, err)
+					return
+				}
+				r.failRequest(ctx, resp, http.StatusBadRequest, invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, numEvents), numEvents, err)
+				return
+			}
+			err = hecJSON.Unmarshal(raw, &hecMsg)
+			if r.config.RawEvent.Enabled {
+				rawEvents = append(rawEvents, raw)
+			}
+			if err == nil && r.config.StrictSchema {
+				schemaErr = hecMsg.validateSchema(raw)
+			}
+		} else {
+			err = dec.Decode(&hecMsg)
+		}
 		var msg splunk.Event
-		err := dec.Decode(&msg)
+		if err == nil {
+			err = hecMsg.toEvent(&msg, r.timestamps)
+		}
 		if err != nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, invalidFormatRespBody, len(events), err)
+			if tooLargeRespBody := body.tooLarge(); tooLargeRespBody != nil {
+				r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, tooLargeRespBody, numEvents, err)
+				return
+			}
+			if invalidEvent(invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, numEvents), err) {
+				continue
+			}
+			return
+		}
+		if schemaErr != nil {
+			if invalidEvent(invalidEventRespBody(responseInvalidSchema, hecCodeInvalidSchema, numEvents), schemaErr) {
+				continue
+			}
 			return
 		}
 
 		if msg.Event == nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, eventRequiredRespBody, len(events), nil)
+			if invalidEvent(invalidEventRespBody(responseErrEventRequired, hecCodeEventRequired, numEvents), nil) {
+				continue
+			}
 			return
 		}
 
 		if msg.Event == "" {
-			r.failRequest(ctx, resp, http.StatusBadRequest, eventBlankRespBody, len(events), nil)
+			if invalidEvent(invalidEventRespBody(responseErrEventBlank, hecCodeEventBlank, numEvents), nil) {
+				continue
+			}
 			return
 		}
 
-		for _, v := range msg.Fields {
-			if !isFlatJSONField(v) {
-				r.failRequest(ctx, resp, http.StatusBadRequest, []byte(fmt.Sprintf(responseErrHandlingIndexedFields, len(events))), len(events), nil)
-				return
+		if r.config.Fields.Flatten {
+			msg.Fields = flattenFields(msg.Fields)
+		}
+		if !areFlatJSONFields(msg.Fields) {
+			if invalidEvent(invalidEventRespBody(responseErrHandlingIndexedFields, hecCodeHandlingIndexedFields, numEvents), nil) {
+				continue
+			}
+			return
+		}
+		if !msg.IsMetric() {
+			r.timestamps.preciseTime(&msg)
+		}
+		r.timeExtractor.extractTime(&msg, observedTime.AsTime())
+		if !r.timestamps.checkRange(&msg, observedTime.AsTime()) {
+			if invalidEvent(invalidEventRespBody(responseTimeOutOfRange, hecCodeTimeOutOfRange, numEvents), errTimeOutOfRange) {
+				continue
 			}
+			return
 		}
+		isSpan := r.tracesConsumer != nil && isSpanEvent(&msg, r.config)
 		if msg.IsMetric() {
 			if r.metricsConsumer == nil {
-				r.failRequest(ctx, resp, http.StatusBadRequest, errUnsupportedMetricEvent, len(events), err)
+				if invalidEvent(errUnsupportedMetricEvent, nil) {
+					continue
+				}
 				return
 			}
-		} else if r.logsConsumer == nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, errUnsupportedLogEvent, len(events), err)
+		} else if r.logsConsumer == nil && !isSpan {
+			if invalidEvent(errUnsupportedLogEvent, nil) {
+				continue
+			}
 			return
 		}
 
+		applyQueryDefaults(query, &msg)
+		token.applyDefaults(&msg)
+		msg.SourceType = r.renameSourceType(msg.SourceType)
+		// Truncating profiling events would corrupt their encoded payload.
+		if !msg.IsMetric() && !isSpan && !isProfilingEvent(&msg, r.config) && !checkEventSize(&msg, r.config) {
+			if invalidEvent(invalidEventRespBody(responseEventTooLarge, hecCodeEventTooLarge, numEvents), errEventTooLarge) {
+				continue
+			}
+			return
+		}
+		if msg.Time != 0 {
+			latencies.record(msg.SourceType, time.Unix(0, int64(msg.Time*1e9)))
+		}
+		// The log records restored from the otel.fidelity field are checked against
+		// their own index, which may differ from the one of the event.
+		var restored plog.Logs
+		restoredIndex, isRestored := "", false
+		if converter != nil && converter.restored != nil && !msg.IsMetric() && !isSpan {
+			restored, restoredIndex, isRestored = decodeOtelFidelityRecord(r.settings.Logger, &msg, r.config)
+		}
+		if !token.allowsIndex(msg.Index) || !token.allowsIndex(restoredIndex) {
+			if invalidEvent(invalidEventRespBody(responseIncorrectIndex, hecCodeIncorrectIndex, numEvents), errIncorrectIndex) {
+				continue
+			}
+			return
+		}
+		if r.dedup != nil {
+			key, err := dedupKey(channel(req), &msg)
+			if err != nil {
+				r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, numEvents, err)
+				return
+			}
+			if r.dedup.seen(key, observedTime.AsTime()) {
+				if r.config.RawEvent.Enabled {
+					rawEvents = rawEvents[:len(rawEvents)-1]
+				}
+				numEvents++
+				duplicateSourceTypes[msg.SourceType]++
+				continue
+			}
+			dedupKeys = append(dedupKeys, key)
+		}
+		if r.blackholes.drops(msg.Index) || isRestored && r.blackholes.drops(restoredIndex) {
+			if r.config.RawEvent.Enabled {
+				rawEvents = rawEvents[:len(rawEvents)-1]
+			}
+			numEvents++
+			droppedSourceTypes[msg.SourceType]++
+			continue
+		}
+		if r.hosts != nil {
+			msg.Host = r.hosts.normalize(ctx, msg.Host)
+		}
+		if isSpan {
+			if r.config.RawEvent.Enabled {
+				rawEvents = rawEvents[:len(rawEvents)-1]
+			}
+			spans = append(spans, &msg)
+			numEvents++
+			spanSourceTypes[msg.SourceType]++
+			continue
+		}
 		events = append(events, &msg)
+		if isRestored {
+			converter.restored[&msg] = restored
+		}
+		if converter != nil {
+			converter.positions[&msg] = numEvents
+		}
+		numEvents++
+		sourceTypes[msg.SourceType]++
+		if converter != nil && len(events) >= chunkSize {
+			if events, rawEvents, err = r.convertEvents(converter, events, rawEvents, false); err != nil {
+				r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, numEvents, err)
+				return
+			}
+			if r.config.MaxEventsPerBatch > 0 {
+				r.deliverLogs(ctx, converter, req, delivery)
+				// The remaining events are not decoded once the next consumer stops
+				// accepting the logs of the request.
+				if delivery.stopped(r.config.PartialSuccess) {
+					r.rateLimiter.charge(rateLimitKey, numEvents)
+					r.respondLogs(ctx, delivery, numEvents, resp, req)
+					return
+				}
+			}
+		}
 	}
-	if r.logsConsumer != nil {
-		r.consumeLogs(ctx, events, resp, req)
+	if body.err != nil {
+		if tooLargeRespBody := body.tooLarge(); tooLargeRespBody != nil {
+			r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, tooLargeRespBody, numEvents, body.err)
+			return
+		}
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidEventRespBody(responseInvalidDataFormat, hecCodeInvalidDataFormat, numEvents), numEvents, body.err)
+		return
+	}
+	if numEvents == 0 {
+		// Bodies holding no event, such as empty arrays, are answered as empty bodies.
+		r.failRequest(ctx, resp, http.StatusBadRequest, noDataRespBody, 0, nil)
+		return
+	}
+	if skipped != nil && len(skipped.numbers) > 0 {
+		r.recordEventsOutcome(ctx, map[string]int{"": len(skipped.numbers)}, outcomeInvalid)
+		if len(skipped.numbers) == numEvents {
+			r.failRequest(ctx, resp, http.StatusBadRequest, skipped.respBody, numEvents, errAllEventsInvalid)
+			return
+		}
+	}
+	r.rateLimiter.charge(rateLimitKey, numEvents)
+	r.recordDroppedEvents(ctx, droppedSourceTypes)
+	r.recordEventsOutcome(ctx, duplicateSourceTypes, outcomeDuplicate)
+	if len(spans) > 0 {
+		if status, failRespBody, err := r.consumeSpans(ctx, spans, spanSourceTypes, resp, req); err != nil {
+			r.failRequest(ctx, resp, status, failRespBody, numEvents, err)
+			return
+		}
+	}
+	if len(sourceTypes) == 0 && (len(droppedSourceTypes) > 0 || len(duplicateSourceTypes) > 0 || len(spans) > 0) {
+		r.acceptDropped(ctx, resp, req, numEvents)
+		return
+	}
+	if converter != nil {
+		if _, _, err := r.convertEvents(converter, events, rawEvents, true); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, numEvents, err)
+			return
+		}
+		r.deliverLogs(ctx, converter, req, delivery)
+		r.respondLogs(ctx, delivery, numEvents, resp, req)
 	} else {
-		r.consumeMetrics(ctx, events, resp, req)
+		r.consumeMetrics(ctx, events, sourceTypes, resp, req)
 	}
 }
 
-func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
+// convertEvents merges multiline events and converts them to logs, returning
+// the events left to convert along with their original JSON. Unless final,
+// the last event is left to convert as the next events may be merged into it.
+func (r *splunkReceiver) convertEvents(converter *logsConverter, events []*splunk.Event, rawEvents [][]byte, final bool) ([]*splunk.Event, [][]byte, error) {
+	events, rawEvents = mergeMultilineEvents(r.multilineRules, events, rawEvents)
+	n := len(events)
+	if !final && len(r.multilineRules) > 0 && n > 0 {
+		n--
+	}
+	var converted [][]byte
+	if rawEvents != nil {
+		converted = rawEvents[:n]
+	}
+	if err := converter.append(events[:n], converted); err != nil {
+		return nil, nil, err
+	}
+	// Leftover events are copied to new slices so converted ones can be released.
+	left := append(make([]*splunk.Event, 0, decodeChunkSize), events[n:]...)
+	var leftRaw [][]byte
+	if rawEvents != nil {
+		leftRaw = append(make([][]byte, 0, decodeChunkSize), rawEvents[n:]...)
+	}
+	return left, leftRaw, nil
+}
+
+// applyQueryDefaults sets the host, source, sourcetype and index passed as
+// query parameters on event when it does not specify them, as clients may set
+// the metadata of all the events of a request in its URL.
+func applyQueryDefaults(query url.Values, event *splunk.Event) {
+	if event.Host == "" {
+		event.Host = query.Get(host)
+	}
+	if event.Source == "" {
+		event.Source = query.Get(source)
+	}
+	if event.SourceType == "" {
+		event.SourceType = query.Get(sourcetype)
+	}
+	if event.Index == "" {
+		event.Index = query.Get(index)
+	}
+}
+
+// renameSourceType returns the sourcetype sourceType is renamed to, if any.
+func (r *splunkReceiver) renameSourceType(sourceType string) string {
+	if renamed, ok := r.config.SourceTypeRename[sourceType]; ok {
+		return renamed
+	}
+	return sourceType
+}
+
+func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Event, sourceTypes map[string]int, resp http.ResponseWriter, req *http.Request) {
 	resourceCustomizer := r.createResourceCustomizer(req)
-	md, _ := splunkHecToMetricsData(r.settings.Logger, events, resourceCustomizer, r.config)
+	md, _ := splunkHecToMetricsData(r.settings.Logger, events, resourceCustomizer, r.config, r.metricTypes)
+	r.startTimes.set(md, time.Now())
 
+	r.markReceived()
 	decodeErr := r.metricsConsumer.ConsumeMetrics(ctx, md)
+	r.recordConsumeResult(decodeErr)
+	r.recordEvents(ctx, sourceTypes, decodeErr)
 	r.obsrecv.EndMetricsOp(ctx, metadata.Type, len(events), decodeErr)
 
 	if decodeErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), decodeErr)
+		status, failRespBody := r.consumeFailure(resp, decodeErr)
+		r.failRequest(ctx, resp, status, failRespBody, len(events), decodeErr)
 	} else {
-		resp.WriteHeader(http.StatusOK)
-		_, err := resp.Write(okRespBody)
-		if err != nil {
+		if err := r.writeSuccess(resp, req); err != nil {
 			r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), err)
 		}
 	}
 }
 
-func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
-	resourceCustomizer := r.createResourceCustomizer(req)
-	ld, err := splunkHecToLogData(r.settings.Logger, events, resourceCustomizer, r.config)
+// consumeSpans passes the span events of a request to the traces consumer.
+func (r *splunkReceiver) consumeSpans(ctx context.Context, events []*splunk.Event, sourceTypes map[string]int, resp http.ResponseWriter, req *http.Request) (int, []byte, error) {
+	td, err := splunkHecToTracesData(r.settings.Logger, events, r.createResourceCustomizer(req), r.config)
 	if err != nil {
-		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, len(events), err)
-		return
+		return http.StatusBadRequest, errUnmarshalBodyRespBody, err
 	}
-
-	decodeErr := r.logsConsumer.ConsumeLogs(ctx, ld)
-	r.obsrecv.EndLogsOp(ctx, metadata.Type, len(events), decodeErr)
-	if decodeErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), decodeErr)
-	} else {
-		resp.WriteHeader(http.StatusOK)
-		if _, err := resp.Write(okRespBody); err != nil {
-			r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), err)
-		}
+	ctx = r.obsrecv.StartTracesOp(ctx)
+	err = r.tracesConsumer.ConsumeTraces(ctx, td)
+	r.recordConsumeResult(err)
+	r.recordEvents(ctx, sourceTypes, err)
+	r.obsrecv.EndTracesOp(ctx, metadata.Type, len(events), err)
+	if err != nil {
+		status, failRespBody := r.consumeFailure(resp, err)
+		return status, failRespBody, err
 	}
+	return http.StatusOK, nil, nil
 }
 
 func (r *splunkReceiver) createResourceCustomizer(req *http.Request) func(resource pcommon.Resource) {
+	var customizers []func(resource pcommon.Resource)
 	if r.config.AccessTokenPassthrough {
 		accessToken := req.Header.Get("Authorization")
 		if strings.HasPrefix(accessToken, splunk.HECTokenHeader+" ") {
 			accessTokenValue := accessToken[len(splunk.HECTokenHeader)+1:]
-			return func(resource pcommon.Resource) {
+			customizers = append(customizers, func(resource pcommon.Resource) {
 				resource.Attributes().PutStr(splunk.HecTokenLabel, accessTokenValue)
-			}
+			})
+		}
+	}
+	if customize := headersCustomizer(r.config.HeadersToAttributes, req); customize != nil {
+		customizers = append(customizers, customize)
+	}
+	if tenant := r.tenant(req); tenant != "" {
+		customizers = append(customizers, func(resource pcommon.Resource) {
+			resource.Attributes().PutStr(r.config.Tenant.ResourceAttribute, tenant)
+		})
+	}
+	if r.config.ChannelAttribute != "" {
+		if c := channel(req); c != "" {
+			customizers = append(customizers, func(resource pcommon.Resource) {
+				resource.Attributes().PutStr(r.config.ChannelAttribute, c)
+			})
+		}
+	}
+	if customize := clientCertificateCustomizer(r.config.ClientCertificate, req); customize != nil {
+		customizers = append(customizers, customize)
+	}
+	if r.hostLookup != nil {
+		customizers = append(customizers, r.hostLookup.enrich)
+	}
+	if r.router != nil {
+		customizers = append(customizers, r.router.route)
+	}
+	switch len(customizers) {
+	case 0:
+		return nil
+	case 1:
+		return customizers[0]
+	}
+	return func(resource pcommon.Resource) {
+		for _, customize := range customizers {
+			customize(resource)
+		}
+	}
+}
+
+// tenant returns the tenant set on req by a trusted gateway, if any.
+func (r *splunkReceiver) tenant(req *http.Request) string {
+	if len(r.trustedProxies) == 0 {
+		return ""
+	}
+	tenant := req.Header.Get(r.config.Tenant.Header)
+	if tenant == "" {
+		return ""
+	}
+	remoteHost, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remoteHost = req.RemoteAddr
+	}
+	ip := net.ParseIP(remoteHost)
+	if ip == nil {
+		return ""
+	}
+	for _, trusted := range r.trustedProxies {
+		if trusted.Contains(ip) {
+			return tenant
 		}
 	}
-	return nil
+	return ""
 }
 
 func (r *splunkReceiver) failRequest(
@@ -424,10 +1203,12 @@ func (r *splunkReceiver) failRequest(
 	numRecordsReceived int,
 	err error,
 ) {
-	resp.WriteHeader(httpStatusCode)
 	if len(jsonResponse) > 0 {
 		// The response needs to be written as a JSON string.
 		resp.Header().Add("Content-Type", "application/json")
+	}
+	resp.WriteHeader(httpStatusCode)
+	if len(jsonResponse) > 0 {
 		_, writeErr := resp.Write(jsonResponse)
 		if writeErr != nil {
 			r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(writeErr))
@@ -451,14 +1232,69 @@ func (r *splunkReceiver) failRequest(
 	}
 }
 
-func (r *splunkReceiver) handleHealthReq(writer http.ResponseWriter, _ *http.Request) {
-	writer.Header().Add("Content-Type", "application/json")
-	writer.WriteHeader(http.StatusOK)
-	_, _ = writer.Write([]byte(responseHecHealthy))
+func (r *splunkReceiver) handleHealthReq(writer http.ResponseWriter, req *http.Request) {
+	statusCode, body := r.healthStatus()
+	r.writeCompressibleResponse(writer, req, statusCode, body)
+}
+
+// writeCompressibleResponse writes the JSON body with the given status code,
+// gzip compressing it when response compression is enabled, the client
+// accepts it and the body is large enough for compression to pay off.
+func (r *splunkReceiver) writeCompressibleResponse(resp http.ResponseWriter, req *http.Request, statusCode int, body []byte) {
+	resp.Header().Add("Content-Type", "application/json")
+	if r.config.ResponseCompression.Enabled {
+		resp.Header().Add("Vary", httpAcceptEncodingHeader)
+		if len(body) >= r.config.ResponseCompression.MinSize && acceptsGzip(req) {
+			var buf bytes.Buffer
+			gzipWriter := gzip.NewWriter(&buf)
+			_, err := gzipWriter.Write(body)
+			if err == nil {
+				err = gzipWriter.Close()
+			}
+			if err == nil {
+				resp.Header().Set(httpContentEncodingHeader, gzipEncoding)
+				body = buf.Bytes()
+			} else {
+				r.settings.Logger.Debug("Cannot compress response, sending it uncompressed", zap.Error(err))
+			}
+		}
+	}
+	resp.WriteHeader(statusCode)
+	if _, err := resp.Write(body); err != nil {
+		r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(err))
+	}
+}
+
+// acceptsGzip returns whether the Accept-Encoding header of req allows gzip
+// encoded responses. An explicit gzip entry takes precedence over "*".
+func acceptsGzip(req *http.Request) bool {
+	gzipAccepted, wildcardAccepted := false, false
+	for _, header := range req.Header.Values(httpAcceptEncodingHeader) {
+		for _, coding := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			// A zero quality value explicitly refuses the coding.
+			accepted := true
+			if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+				if q, err := strconv.ParseFloat(params[len("q="):], 64); err == nil && q == 0 {
+					accepted = false
+				}
+			}
+			switch strings.TrimSpace(name) {
+			case gzipEncoding:
+				if !accepted {
+					return false
+				}
+				gzipAccepted = true
+			case "*":
+				wildcardAccepted = accepted
+			}
+		}
+	}
+	return gzipAccepted || wildcardAccepted
 }
 
-func initJSONResponse(s string) []byte {
-	respBody, err := jsoniter.Marshal(s)
+func initHecResponse(text string, code int) []byte {
+	respBody, err := jsoniter.Marshal(hecResponse{Text: text, Code: code})
 	if err != nil {
 		// This is to be used in initialization so panic here is fine.
 		panic(err)
@@ -466,6 +1302,22 @@ func initJSONResponse(s string) []byte {
 	return respBody
 }
 
+// invalidEventRespBody returns a response body pointing at the event of the
+// request that caused the failure.
+func invalidEventRespBody(text string, code int, eventNumber int) []byte {
+	respBody, _ := jsoniter.Marshal(hecResponse{Text: text, Code: code, InvalidEventNumber: &eventNumber})
+	return respBody
+}
+
+func areFlatJSONFields(fields map[string]interface{}) bool {
+	for _, v := range fields {
+		if !isFlatJSONField(v) {
+			return false
+		}
+	}
+	return true
+}
+
 func isFlatJSONField(field interface{}) bool {
 	switch value := field.(type) {
 	case map[string]interface{}:
@@ -480,3 +1332,43 @@ func isFlatJSONField(field interface{}) bool {
 	}
 	return true
 }
+
+// flattenFields returns fields with their nested objects replaced by their
+// leaves, keyed by their path joined with dots, as done by Splunk. Fields
+// sent with a dotted key take precedence over the nested ones they collide
+// with.
+func flattenFields(fields map[string]interface{}) map[string]interface{} {
+	nested := false
+	for _, v := range fields {
+		if _, ok := v.(map[string]interface{}); ok {
+			nested = true
+			break
+		}
+	}
+	if !nested {
+		return fields
+	}
+	flattened := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if _, ok := v.(map[string]interface{}); !ok {
+			flattened[k] = v
+		}
+	}
+	for k, v := range fields {
+		if m, ok := v.(map[string]interface{}); ok {
+			flattenInto(flattened, k, m)
+		}
+	}
+	return flattened
+}
+
+func flattenInto(flattened map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for k, v := range fields {
+		key := prefix + "." + k
+		if m, ok := v.(map[string]interface{}); ok {
+			flattenInto(flattened, key, m)
+		} else if _, exists := flattened[key]; !exists {
+			flattened[key] = v
+		}
+	}
+}
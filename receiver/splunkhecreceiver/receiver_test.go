@@ -34,6 +34,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/splunkhecexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/plogtest"
 )
 
 func Test_splunkhecreceiver_NewLogsReceiver(t *testing.T) {
@@ -173,7 +174,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			req:  httptest.NewRequest("PUT", "http://localhost/foo", nil),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseInvalidMethod, body)
+				assert.Equal(t, string(invalidMethodRespBody), body)
 			},
 		},
 		{
@@ -187,7 +188,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusOK, status)
-				assert.Equal(t, responseOK, body)
+				assert.Equal(t, string(okRespBody), body)
 			},
 		},
 		{
@@ -202,7 +203,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseErrUnsupportedMetricEvent, body)
+				assert.Equal(t, string(errUnsupportedMetricEvent), body)
 			},
 		},
 		{
@@ -214,7 +215,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusUnsupportedMediaType, status)
-				assert.Equal(t, responseInvalidEncoding, body)
+				assert.Equal(t, string(invalidEncodingRespBody), body)
 			},
 		},
 		{
@@ -225,7 +226,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseInvalidDataFormat, body)
+				assert.Equal(t, `{"text":"Invalid data format","code":6,"invalid-event-number":0}`, body)
 			},
 		},
 		{
@@ -236,7 +237,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseNoData, body)
+				assert.Equal(t, string(noDataRespBody), body)
 			},
 		},
 		{
@@ -249,7 +250,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseInvalidDataFormat, body)
+				assert.Equal(t, `{"text":"Invalid data format","code":6,"invalid-event-number":0}`, body)
 			},
 		},
 		{
@@ -264,7 +265,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseErrEventRequired, body)
+				assert.Equal(t, `{"text":"Event field is required","code":12,"invalid-event-number":0}`, body)
 			},
 		},
 		{
@@ -279,7 +280,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseErrEventBlank, body)
+				assert.Equal(t, `{"text":"Event field cannot be blank","code":13,"invalid-event-number":0}`, body)
 			},
 		},
 		{
@@ -292,7 +293,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusOK, status)
-				assert.Equal(t, responseOK, body)
+				assert.Equal(t, string(okRespBody), body)
 			},
 		},
 		{
@@ -313,7 +314,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusOK, status)
-				assert.Equal(t, responseOK, body)
+				assert.Equal(t, string(okRespBody), body)
 			},
 		},
 		{
@@ -328,7 +329,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseErrGzipReader, body)
+				assert.Equal(t, string(errGzipReaderRespBody), body)
 			},
 		},
 	}
@@ -347,10 +348,7 @@ func Test_splunkhecReceiver_handleReq(t *testing.T) {
 			respBytes, err := io.ReadAll(resp.Body)
 			assert.NoError(t, err)
 
-			var bodyStr string
-			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
-
-			tt.assertResponse(t, resp.StatusCode, bodyStr)
+			tt.assertResponse(t, resp.StatusCode, string(respBytes))
 		})
 	}
 }
@@ -374,11 +372,8 @@ func Test_consumer_err(t *testing.T) {
 	respBytes, err := io.ReadAll(resp.Body)
 	assert.NoError(t, err)
 
-	var bodyStr string
-	assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
-
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-	assert.Equal(t, "Internal Server Error", bodyStr)
+	assert.Equal(t, string(errInternalServerError), string(respBytes))
 }
 
 func Test_consumer_err_metrics(t *testing.T) {
@@ -401,11 +396,8 @@ func Test_consumer_err_metrics(t *testing.T) {
 	respBytes, err := io.ReadAll(resp.Body)
 	assert.NoError(t, err)
 
-	var bodyStr string
-	assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
-
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-	assert.Equal(t, "Internal Server Error", bodyStr)
+	assert.Equal(t, string(errInternalServerError), string(respBytes))
 }
 
 func Test_splunkhecReceiver_TLS(t *testing.T) {
@@ -436,6 +428,8 @@ func Test_splunkhecReceiver_TLS(t *testing.T) {
 	logs := plog.NewLogs()
 	rl := logs.ResourceLogs().AppendEmpty()
 	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("otelcol/splunkhecreceiver")
+	sl.Scope().SetVersion(receivertest.NewNopCreateSettings().BuildInfo.Version)
 	lr := sl.LogRecords().AppendEmpty()
 
 	now := time.Now()
@@ -481,6 +475,9 @@ func Test_splunkhecReceiver_TLS(t *testing.T) {
 
 	got := sink.AllLogs()
 	require.Equal(t, 1, len(got))
+	observedTime := got[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).ObservedTimestamp()
+	assert.False(t, observedTime.AsTime().Before(now))
+	lr.SetObservedTimestamp(observedTime)
 	assert.Equal(t, want, got[0])
 }
 
@@ -706,10 +703,8 @@ func Test_Logs_splunkhecReceiver_IndexSourceTypePassthrough(t *testing.T) {
 			resp := w.Result()
 			respBytes, err := io.ReadAll(resp.Body)
 			assert.NoError(t, err)
-			var bodyStr string
-			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
-			assert.Equal(t, responseOK, bodyStr)
+			assert.Equal(t, `{"text":"Success","code":0}`, string(respBytes))
 			select {
 			case <-done:
 				break
@@ -800,10 +795,8 @@ func Test_Metrics_splunkhecReceiver_IndexSourceTypePassthrough(t *testing.T) {
 			resp := w.Result()
 			respBytes, err := io.ReadAll(resp.Body)
 			assert.NoError(t, err)
-			var bodyStr string
-			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
-			assert.Equal(t, responseOK, bodyStr)
+			assert.Equal(t, `{"text":"Success","code":0}`, string(respBytes))
 			select {
 			case <-done:
 				break
@@ -892,7 +885,7 @@ func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
 			req:  httptest.NewRequest("PUT", "http://localhost/foo", nil),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseInvalidMethod, body)
+				assert.Equal(t, string(invalidMethodRespBody), body)
 			},
 		},
 		{
@@ -915,7 +908,7 @@ func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusUnsupportedMediaType, status)
-				assert.Equal(t, responseInvalidEncoding, body)
+				assert.Equal(t, string(invalidEncodingRespBody), body)
 			},
 		},
 		{
@@ -926,7 +919,7 @@ func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseNoData, body)
+				assert.Equal(t, string(noDataRespBody), body)
 			},
 		},
 
@@ -984,7 +977,7 @@ func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseErrGzipReader, body)
+				assert.Equal(t, string(errGzipReaderRespBody), body)
 			},
 		},
 	}
@@ -1007,14 +1000,7 @@ func Test_splunkhecReceiver_handleRawReq(t *testing.T) {
 			respBytes, err := io.ReadAll(resp.Body)
 			assert.NoError(t, err)
 
-			var bodyStr string
-			if len(respBytes) > 0 {
-				assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
-			} else {
-				bodyStr = ""
-			}
-
-			tt.assertResponse(t, resp.StatusCode, bodyStr)
+			tt.assertResponse(t, resp.StatusCode, string(respBytes))
 		})
 	}
 }
@@ -1036,7 +1022,7 @@ func Test_splunkhecreceiver_handleHealthPath(t *testing.T) {
 	resp := w.Result()
 	respBytes, err := io.ReadAll(resp.Body)
 	assert.NoError(t, err)
-	assert.Equal(t, string(respBytes), responseHecHealthy)
+	assert.Equal(t, string(healthyRespBody), string(respBytes))
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
@@ -1104,13 +1090,57 @@ func Test_splunkhecreceiver_handle_nested_fields(t *testing.T) {
 				assert.Equal(t, 1, sink.LogRecordCount())
 			} else {
 				assert.Equal(t, http.StatusBadRequest, w.Code)
-				assert.Equal(t, fmt.Sprintf(responseErrHandlingIndexedFields, 0), w.Body.String())
+				assert.Equal(t, `{"text":"Error in handling indexed fields","code":15,"invalid-event-number":0}`, w.Body.String())
 			}
 
 		})
 	}
 }
 
+func Test_flattenFields(t *testing.T) {
+	fields := map[string]interface{}{
+		"k8s":     map[string]interface{}{"pod": map[string]interface{}{"name": "web", "uid": "123"}, "namespace": "prod"},
+		"a":       map[string]interface{}{"b": "nested"},
+		"a.b":     "dotted",
+		"empty":   map[string]interface{}{},
+		"list":    []interface{}{int64(1), int64(2)},
+		"foo":     "bar",
+		"numbers": map[string]interface{}{"int": int64(1)},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"k8s.pod.name":  "web",
+		"k8s.pod.uid":   "123",
+		"k8s.namespace": "prod",
+		"a.b":           "dotted",
+		"list":          []interface{}{int64(1), int64(2)},
+		"foo":           "bar",
+		"numbers.int":   int64(1),
+	}, flattenFields(fields))
+
+	flat := map[string]interface{}{"foo": "bar"}
+	assert.Equal(t, flat, flattenFields(flat))
+	assert.Nil(t, flattenFields(nil))
+}
+
+func Test_splunkhecReceiver_flattenFields(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Fields.Flatten = true
+	config.Fields.ResourceAttributes = []string{"k8s.pod.name"}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	body := `{"event":"foo","fields":{"k8s":{"pod":{"name":"web"}},"http":{"status":200}}}`
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{"k8s.pod.name": "web"}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{"http.status": int64(200)}, rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+}
+
 func Test_splunkhecReceiver_rawReqHasmetadataInResource(t *testing.T) {
 	config := createDefaultConfig().(*Config)
 	config.Endpoint = "localhost:0" // Actually not creating the endpoint
@@ -1127,7 +1157,7 @@ func Test_splunkhecReceiver_rawReqHasmetadataInResource(t *testing.T) {
 
 	assertResponse := func(t *testing.T, status int, body string) {
 		assert.Equal(t, http.StatusOK, status)
-		assert.Equal(t, responseOK, body)
+		assert.Equal(t, string(okRespBody), body)
 	}
 
 	tests := []struct {
@@ -1218,10 +1248,7 @@ func Test_splunkhecReceiver_rawReqHasmetadataInResource(t *testing.T) {
 			w := httptest.NewRecorder()
 			r.handleRawReq(w, tt.req)
 
-			resp := w.Result()
-			assert.NoError(t, err)
-
-			assertResponse(t, resp.StatusCode, "OK")
+			assertResponse(t, w.Code, w.Body.String())
 			tt.assertResource(t, sink.AllLogs())
 		})
 	}
@@ -1258,6 +1285,120 @@ func BenchmarkHandleReq(b *testing.B) {
 	}
 }
 
+func Test_splunkhecReceiver_rawRoutes(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	for _, path := range []string{"/services/collector/raw", "/services/collector/raw/1.0"} {
+		t.Run(path, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+
+			r := rcv.(*splunkReceiver)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				assert.NoError(t, r.Shutdown(context.Background()))
+			}()
+
+			req := httptest.NewRequest("POST", "http://localhost:0"+path+"?host=h&source=s&sourcetype=st&index=i", strings.NewReader("foo\nbar"))
+			w := httptest.NewRecorder()
+			r.server.Handler.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+			require.Equal(t, 2, sink.LogRecordCount())
+			rl := sink.AllLogs()[0].ResourceLogs().At(0)
+			assert.Equal(t, map[string]interface{}{
+				"host.name":             "h",
+				"com.splunk.source":     "s",
+				"com.splunk.sourcetype": "st",
+				"com.splunk.index":      "i",
+			}, rl.Resource().Attributes().AsRaw())
+			assert.Equal(t, "foo", rl.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+			assert.Equal(t, "bar", rl.ScopeLogs().At(0).LogRecords().At(1).Body().Str())
+		})
+	}
+}
+
+func Test_splunkhecReceiver_customPaths(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.EventPath = "/ingest/hec/event"
+	config.RawPath = "/ingest/hec/raw"
+	config.HealthPath = "/ingest/hec/health"
+	config.Ack.Path = "/ingest/hec/ack"
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+		logs   int
+	}{
+		{path: "/ingest/hec/event", body: `{"event":"foo"}`, status: http.StatusOK, logs: 1},
+		{path: "/ingest/hec/event/1.0", body: `{"event":"foo"}`, status: http.StatusOK, logs: 1},
+		{path: "/ingest/hec/raw", body: "foo", status: http.StatusOK, logs: 1},
+		{path: "/ingest/hec/health", status: http.StatusOK},
+		{path: "/services/collector", body: `{"event":"foo"}`, status: http.StatusNotFound},
+		{path: "/services/collector/raw", body: "foo", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+
+			r := rcv.(*splunkReceiver)
+			require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() {
+				assert.NoError(t, r.Shutdown(context.Background()))
+			}()
+
+			method := http.MethodPost
+			if tt.body == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "http://localhost:0"+tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			r.server.Handler.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Result().StatusCode)
+			assert.Equal(t, tt.logs, sink.LogRecordCount())
+		})
+	}
+}
+
+func Test_splunkhecReceiver_eventQueryDefaults(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Tokens = []TokenConfig{{Token: "tok", Index: "token_index", SourceType: "token_sourcetype"}}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	body := `{"event":"foo","host":"event_host"}{"event":"bar","source":"event_source","index":"event_index"}`
+	req := httptest.NewRequest("POST", "http://localhost/services/collector?host=h&source=s&index=i", strings.NewReader(body))
+	req.Header.Set("Authorization", "Splunk tok")
+	w := httptest.NewRecorder()
+	r.handleReq(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	require.Equal(t, 1, len(sink.AllLogs()))
+	rls := sink.AllLogs()[0].ResourceLogs()
+	require.Equal(t, 2, rls.Len())
+	assert.Equal(t, map[string]interface{}{
+		"host.name":             "event_host",
+		"com.splunk.source":     "s",
+		"com.splunk.sourcetype": "token_sourcetype",
+		"com.splunk.index":      "i",
+	}, rls.At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{
+		"host.name":             "h",
+		"com.splunk.source":     "event_source",
+		"com.splunk.sourcetype": "token_sourcetype",
+		"com.splunk.index":      "event_index",
+	}, rls.At(1).Resource().Attributes().AsRaw())
+}
+
 func Test_splunkhecReceiver_healthCheck_success(t *testing.T) {
 	config := createDefaultConfig().(*Config)
 	config.Endpoint = "localhost:0" // Actually not creating the endpoint
@@ -1275,7 +1416,7 @@ func Test_splunkhecReceiver_healthCheck_success(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusOK, status)
-				assert.Equal(t, responseHecHealthy, body)
+				assert.Equal(t, string(healthyRespBody), body)
 			},
 		},
 		{
@@ -1286,7 +1427,7 @@ func Test_splunkhecReceiver_healthCheck_success(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusOK, status)
-				assert.Equal(t, responseHecHealthy, body)
+				assert.Equal(t, string(healthyRespBody), body)
 			},
 		},
 		{
@@ -1297,7 +1438,7 @@ func Test_splunkhecReceiver_healthCheck_success(t *testing.T) {
 			}(),
 			assertResponse: func(t *testing.T, status int, body string) {
 				assert.Equal(t, http.StatusBadRequest, status)
-				assert.Equal(t, responseNoData, body)
+				assert.Equal(t, string(noDataRespBody), body)
 			},
 		},
 	}
@@ -1319,12 +1460,688 @@ func Test_splunkhecReceiver_healthCheck_success(t *testing.T) {
 			resp := w.Result()
 			respBytes, err := io.ReadAll(resp.Body)
 			assert.NoError(t, err)
-			var bodyStr string
-			if err := json.Unmarshal(respBytes, &bodyStr); err != nil {
-				bodyStr = string(respBytes)
+			tt.assertResponse(t, resp.StatusCode, string(respBytes))
+		})
+	}
+}
+
+func Test_splunkhecReceiver_responseCodes(t *testing.T) {
+	tests := []struct {
+		body []byte
+		text string
+		code int
+	}{
+		{body: healthyRespBody, text: responseHecHealthy, code: 17},
+		{body: queuesFullRespBody, text: responseHecQueuesFull, code: 18},
+		{body: tokenDisabledRespBody, text: responseTokenDisabled, code: 1},
+		{body: tokenRequiredRespBody, text: responseTokenRequired, code: 2},
+		{body: invalidAuthorizationRespBody, text: responseInvalidAuthorization, code: 3},
+		{body: invalidTokenRespBody, text: responseInvalidToken, code: 4},
+		{body: noDataRespBody, text: responseNoData, code: 5},
+		{body: invalidFormatRespBody, text: responseInvalidDataFormat, code: 6},
+		{body: incorrectIndexRespBody, text: responseIncorrectIndex, code: 7},
+		{body: errInternalServerError, text: responseErrInternalServerError, code: 8},
+		{body: serverBusyRespBody, text: responseServerBusy, code: 9},
+		{body: initHecResponse(responseErrEventRequired, hecCodeEventRequired), text: responseErrEventRequired, code: 12},
+		{body: initHecResponse(responseErrEventBlank, hecCodeEventBlank), text: responseErrEventBlank, code: 13},
+		{body: invalidMethodRespBody, text: responseInvalidMethod, code: 100},
+		{body: invalidEncodingRespBody, text: responseInvalidEncoding, code: 101},
+		{body: errGzipReaderRespBody, text: responseErrGzipReader, code: 102},
+		{body: errUnmarshalBodyRespBody, text: responseErrUnmarshalBody, code: 103},
+		{body: errUnsupportedMetricEvent, text: responseErrUnsupportedMetricEvent, code: 104},
+		{body: errUnsupportedLogEvent, text: responseErrUnsupportedLogEvent, code: 105},
+		{body: errZstdReaderRespBody, text: responseErrZstdReader, code: 106},
+		{body: decompressedTooLargeBody, text: responseDecompressedTooLarge, code: 107},
+		{body: notReadyRespBody, text: responseHecNotReady, code: 108},
+		{body: contentTooLargeRespBody, text: responseContentTooLarge, code: 109},
+		{body: initHecResponse(responseInvalidSchema, hecCodeInvalidSchema), text: responseInvalidSchema, code: 110},
+		{body: rateLimitedRespBody, text: responseRateLimited, code: 111},
+		{body: initHecResponse(responseTimeOutOfRange, hecCodeTimeOutOfRange), text: responseTimeOutOfRange, code: 112},
+		{body: initHecResponse(responseEventTooLarge, hecCodeEventTooLarge), text: responseEventTooLarge, code: 113},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got map[string]interface{}
+			require.NoError(t, json.Unmarshal(tt.body, &got))
+			assert.Equal(t, map[string]interface{}{"text": tt.text, "code": float64(tt.code)}, got)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_timeOutOfRange(t *testing.T) {
+	now := time.Now().Unix()
+	body := fmt.Sprintf(`{"event":"in range","time":%d}{"event":"future","time":%d}`, now, now+7200)
+	tests := []struct {
+		outOfRange string
+		status     int
+		respBody   string
+		logs       int
+	}{
+		{
+			outOfRange: outOfRangeReject,
+			status:     http.StatusBadRequest,
+			respBody:   `{"text":"Event time is out of range","code":112,"invalid-event-number":1}`,
+		},
+		{
+			outOfRange: outOfRangeClamp,
+			status:     http.StatusOK,
+			respBody:   `{"text":"Success","code":0}`,
+			logs:       2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.outOfRange, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Timestamp.MaxFuture = time.Hour
+			config.Timestamp.OutOfRange = tt.outOfRange
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			w := httptest.NewRecorder()
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.respBody, w.Body.String())
+			require.Equal(t, tt.logs, sink.LogRecordCount())
+			if tt.logs == 0 {
+				return
 			}
+			records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			_, clamped := records.At(0).Attributes().Get(originalTimeAttr)
+			assert.False(t, clamped)
+			future := records.At(1)
+			original, clamped := future.Attributes().Get(originalTimeAttr)
+			require.True(t, clamped)
+			assert.Equal(t, float64(now+7200), original.Double())
+			assert.Less(t, future.Timestamp().AsTime().Unix(), now+7200)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_strictSchema(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantRespBody string
+	}{
+		{
+			name:       "valid",
+			body:       `{"time":1.5,"host":"h","source":"s","sourcetype":"st","index":"i","event":"a","fields":{"k":"v"}}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "string_time",
+			body:         `{"event":"a"}{"time":"1.5","event":"b"}`,
+			wantStatus:   http.StatusBadRequest,
+			wantRespBody: `{"text":"Event does not match the schema","code":110,"invalid-event-number":1}`,
+		},
+		{
+			name:         "unknown_key",
+			body:         `{"event":"a","severity":"info"}`,
+			wantStatus:   http.StatusBadRequest,
+			wantRespBody: `{"text":"Event does not match the schema","code":110,"invalid-event-number":0}`,
+		},
+		{
+			name:         "non_string_host",
+			body:         `{"event":"a","host":1}`,
+			wantStatus:   http.StatusBadRequest,
+			wantRespBody: `{"text":"Invalid data format","code":6,"invalid-event-number":0}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.StrictSchema = true
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
 
-			tt.assertResponse(t, resp.StatusCode, bodyStr)
+			w := httptest.NewRecorder()
+			r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(tt.body)))
+			resp := w.Result()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantRespBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.wantRespBody, string(body))
+				assert.Equal(t, 0, sink.LogRecordCount())
+			} else {
+				assert.Equal(t, 1, sink.LogRecordCount())
+			}
 		})
 	}
 }
+
+func Test_splunkhecReceiver_chunkedConversion(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Multiline = map[string]MultilineConfig{"java": {LineStartPattern: `^\d{4}-\d{2}-\d{2}`}}
+	config.RawEvent = RawEventConfig{Enabled: true, MaxSize: 1024}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	// Every third event starts a new event, so merged events straddle the
+	// boundaries of the converted chunks.
+	numEvents := 2*decodeChunkSize + 5
+	var body strings.Builder
+	for i := 0; i < numEvents; i++ {
+		line := fmt.Sprintf("  at %d", i)
+		if i%3 == 0 {
+			line = fmt.Sprintf("2023-01-01 line %d", i)
+		}
+		fmt.Fprintf(&body, `{"event":%q,"sourcetype":"java","host":"host%d"}`, line, i/3%2)
+	}
+
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/event", strings.NewReader(body.String())))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, sink.AllLogs(), 1)
+	ld := sink.AllLogs()[0]
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+	assert.Equal(t, (numEvents+2)/3, ld.LogRecordCount())
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		records := ld.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords()
+		for j := 0; j < records.Len(); j++ {
+			start := (2*j + i) * 3
+			want := fmt.Sprintf("2023-01-01 line %d", start)
+			for k := start + 1; k < start+3 && k < numEvents; k++ {
+				want += fmt.Sprintf("\n  at %d", k)
+			}
+			assert.Equal(t, want, records.At(j).Body().Str())
+			rawEvent, ok := records.At(j).Attributes().Get(rawEventAttr)
+			require.True(t, ok)
+			assert.Equal(t, strings.Count(want, "\n")+1, strings.Count(rawEvent.Str(), "\n")+1)
+		}
+	}
+}
+
+func Test_splunkhecReceiver_rawEvent(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.RawEvent = RawEventConfig{Enabled: true, MaxSize: 64}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	first := `{"event":"first", "time":1.5,"fields":{"a":"b"}}`
+	second := `{"event":"` + strings.Repeat("x", 64) + `"}`
+	third := `{ "event" : "third" }`
+	body := first + "\n" + second + third
+	r := rcv.(*splunkReceiver)
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 3, sink.LogRecordCount())
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	raw, ok := records.At(0).Attributes().Get("splunk.raw_event")
+	require.True(t, ok)
+	assert.Equal(t, first, raw.Str())
+	_, ok = records.At(1).Attributes().Get("splunk.raw_event")
+	assert.False(t, ok)
+	raw, ok = records.At(2).Attributes().Get("splunk.raw_event")
+	require.True(t, ok)
+	assert.Equal(t, third, raw.Str())
+}
+
+func Test_splunkhecReceiver_tenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		tenant     string
+		expected   string
+	}{
+		{
+			name:       "trusted_proxy",
+			remoteAddr: "10.1.2.3:4567",
+			tenant:     "team-a",
+			expected:   "team-a",
+		},
+		{
+			name:       "untrusted_client",
+			remoteAddr: "192.0.2.1:4567",
+			tenant:     "team-a",
+		},
+		{
+			name:       "no_header",
+			remoteAddr: "10.1.2.3:4567",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Tenant.TrustedProxies = []string{"10.0.0.0/8"}
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`))
+			req.RemoteAddr = tt.remoteAddr
+			if tt.tenant != "" {
+				req.Header.Set("X-Scope-OrgID", tt.tenant)
+			}
+			w := httptest.NewRecorder()
+			rcv.(*splunkReceiver).handleReq(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			tenant, ok := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get("tenant.id")
+			if tt.expected == "" {
+				assert.False(t, ok)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, tt.expected, tenant.Str())
+			}
+		})
+	}
+}
+
+func Test_splunkhecReceiver_channelAttribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute string
+		url       string
+		header    string
+		expected  string
+	}{
+		{
+			name:      "header",
+			attribute: "com.splunk.hec.channel",
+			url:       "http://localhost/services/collector/raw",
+			header:    "00000000-0000-0000-0000-00000000000a",
+			expected:  "00000000-0000-0000-0000-00000000000a",
+		},
+		{
+			name:      "query_parameter",
+			attribute: "com.splunk.hec.channel",
+			url:       "http://localhost/services/collector/raw?channel=00000000-0000-0000-0000-00000000000b",
+			expected:  "00000000-0000-0000-0000-00000000000b",
+		},
+		{
+			name:      "no_channel",
+			attribute: "com.splunk.hec.channel",
+			url:       "http://localhost/services/collector/raw",
+		},
+		{
+			name:   "disabled",
+			url:    "http://localhost/services/collector/raw",
+			header: "00000000-0000-0000-0000-00000000000a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.ChannelAttribute = tt.attribute
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader("foo"))
+			if tt.header != "" {
+				req.Header.Set("X-Splunk-Request-Channel", tt.header)
+			}
+			w := httptest.NewRecorder()
+			rcv.(*splunkReceiver).handleRawReq(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			attrs := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes()
+			channel, ok := attrs.Get("com.splunk.hec.channel")
+			if tt.expected == "" {
+				assert.False(t, ok)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, tt.expected, channel.Str())
+			}
+		})
+	}
+}
+
+func Test_splunkhecReceiver_channelResources(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.ChannelAttribute = "com.splunk.hec.channel"
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	// Events sharing their metadata are not merged across channels.
+	for _, channel := range []string{"00000000-0000-0000-0000-00000000000a", "00000000-0000-0000-0000-00000000000b"} {
+		req := httptest.NewRequest("POST", "http://localhost/services/collector?channel="+channel, strings.NewReader(`{"event":"foo","host":"h"}{"event":"bar","host":"h"}`))
+		w := httptest.NewRecorder()
+		rcv.(*splunkReceiver).handleReq(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Len(t, sink.AllLogs(), 2)
+	for i, channel := range []string{"00000000-0000-0000-0000-00000000000a", "00000000-0000-0000-0000-00000000000b"} {
+		rls := sink.AllLogs()[i].ResourceLogs()
+		require.Equal(t, 1, rls.Len())
+		assert.Equal(t, map[string]interface{}{
+			"host.name":              "h",
+			"com.splunk.hec.channel": channel,
+		}, rls.At(0).Resource().Attributes().AsRaw())
+		assert.Equal(t, 2, rls.At(0).ScopeLogs().At(0).LogRecords().Len())
+	}
+}
+
+func Test_splunkhecReceiver_otelFidelityRoundTrip(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = addr
+	config.OtelFidelity = true
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	factory := splunkhecexporter.NewFactory()
+	exporterConfig := factory.CreateDefaultConfig().(*splunkhecexporter.Config)
+	exporterConfig.Token = "token"
+	exporterConfig.Endpoint = "http://" + addr + "/services/collector"
+	exporterConfig.OtelFidelity = true
+	exporter, err := factory.CreateLogsExporter(context.Background(), exportertest.NewNopCreateSettings(), exporterConfig)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, exporter.Shutdown(context.Background()))
+	}()
+
+	expected := plog.NewLogs()
+	for _, service := range []string{"checkout", "cart"} {
+		rl := expected.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", service)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("io.opentelemetry." + service)
+		sl.Scope().SetVersion("1.2.3")
+		for i := 0; i < 2; i++ {
+			lr := sl.LogRecords().AppendEmpty()
+			lr.SetTimestamp(pcommon.Timestamp(1001000123 + i))
+			lr.SetObservedTimestamp(pcommon.Timestamp(1002000456))
+			lr.SetSeverityNumber(plog.SeverityNumberError)
+			lr.SetSeverityText("ERROR")
+			lr.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+			lr.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+			lr.SetFlags(plog.DefaultLogRecordFlags.WithIsSampled(true))
+			lr.Attributes().PutEmptyMap("nested").PutInt("int", int64(i))
+			body := lr.Body().SetEmptyMap()
+			body.PutInt("id", 9007199254740993)
+			body.PutEmptySlice("items").AppendEmpty().SetDouble(1.5)
+		}
+	}
+
+	require.NoError(t, exporter.ConsumeLogs(context.Background(), expected))
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, plogtest.CompareLogs(expected, sink.AllLogs()[0]))
+}
+
+func Test_splunkhecReceiver_otelFidelity(t *testing.T) {
+	encode := func(index string) string {
+		ld := plog.NewLogs()
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "forged")
+		rl.Resource().Attributes().PutStr("com.splunk.hec.channel", "forged")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Body().SetStr("restored")
+		lr.Attributes().PutStr("debug", "x")
+		if index != "" {
+			lr.Attributes().PutStr("com.splunk.index", index)
+		}
+		b, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+		require.NoError(t, err)
+		return string(b)
+	}
+	event := func(index string) string {
+		b, err := json.Marshal(splunk.Event{
+			Event:  "regular",
+			Index:  "main",
+			Fields: map[string]interface{}{splunk.OtelFidelityField: encode(index)},
+		})
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		index    string
+		status   int
+		respBody string
+		body     string
+		resource map[string]interface{}
+	}{
+		{
+			name:     "disabled",
+			status:   http.StatusOK,
+			body:     "regular",
+			resource: map[string]interface{}{"com.splunk.index": "main", "com.splunk.hec.channel": "channel"},
+		},
+		{
+			name:     "enabled",
+			enabled:  true,
+			status:   http.StatusOK,
+			body:     "restored",
+			resource: map[string]interface{}{"service.name": "forged", "com.splunk.hec.channel": "channel"},
+		},
+		{
+			name:     "enabled_allowed_index",
+			enabled:  true,
+			index:    "main",
+			status:   http.StatusOK,
+			body:     "restored",
+			resource: map[string]interface{}{"service.name": "forged", "com.splunk.hec.channel": "channel"},
+		},
+		{
+			name:     "enabled_forbidden_index",
+			enabled:  true,
+			index:    "audit",
+			status:   http.StatusBadRequest,
+			respBody: `{"text":"Incorrect index","code":7,"invalid-event-number":0}`,
+		},
+		{
+			name:    "enabled_blackhole_index",
+			enabled: true,
+			index:   "debug",
+			status:  http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.OtelFidelity = tt.enabled
+			config.ChannelAttribute = "com.splunk.hec.channel"
+			config.Fields.Drop = []string{"debug"}
+			config.Tokens = []TokenConfig{{Token: "tok", Indexes: []string{"main", "debug"}}}
+			config.BlackholeIndexes = []string{"debug"}
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(event(tt.index)))
+			req.Header.Set("Authorization", "Splunk tok")
+			req.Header.Set("X-Splunk-Request-Channel", "channel")
+			w := httptest.NewRecorder()
+			r.handleReq(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			if tt.respBody != "" {
+				assert.Equal(t, tt.respBody, w.Body.String())
+			}
+			if tt.body == "" {
+				assert.Equal(t, 0, sink.LogRecordCount())
+				return
+			}
+			require.Equal(t, 1, sink.LogRecordCount())
+			rl := sink.AllLogs()[0].ResourceLogs().At(0)
+			assert.Equal(t, tt.resource, rl.Resource().Attributes().AsRaw())
+			lr := rl.ScopeLogs().At(0).LogRecords().At(0)
+			assert.Equal(t, tt.body, lr.Body().Str())
+			_, dropped := lr.Attributes().Get("debug")
+			assert.False(t, dropped)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_responseCompression(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		minSize        int
+		acceptEncoding string
+		compressed     bool
+	}{
+		{
+			name:           "disabled",
+			acceptEncoding: "gzip",
+		},
+		{
+			name:           "enabled",
+			enabled:        true,
+			acceptEncoding: "deflate, gzip;q=0.8",
+			compressed:     true,
+		},
+		{
+			name:    "not_accepted",
+			enabled: true,
+		},
+		{
+			name:           "refused",
+			enabled:        true,
+			acceptEncoding: "gzip;q=0, *",
+		},
+		{
+			name:           "below_min_size",
+			enabled:        true,
+			minSize:        1024,
+			acceptEncoding: "gzip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.ResponseCompression = ResponseCompressionConfig{Enabled: tt.enabled, MinSize: tt.minSize}
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+			r.ready.Store(true)
+
+			req := httptest.NewRequest("GET", "http://localhost/services/collector/health", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			r.handleHealthReq(w, req)
+
+			resp := w.Result()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			body := io.Reader(resp.Body)
+			if tt.compressed {
+				assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+				body, err = gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+			} else {
+				assert.Empty(t, resp.Header.Get("Content-Encoding"))
+			}
+			respBytes, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, string(healthyRespBody), string(respBytes))
+		})
+	}
+}
+
+func Test_splunkhecReceiver_sourceTypeRename(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.SourceTypeRename = map[string]string{"httpevent": "app:payments:access"}
+	config.Multiline = map[string]MultilineConfig{"app:payments:access": {LineStartPattern: `^\d`}}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	body := `{"event":"1 failed","sourcetype":"httpevent"}{"event":"  at main","sourcetype":"httpevent"}{"event":"kept","sourcetype":"other"}{"event":"2 default"}`
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector?sourcetype=httpevent", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw?sourcetype=httpevent", strings.NewReader("3 failed\n  at main")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	got := map[string][]string{}
+	for _, ld := range sink.AllLogs() {
+		for i := 0; i < ld.ResourceLogs().Len(); i++ {
+			rl := ld.ResourceLogs().At(i)
+			sourceType, ok := rl.Resource().Attributes().Get(splunk.DefaultSourceTypeLabel)
+			require.True(t, ok)
+			records := rl.ScopeLogs().At(0).LogRecords()
+			for j := 0; j < records.Len(); j++ {
+				got[sourceType.Str()] = append(got[sourceType.Str()], records.At(j).Body().Str())
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"app:payments:access": {"1 failed\n  at main", "2 default", "3 failed\n  at main"},
+		"other":               {"kept"},
+	}, got)
+}
+
+func Test_splunkhecReceiver_timestampNanosecondsField(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Timestamp.NanosecondsField = "time_ns"
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	body := `{"event":"precise","time":1600000000.123,"fields":{"time_ns":1600000000123456789,"foo":"bar"}}` +
+		`{"event":"coarse","time":1600000000.123,"fields":{"time_ns":"soon"}}`
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 1, len(sink.AllLogs()))
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, pcommon.Timestamp(1600000000123456789), records.At(0).Timestamp())
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, records.At(0).Attributes().AsRaw())
+	coarse := 1600000000.123
+	assert.Equal(t, pcommon.Timestamp(coarse*1e9), records.At(1).Timestamp())
+	assert.Equal(t, map[string]interface{}{"time_ns": "soon"}, records.At(1).Attributes().AsRaw())
+}
+
+func Test_splunkhecReceiver_timestampExtract(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Timestamp.Extract = TimestampExtractConfig{Pattern: `^\S+`, Layout: time.RFC3339}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	w := httptest.NewRecorder()
+	body := `{"event":"2022-12-31T12:00:00Z event"}{"event":"2022-12-31T12:00:00Z event with time","time":1.5e9}`
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw", strings.NewReader("2022-12-31T13:00:00Z raw\nno time")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	want := map[string]time.Time{
+		"2022-12-31T12:00:00Z event":           time.Date(2022, time.December, 31, 12, 0, 0, 0, time.UTC),
+		"2022-12-31T12:00:00Z event with time": time.Unix(1.5e9, 0),
+		"2022-12-31T13:00:00Z raw":             time.Date(2022, time.December, 31, 13, 0, 0, 0, time.UTC),
+		"no time":                              time.Unix(0, 0),
+	}
+	require.Equal(t, len(want), sink.LogRecordCount())
+	for _, ld := range sink.AllLogs() {
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			record := records.At(i)
+			assert.Equal(t, want[record.Body().Str()].UnixNano(), record.Timestamp().AsTime().UnixNano(), record.Body().Str())
+		}
+	}
+}
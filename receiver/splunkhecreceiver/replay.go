@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	replayRawExtension = ".raw"
+	replayEventPath    = "/services/collector/event"
+)
+
+// replay sends every request recorded in the replay directory to handler,
+// waiting the configured interval between two requests. It stops early when
+// ctx is cancelled.
+func (r *splunkReceiver) replay(ctx context.Context, handler http.Handler) error {
+	entries, err := os.ReadDir(r.config.Replay.Directory)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 && r.config.Replay.Interval > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(r.config.Replay.Interval):
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err = r.replayFile(ctx, handler, filepath.Join(r.config.Replay.Directory, name)); err != nil {
+			return err
+		}
+	}
+	r.settings.Logger.Info("Finished replaying recorded HEC requests", zap.Int("requests", len(names)))
+	return nil
+}
+
+func (r *splunkReceiver) replayFile(ctx context.Context, handler http.Handler, path string) error {
+	body, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	target := replayEventPath
+	if r.config.EventPath != "" {
+		target = r.config.EventPath
+	}
+	if strings.HasSuffix(path, replayRawExtension) {
+		target = r.config.RawPath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp := &replayResponseWriter{header: http.Header{}, status: http.StatusOK}
+	handler.ServeHTTP(resp, req)
+	if resp.status != http.StatusOK {
+		r.settings.Logger.Warn("Replayed HEC request failed",
+			zap.String("file", path),
+			zap.Int("http_status_code", resp.status),
+			zap.String("msg", resp.body.String()))
+	}
+	return nil
+}
+
+// replayResponseWriter records the response of a replayed request.
+type replayResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *replayResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *replayResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *replayResponseWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_replay(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001.json"), []byte(`{"event":"first","sourcetype":"replayed"}{"event":"second"}`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "002.raw"), []byte("third\nfourth\nfifth"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "003.json"), []byte(`not json`), 0600))
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Not used while replaying.
+	config.Replay = &ReplayConfig{Directory: dir, Interval: time.Millisecond}
+	sink := new(consumertest.LogsSink)
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	sourcetype, ok := logs[0].ResourceLogs().At(0).Resource().Attributes().Get("com.splunk.sourcetype")
+	require.True(t, ok)
+	assert.Equal(t, "replayed", sourcetype.Str())
+	assert.Equal(t, "third", logs[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
+
+func Test_splunkhecReceiver_replayMissingDirectory(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Replay = &ReplayConfig{Directory: filepath.Join(t.TempDir(), "missing")}
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+
+	errs := make(chan error, 1)
+	host := &reportErrorHost{Host: componenttest.NewNopHost(), errs: errs}
+	require.NoError(t, r.Start(context.Background(), host))
+	select {
+	case err = <-errs:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected replay error to be reported")
+	}
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func Test_splunkhecReceiver_replayShutdown(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001.raw"), []byte("first"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "002.raw"), []byte("second"), 0600))
+
+	config := createDefaultConfig().(*Config)
+	config.Replay = &ReplayConfig{Directory: dir, Interval: time.Hour}
+	sink := new(consumertest.LogsSink)
+	r, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, 1, sink.LogRecordCount())
+}
+
+// reportErrorHost implements a component.Host that forwards reported errors to a channel.
+type reportErrorHost struct {
+	component.Host
+	errs chan error
+}
+
+func (h *reportErrorHost) ReportFatalError(err error) {
+	h.errs <- err
+}
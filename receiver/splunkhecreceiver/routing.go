@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// sourceTypeRouter sets the route of resources, chosen according to their
+// sourcetype, as a resource attribute.
+type sourceTypeRouter struct {
+	attribute           string
+	sourceTypeAttribute string
+	routes              []sourceTypeRoute
+	defaultRoute        string
+}
+
+type sourceTypeRoute struct {
+	sourceType *regexp.Regexp
+	route      string
+}
+
+// newSourceTypeRouter returns the router configured by config, or nil when no
+// route is configured.
+func newSourceTypeRouter(config *Config) (*sourceTypeRouter, error) {
+	if len(config.Routing.Routes) == 0 && config.Routing.DefaultRoute == "" {
+		return nil, nil
+	}
+	router := &sourceTypeRouter{
+		attribute:           config.Routing.Attribute,
+		sourceTypeAttribute: config.HecToOtelAttrs.SourceType,
+		routes:              make([]sourceTypeRoute, 0, len(config.Routing.Routes)),
+		defaultRoute:        config.Routing.DefaultRoute,
+	}
+	for i, route := range config.Routing.Routes {
+		if route.Route == "" {
+			return nil, fmt.Errorf("routing route %d must specify a route", i)
+		}
+		sourceType, err := regexp.Compile(route.SourceTypePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid routing sourcetype_pattern for route %q: %w", route.Route, err)
+		}
+		router.routes = append(router.routes, sourceTypeRoute{sourceType: sourceType, route: route.Route})
+	}
+	return router, nil
+}
+
+// route sets the route of the first route matching the sourcetype of
+// resource, or the default route, on resource.
+func (s *sourceTypeRouter) route(resource pcommon.Resource) {
+	attrs := resource.Attributes()
+	route := s.defaultRoute
+	if sourceType, ok := attrs.Get(s.sourceTypeAttribute); ok {
+		for _, candidate := range s.routes {
+			if candidate.sourceType.MatchString(sourceType.AsString()) {
+				route = candidate.route
+				break
+			}
+		}
+	}
+	if route != "" {
+		attrs.PutStr(s.attribute, route)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_splunkhecReceiver_routing(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Routing.Routes = []RouteConfig{
+		{SourceTypePattern: `^cisco:`, Route: "security"},
+		{SourceTypePattern: `^cisco:asa$`, Route: "unreachable"},
+		{SourceTypePattern: `^access_combined$`, Route: "web"},
+	}
+	config.Routing.DefaultRoute = "default"
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	body := `{"event":"a","sourcetype":"cisco:asa"}{"event":"b","sourcetype":"access_combined"}{"event":"c","sourcetype":"syslog"}{"event":"d"}`
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rls := sink.AllLogs()[0].ResourceLogs()
+	var routes []interface{}
+	for i := 0; i < rls.Len(); i++ {
+		routes = append(routes, rls.At(i).Resource().Attributes().AsRaw()["com.splunk.route"])
+	}
+	assert.Equal(t, []interface{}{"security", "web", "default", "default"}, routes)
+
+	w = httptest.NewRecorder()
+	r.handleRawReq(w, httptest.NewRequest("POST", "http://localhost/services/collector/raw?sourcetype=cisco:ftd", strings.NewReader("e")))
+	require.Equal(t, http.StatusOK, w.Code)
+	route, ok := sink.AllLogs()[1].ResourceLogs().At(0).Resource().Attributes().Get("com.splunk.route")
+	require.True(t, ok)
+	assert.Equal(t, "security", route.Str())
+}
+
+func Test_splunkhecReceiver_routingWithoutDefault(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Routing.Attribute = "route"
+	config.Routing.Routes = []RouteConfig{{SourceTypePattern: `^cisco:`, Route: "security"}}
+	sink := new(consumertest.MetricsSink)
+	rcv, err := newMetricsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	body := `{"event":"metric","sourcetype":"cisco:metrics","fields":{"metric_name":"m","_value":1}}{"event":"metric","fields":{"metric_name":"m","_value":2}}`
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+	route, ok := rms.At(0).Resource().Attributes().Get("route")
+	require.True(t, ok)
+	assert.Equal(t, "security", route.Str())
+	_, ok = rms.At(1).Resource().Attributes().Get("route")
+	assert.False(t, ok)
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+// Bounds of the HTTP/2 frame sizes, as defined by RFC 7540.
+const (
+	minHTTP2FrameSize = 1 << 14
+	maxHTTP2FrameSize = 1<<24 - 1
+)
+
+// configureServer applies the timeouts, limits and HTTP/2 settings of the
+// configuration to server.
+func configureServer(server *http.Server, config *Config) error {
+	server.ReadTimeout = config.ReadTimeout
+	server.ReadHeaderTimeout = config.ReadHeaderTimeout
+	server.WriteTimeout = config.WriteTimeout
+	server.IdleTimeout = config.IdleTimeout
+	server.MaxHeaderBytes = config.MaxHeaderBytes
+	server.SetKeepAlivesEnabled(!config.DisableKeepAlives)
+	if config.HTTP2.MaxConcurrentStreams == 0 && config.HTTP2.MaxReadFrameSize == 0 {
+		return nil
+	}
+	// HTTP/2 is served over TLS only, as negotiated by the listener.
+	return http2.ConfigureServer(server, &http2.Server{
+		MaxConcurrentStreams: config.HTTP2.MaxConcurrentStreams,
+		MaxReadFrameSize:     config.HTTP2.MaxReadFrameSize,
+		IdleTimeout:          config.IdleTimeout,
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func Test_configureServer(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.ReadTimeout = time.Minute
+	config.IdleTimeout = 2 * time.Minute
+	config.MaxHeaderBytes = 4096
+
+	server := &http.Server{}
+	require.NoError(t, configureServer(server, config))
+	assert.Equal(t, time.Minute, server.ReadTimeout)
+	assert.Equal(t, defaultServerTimeout, server.ReadHeaderTimeout)
+	assert.Equal(t, defaultServerTimeout, server.WriteTimeout)
+	assert.Equal(t, 2*time.Minute, server.IdleTimeout)
+	assert.Equal(t, 4096, server.MaxHeaderBytes)
+	assert.Nil(t, server.TLSNextProto, "HTTP/2 should keep its default settings")
+
+	config.HTTP2.MaxConcurrentStreams = 10
+	require.NoError(t, configureServer(server, config))
+	assert.Contains(t, server.TLSNextProto, "h2")
+}
+
+func Test_splunkhecReceiver_disableKeepAlives(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.DisableKeepAlives = true
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	resp, err := http.Post("http://"+config.Endpoint+"/services/collector", "application/json", strings.NewReader(`{"event":"foo"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, resp.Close, "the connection should be closed after the request")
+}
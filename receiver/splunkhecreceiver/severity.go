@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// defaultSeverityMapping maps common level names, and syslog severities, to
+// severity numbers.
+var defaultSeverityMapping = map[string]plog.SeverityNumber{
+	"trace":       plog.SeverityNumberTrace,
+	"debug":       plog.SeverityNumberDebug,
+	"info":        plog.SeverityNumberInfo,
+	"information": plog.SeverityNumberInfo,
+	"notice":      plog.SeverityNumberInfo2,
+	"warn":        plog.SeverityNumberWarn,
+	"warning":     plog.SeverityNumberWarn,
+	"err":         plog.SeverityNumberError,
+	"error":       plog.SeverityNumberError,
+	"crit":        plog.SeverityNumberError2,
+	"critical":    plog.SeverityNumberError2,
+	"alert":       plog.SeverityNumberError3,
+	"fatal":       plog.SeverityNumberFatal,
+	"emerg":       plog.SeverityNumberFatal,
+	"emergency":   plog.SeverityNumberFatal,
+	"panic":       plog.SeverityNumberFatal,
+	// Syslog severities, see RFC 5424.
+	"0": plog.SeverityNumberFatal,
+	"1": plog.SeverityNumberError3,
+	"2": plog.SeverityNumberError2,
+	"3": plog.SeverityNumberError,
+	"4": plog.SeverityNumberWarn,
+	"5": plog.SeverityNumberInfo2,
+	"6": plog.SeverityNumberInfo,
+	"7": plog.SeverityNumberDebug,
+}
+
+// severityMapper sets the severity of log records from a field of events.
+type severityMapper struct {
+	field   string
+	mapping map[string]plog.SeverityNumber
+}
+
+// newSeverityMapper returns the mapper configured by config, or nil when no
+// severity field is configured.
+func newSeverityMapper(config SeverityConfig) (*severityMapper, error) {
+	if config.Field == "" {
+		return nil, nil
+	}
+	mapper := &severityMapper{field: config.Field, mapping: defaultSeverityMapping}
+	if len(config.Mapping) == 0 {
+		return mapper, nil
+	}
+	mapper.mapping = make(map[string]plog.SeverityNumber, len(defaultSeverityMapping)+len(config.Mapping))
+	for value, number := range defaultSeverityMapping {
+		mapper.mapping[value] = number
+	}
+	for value, number := range config.Mapping {
+		if number < int(plog.SeverityNumberTrace) || number > int(plog.SeverityNumberFatal4) {
+			return nil, fmt.Errorf("severity mapping of %q must be between 1 and 24", value)
+		}
+		mapper.mapping[strings.ToLower(value)] = plog.SeverityNumber(number)
+	}
+	return mapper, nil
+}
+
+// setSeverity sets the severity of logRecord from the severity field of
+// fields, if any. The severity text is the value of the field, and the
+// severity number is left unset for values missing from the mapping.
+func (m *severityMapper) setSeverity(logRecord plog.LogRecord, fields map[string]interface{}) {
+	var value string
+	switch v := fields[m.field].(type) {
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return
+	}
+	logRecord.SetSeverityText(value)
+	if number, ok := m.mapping[strings.ToLower(value)]; ok {
+		logRecord.SetSeverityNumber(number)
+	}
+}
@@ -8,13 +8,16 @@ import (
 	"errors"
 	"io"
 	"net/url"
+	"regexp"
 	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
 )
 
 const (
@@ -23,99 +26,498 @@ const (
 	source     = "source"
 	sourcetype = "sourcetype"
 	host       = "host"
+
+	// rawEventAttr holds the original JSON of an event when enabled in the configuration.
+	rawEventAttr = splunk.RawEventLabel
 )
 
 var (
-	errCannotConvertValue = errors.New("cannot convert field value to attribute")
+	errCannotConvertValue       = errors.New("cannot convert field value to attribute")
+	errInvalidOtelFidelityField = errors.New("otel.fidelity field must hold exactly one log record")
+
+	otlpJSONUnmarshaler = &plog.JSONUnmarshaler{}
 )
 
-// splunkHecToLogData transforms splunk events into logs
-func splunkHecToLogData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config) (plog.Logs, error) {
-	ld := plog.NewLogs()
-	scopeLogsMap := make(map[[4]string]plog.ScopeLogs)
-	for _, event := range events {
-		key := [4]string{event.Host, event.Source, event.SourceType, event.Index}
-		var sl plog.ScopeLogs
-		var found bool
-		if sl, found = scopeLogsMap[key]; !found {
-			rl := ld.ResourceLogs().AppendEmpty()
-			sl = rl.ScopeLogs().AppendEmpty()
-			scopeLogsMap[key] = sl
-			appendSplunkMetadata(rl, config.HecToOtelAttrs, event.Host, event.Source, event.SourceType, event.Index)
-			if resourceCustomizer != nil {
-				resourceCustomizer(rl.Resource())
+// splunkHecToLogData transforms splunk events into logs. rawEvents, if not nil,
+// holds the original JSON of each event. observedTime is the time the events
+// were received at.
+func splunkHecToLogData(logger *zap.Logger, events []*splunk.Event, rawEvents [][]byte, resourceCustomizer func(pcommon.Resource), config *Config, observedTime pcommon.Timestamp) (plog.Logs, error) {
+	converter := newLogsConverter(logger, resourceCustomizer, config, observedTime)
+	if converter.restored != nil {
+		for _, event := range events {
+			if decoded, _, ok := decodeOtelFidelityRecord(logger, event, config); ok {
+				converter.restored[event] = decoded
+			}
+		}
+	}
+	err := converter.append(events, rawEvents)
+	return converter.ld, err
+}
+
+// logsConverter transforms splunk events into logs incrementally, so the
+// events of a request can be released as soon as they are converted rather
+// than once the whole request is.
+type logsConverter struct {
+	logger             *zap.Logger
+	resourceCustomizer func(pcommon.Resource)
+	config             *Config
+	observedTime       pcommon.Timestamp
+	placement          metadataPlacement
+	severity           *severityMapper
+	skippedFields      map[string]struct{}
+	// profilingResourceAttributes are the fields of profiling events set as
+	// resource attributes.
+	profilingResourceAttributes []string
+	ld                          plog.Logs
+	scopeLogsMap                map[[6]string]resourceScope
+	fidelityScopeLogsMap        map[fidelityKey]resourceScope
+	// restored, if not nil, holds the log records decoded from the otel.fidelity
+	// field of the events to convert. Other events are converted as regular events.
+	restored map[*splunk.Event]plog.Logs
+	// groups describes the events converted into each resource of ld.
+	groups []resourceGroup
+	// positions, if not nil, holds the position in the request of the events
+	// to convert. Events are otherwise numbered in the order they are appended.
+	positions map[*splunk.Event]int
+	appended  int
+}
+
+// resourceScope is the scope logs of a resource of the converted logs, along
+// with the index of the resource.
+type resourceScope struct {
+	sl    plog.ScopeLogs
+	group int
+}
+
+// resourceGroup describes the events converted into a resource.
+type resourceGroup struct {
+	// firstEvent is the position of the first event of the resource.
+	firstEvent int
+	// sourceTypes counts the events of the resource per sourcetype.
+	sourceTypes map[string]int
+}
+
+func newLogsConverter(logger *zap.Logger, resourceCustomizer func(pcommon.Resource), config *Config, observedTime pcommon.Timestamp) *logsConverter {
+	// The configuration is validated, so the severity mapping is valid.
+	severity, _ := newSeverityMapper(config.Severity)
+	// Fields promoted to resource attributes, or dropped, are not set as log record attributes.
+	skippedFields := make(map[string]struct{}, len(config.Fields.ResourceAttributes)+len(config.Fields.Drop))
+	for _, field := range config.Fields.ResourceAttributes {
+		skippedFields[field] = struct{}{}
+	}
+	for _, field := range config.Fields.Drop {
+		skippedFields[field] = struct{}{}
+	}
+	var profilingResourceAttributes []string
+	if config.Profiling.Enabled {
+		profilingResourceAttributes = append(append(profilingResourceAttributes, config.Fields.ResourceAttributes...), config.Profiling.ResourceAttributes...)
+	}
+	converter := &logsConverter{
+		logger:                      logger,
+		resourceCustomizer:          resourceCustomizer,
+		config:                      config,
+		observedTime:                observedTime,
+		placement:                   newMetadataPlacement(config.RecordMetadata),
+		severity:                    severity,
+		skippedFields:               skippedFields,
+		profilingResourceAttributes: profilingResourceAttributes,
+		ld:                          plog.NewLogs(),
+		scopeLogsMap:                make(map[[6]string]resourceScope),
+		fidelityScopeLogsMap:        make(map[fidelityKey]resourceScope),
+	}
+	if config.OtelFidelity {
+		converter.restored = make(map[*splunk.Event]plog.Logs)
+	}
+	return converter
+}
+
+// append converts events, and appends the produced log records to the ones
+// of previously appended events sharing their metadata. rawEvents, if not
+// nil, holds the original JSON of each event.
+func (c *logsConverter) append(events []*splunk.Event, rawEvents [][]byte) error {
+	logger, config := c.logger, c.config
+	for i, event := range events {
+		position := c.position(event)
+		if decoded, ok := c.restored[event]; ok {
+			delete(c.restored, event)
+			logRecord, group := appendOtelFidelityRecord(c.ld, c.fidelityScopeLogsMap, decoded, c.resourceCustomizer)
+			c.track(group, position, event.SourceType)
+			// Restored timestamps are kept, only missing ones are set.
+			if logRecord.ObservedTimestamp() == 0 {
+				logRecord.SetObservedTimestamp(c.observedTime)
+			}
+			if logRecord.Timestamp() == 0 && config.UseReceiveTimeOnMissing {
+				logRecord.SetTimestamp(c.observedTime)
+			}
+			appendRawEvent(logger, logRecord, rawEvents, i, config)
+			continue
+		}
+
+		// Profiling data is kept in its own scope, so that it is told apart from
+		// regular logs downstream.
+		profiling := isProfilingEvent(event, config)
+		resourceAttributes := config.Fields.ResourceAttributes
+		var scopeKey string
+		if profiling {
+			resourceAttributes = c.profilingResourceAttributes
+			scopeKey = profilingScopeName
+		}
+		resourceMetadata, recordMetadata := c.placement.split(event.Host, event.Source, event.SourceType, event.Index)
+		resourceFields, resourceFieldsKey, err := buildResourceFields(logger, event.Fields, resourceAttributes)
+		if err != nil {
+			return err
+		}
+		key := [6]string{resourceMetadata[0], resourceMetadata[1], resourceMetadata[2], resourceMetadata[3], resourceFieldsKey, scopeKey}
+		scope, found := c.scopeLogsMap[key]
+		if !found {
+			rl := c.ld.ResourceLogs().AppendEmpty()
+			scope = resourceScope{sl: rl.ScopeLogs().AppendEmpty(), group: c.ld.ResourceLogs().Len() - 1}
+			if profiling {
+				scope.sl.Scope().SetName(profilingScopeName)
+			} else {
+				setScope(scope.sl, config)
+			}
+			c.scopeLogsMap[key] = scope
+			resourceFields.CopyTo(rl.Resource().Attributes())
+			appendSplunkMetadata(rl, config.HecToOtelAttrs, key[0], key[1], key[2], key[3])
+			if c.resourceCustomizer != nil {
+				c.resourceCustomizer(rl.Resource())
 			}
 		}
 
+		c.track(scope.group, position, event.SourceType)
+
 		// The SourceType field is the most logical "name" of the event.
-		logRecord := sl.LogRecords().AppendEmpty()
-		if err := convertToValue(logger, event.Event, logRecord.Body()); err != nil {
-			return ld, err
+		logRecord := scope.sl.LogRecords().AppendEmpty()
+		body := event.Event
+		// The encoded payload of profiling events is kept as is.
+		if config.ParseJSONEvents && !profiling {
+			body = parseJSONObject(body)
+		}
+		if err := convertToValue(logger, body, logRecord.Body()); err != nil {
+			return err
 		}
 
 		// Splunk timestamps are in seconds so convert to nanos by multiplying
-		// by 1 billion.
-		logRecord.SetTimestamp(pcommon.Timestamp(event.Time * 1e9))
+		// by 1 billion, unless the precise nanoseconds are sent in a field.
+		nanosField := config.Timestamp.NanosecondsField
+		nanos, precise := preciseNanoseconds(event, nanosField)
+		if precise {
+			logRecord.SetTimestamp(pcommon.Timestamp(nanos))
+		} else {
+			logRecord.SetTimestamp(pcommon.Timestamp(event.Time * 1e9))
+		}
+		setObservedTime(logRecord, config, c.observedTime)
 
 		// Set event fields first, so the specialized attributes overwrite them if needed.
 		keys := make([]string, 0, len(event.Fields))
 		for k := range event.Fields {
-			keys = append(keys, k)
+			if precise && k == nanosField {
+				continue
+			}
+			if profiling && containsString(c.profilingResourceAttributes, k) {
+				continue
+			}
+			if _, skipped := c.skippedFields[k]; !skipped {
+				keys = append(keys, k)
+			}
 		}
 		sort.Strings(keys)
 		for _, key := range keys {
 			val := event.Fields[key]
 			err := convertToValue(logger, val, logRecord.Attributes().PutEmpty(key))
 			if err != nil {
-				return ld, err
+				return err
 			}
 		}
+		putSplunkMetadata(logRecord.Attributes(), config.HecToOtelAttrs, recordMetadata[0], recordMetadata[1], recordMetadata[2], recordMetadata[3])
+		if c.severity != nil {
+			c.severity.setSeverity(logRecord, event.Fields)
+		}
+
+		appendRawEvent(logger, logRecord, rawEvents, i, config)
+	}
+
+	return nil
+}
+
+// position returns the position of event, forgetting it as events are only
+// converted once.
+func (c *logsConverter) position(event *splunk.Event) int {
+	position := c.appended
+	c.appended++
+	if p, ok := c.positions[event]; ok {
+		position = p
+		delete(c.positions, event)
+	}
+	return position
+}
+
+// take returns the logs converted so far, along with the groups of events
+// converted into their resources, and resets the converter so that the next
+// events are converted into new logs.
+func (c *logsConverter) take() (plog.Logs, []resourceGroup) {
+	ld, groups := c.ld, c.groups
+	c.ld = plog.NewLogs()
+	c.groups = nil
+	c.scopeLogsMap = make(map[[6]string]resourceScope)
+	c.fidelityScopeLogsMap = make(map[fidelityKey]resourceScope)
+	return ld, groups
+}
+
+// track records that the event at position, of sourceType, was converted into
+// the resource group.
+func (c *logsConverter) track(group int, position int, sourceType string) {
+	if group == len(c.groups) {
+		c.groups = append(c.groups, resourceGroup{firstEvent: position, sourceTypes: map[string]int{}})
+	}
+	c.groups[group].sourceTypes[sourceType]++
+}
+
+// buildResourceFields converts the fields of an event promoted to resource
+// attributes. It also returns a key identifying their values, so that only
+// events sharing them are grouped in a resource.
+func buildResourceFields(logger *zap.Logger, fields map[string]interface{}, resourceAttributes []string) (pcommon.Map, string, error) {
+	attributes := pcommon.NewMap()
+	var key strings.Builder
+	for _, name := range resourceAttributes {
+		val, ok := fields[name]
+		if !ok || val == nil {
+			continue
+		}
+		if _, ok = attributes.Get(name); ok {
+			continue
+		}
+		dest := attributes.PutEmpty(name)
+		if err := convertToValue(logger, val, dest); err != nil {
+			return attributes, "", err
+		}
+		key.WriteString(name)
+		key.WriteByte(0)
+		key.WriteString(dest.AsString())
+		key.WriteByte(0)
+	}
+	return attributes, key.String(), nil
+}
+
+// appendRawEvent attaches the original JSON of the i-th event, if preserved, to logRecord.
+func appendRawEvent(logger *zap.Logger, logRecord plog.LogRecord, rawEvents [][]byte, i int, config *Config) {
+	if i >= len(rawEvents) {
+		return
+	}
+	if len(rawEvents[i]) > config.RawEvent.MaxSize {
+		logger.Debug("Original event exceeds raw_event max_size, not preserving it", zap.Int("size", len(rawEvents[i])))
+		return
+	}
+	logRecord.Attributes().PutStr(rawEventAttr, string(rawEvents[i]))
+}
+
+// fidelityKey identifies the resource and scope of log records restored from their OTLP encoding.
+type fidelityKey struct {
+	resource     [16]byte
+	scope        [16]byte
+	scopeName    string
+	scopeVersion string
+}
+
+// decodeOtelFidelityRecord decodes the log record encoded by the Splunk HEC
+// exporter in the otel.fidelity field of event, removing the fields dropped by
+// the configuration from its attributes and the ones of its resource. It also
+// returns the index of the record, empty if it has none. ok is false when the
+// event has no such field, or when it cannot be decoded, in which case the
+// event is converted as a regular event.
+func decodeOtelFidelityRecord(logger *zap.Logger, event *splunk.Event, config *Config) (decoded plog.Logs, index string, ok bool) {
+	encoded, ok := event.Fields[splunk.OtelFidelityField].(string)
+	if !ok {
+		return plog.Logs{}, "", false
+	}
+	decoded, err := otlpJSONUnmarshaler.UnmarshalLogs([]byte(encoded))
+	if err == nil && decoded.LogRecordCount() != 1 {
+		err = errInvalidOtelFidelityField
+	}
+	if err != nil {
+		logger.Debug("Cannot decode the OTLP encoding of the event, converting it as a regular event", zap.Error(err))
+		return plog.Logs{}, "", false
+	}
+	rl := decoded.ResourceLogs().At(0)
+	lr := rl.ScopeLogs().At(0).LogRecords().At(0)
+	for _, field := range config.Fields.Drop {
+		rl.Resource().Attributes().Remove(field)
+		lr.Attributes().Remove(field)
+	}
+	// Like the Splunk HEC exporter, the index of the record takes precedence over the one of its resource.
+	for _, attrs := range []pcommon.Map{rl.Resource().Attributes(), lr.Attributes()} {
+		if v, found := attrs.Get(config.HecToOtelAttrs.Index); found {
+			index = v.Str()
+		}
 	}
+	return decoded, index, true
+}
 
-	return ld, nil
+// appendOtelFidelityRecord appends the log record decoded from the otel.fidelity
+// field of an event to ld, grouped with the records sharing the same resource
+// and scope. It returns the appended record along with the index of its
+// resource.
+func appendOtelFidelityRecord(ld plog.Logs, scopeLogsMap map[fidelityKey]resourceScope, decoded plog.Logs, resourceCustomizer func(pcommon.Resource)) (plog.LogRecord, int) {
+	rl := decoded.ResourceLogs().At(0)
+	sl := rl.ScopeLogs().At(0)
+	key := fidelityKey{
+		resource:     pdatautil.MapHash(rl.Resource().Attributes()),
+		scope:        pdatautil.MapHash(sl.Scope().Attributes()),
+		scopeName:    sl.Scope().Name(),
+		scopeVersion: sl.Scope().Version(),
+	}
+	target, found := scopeLogsMap[key]
+	if !found {
+		targetRl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().CopyTo(targetRl.Resource())
+		// The resource is customized once restored, so that the attributes set by the
+		// receiver take precedence over the ones sent by the client.
+		if resourceCustomizer != nil {
+			resourceCustomizer(targetRl.Resource())
+		}
+		target = resourceScope{sl: targetRl.ScopeLogs().AppendEmpty(), group: ld.ResourceLogs().Len() - 1}
+		sl.Scope().CopyTo(target.sl.Scope())
+		scopeLogsMap[key] = target
+	}
+	logRecord := target.sl.LogRecords().AppendEmpty()
+	sl.LogRecords().At(0).MoveTo(logRecord)
+	return logRecord, target.group
 }
 
-// splunkHecRawToLogData transforms raw splunk event into log
-func splunkHecRawToLogData(bodyReader io.Reader, query url.Values, resourceCustomizer func(pcommon.Resource), config *Config) (plog.Logs, int, error) {
+// splunkHecRawToLogData transforms raw splunk event into log. When splitting
+// by line, lines are broken by lineBreaker instead of newlines, if not nil,
+// and lines continuing a previous line according to multiline, if not nil, are
+// merged into a single log record. The timestamps of log records are extracted
+// from their body by timeExtractor, if not nil. observedTime is the time the
+// event was received at.
+func splunkHecRawToLogData(bodyReader io.Reader, query url.Values, resourceCustomizer func(pcommon.Resource), config *Config, multiline *multilineRule, lineBreaker *regexp.Regexp, timeExtractor *timestampExtractor, observedTime pcommon.Timestamp) (plog.Logs, int, error) {
 	ld := plog.NewLogs()
 	rl := ld.ResourceLogs().AppendEmpty()
-	appendSplunkMetadata(rl, config.HecToOtelAttrs, query.Get(host), query.Get(source), query.Get(sourcetype), query.Get(index))
+	resourceMetadata, recordMetadata := newMetadataPlacement(config.RecordMetadata).split(query.Get(host), query.Get(source), query.Get(sourcetype), query.Get(index))
+	appendSplunkMetadata(rl, config.HecToOtelAttrs, resourceMetadata[0], resourceMetadata[1], resourceMetadata[2], resourceMetadata[3])
 	if resourceCustomizer != nil {
 		resourceCustomizer(rl.Resource())
 	}
 	sl := rl.ScopeLogs().AppendEmpty()
+	setScope(sl, config)
 	if config.Splitting == SplittingStrategyNone {
 		b, err := io.ReadAll(bodyReader)
 		if err != nil {
 			return ld, 0, err
 		}
 		logRecord := sl.LogRecords().AppendEmpty()
-		logRecord.Body().SetStr(string(b))
+		if err = setRawBody(logRecord, string(b), config); err != nil {
+			return ld, 0, err
+		}
+		if t, ok := timeExtractor.extract(string(b), observedTime.AsTime()); ok {
+			logRecord.SetTimestamp(pcommon.NewTimestampFromTime(t))
+		}
+		setObservedTime(logRecord, config, observedTime)
+		putSplunkMetadata(logRecord.Attributes(), config.HecToOtelAttrs, recordMetadata[0], recordMetadata[1], recordMetadata[2], recordMetadata[3])
 	} else {
 		sc := bufio.NewScanner(bodyReader)
-		for sc.Scan() {
+		if lineBreaker != nil {
+			sc.Split(splitEvents(lineBreaker))
+		}
+		merger := lineMerger{rule: multiline}
+		appendLine := func(logLine string) error {
 			logRecord := sl.LogRecords().AppendEmpty()
-			logLine := sc.Text()
-			logRecord.Body().SetStr(logLine)
+			if err := setRawBody(logRecord, logLine, config); err != nil {
+				return err
+			}
+			if t, ok := timeExtractor.extract(logLine, observedTime.AsTime()); ok {
+				logRecord.SetTimestamp(pcommon.NewTimestampFromTime(t))
+			}
+			setObservedTime(logRecord, config, observedTime)
+			putSplunkMetadata(logRecord.Attributes(), config.HecToOtelAttrs, recordMetadata[0], recordMetadata[1], recordMetadata[2], recordMetadata[3])
+			return nil
+		}
+		for sc.Scan() {
+			if logLine, ok := merger.add(sc.Text()); ok {
+				if err := appendLine(logLine); err != nil {
+					return ld, sl.LogRecords().Len() - 1, err
+				}
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return ld, 0, err
+		}
+		if logLine, ok := merger.flush(); ok {
+			if err := appendLine(logLine); err != nil {
+				return ld, sl.LogRecords().Len() - 1, err
+			}
 		}
 	}
 
 	return ld, sl.LogRecords().Len(), nil
 }
 
+// setObservedTime sets the observed timestamp of logRecord, and its timestamp
+// as well when missing and configured to.
+func setObservedTime(logRecord plog.LogRecord, config *Config, observedTime pcommon.Timestamp) {
+	logRecord.SetObservedTimestamp(observedTime)
+	if logRecord.Timestamp() == 0 && config.UseReceiveTimeOnMissing {
+		logRecord.SetTimestamp(observedTime)
+	}
+}
+
+func setScope(sl plog.ScopeLogs, config *Config) {
+	sl.Scope().SetName(config.Scope.Name)
+	sl.Scope().SetVersion(config.Scope.Version)
+}
+
 func appendSplunkMetadata(rl plog.ResourceLogs, attrs splunk.HecToOtelAttrs, host, source, sourceType, index string) {
+	putSplunkMetadata(rl.Resource().Attributes(), attrs, host, source, sourceType, index)
+}
+
+func putSplunkMetadata(m pcommon.Map, attrs splunk.HecToOtelAttrs, host, source, sourceType, index string) {
 	if host != "" {
-		rl.Resource().Attributes().PutStr(attrs.Host, host)
+		m.PutStr(attrs.Host, host)
 	}
 	if source != "" {
-		rl.Resource().Attributes().PutStr(attrs.Source, source)
+		m.PutStr(attrs.Source, source)
 	}
 	if sourceType != "" {
-		rl.Resource().Attributes().PutStr(attrs.SourceType, sourceType)
+		m.PutStr(attrs.SourceType, sourceType)
 	}
 	if index != "" {
-		rl.Resource().Attributes().PutStr(attrs.Index, index)
+		m.PutStr(attrs.Index, index)
+	}
+}
+
+// metadataPlacement tells which of the host, source, sourcetype and index of
+// events are set as log record attributes rather than resource attributes.
+type metadataPlacement [4]bool
+
+func newMetadataPlacement(recordMetadata []string) metadataPlacement {
+	var placement metadataPlacement
+	for _, field := range recordMetadata {
+		switch field {
+		case host:
+			placement[0] = true
+		case source:
+			placement[1] = true
+		case sourcetype:
+			placement[2] = true
+		case index:
+			placement[3] = true
+		}
+	}
+	return placement
+}
+
+// split returns the host, source, sourcetype and index set as resource
+// attributes, and the ones set as log record attributes, in this order.
+func (p metadataPlacement) split(host, source, sourceType, index string) (resource [4]string, record [4]string) {
+	for i, value := range [4]string{host, source, sourceType, index} {
+		if p[i] {
+			record[i] = value
+		} else {
+			resource[i] = value
+		}
 	}
+	return resource, record
 }
 
 func convertToValue(logger *zap.Logger, src interface{}, dest pcommon.Value) error {
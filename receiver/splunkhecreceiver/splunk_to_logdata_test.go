@@ -5,7 +5,10 @@ package splunkhecreceiver
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -330,7 +333,7 @@ func Test_SplunkHecToLogData(t *testing.T) {
 	n := len(tests)
 	for _, tt := range tests[n-1:] {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := splunkHecToLogData(zap.NewNop(), tt.events, func(resource pcommon.Resource) {}, tt.hecConfig)
+			result, err := splunkHecToLogData(zap.NewNop(), tt.events, nil, func(resource pcommon.Resource) {}, tt.hecConfig, 0)
 			assert.Equal(t, tt.wantErr, err)
 			require.Equal(t, tt.output.Len(), result.ResourceLogs().Len())
 			for i := 0; i < result.ResourceLogs().Len(); i++ {
@@ -466,13 +469,230 @@ func Test_SplunkHecRawToLogData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, slLen, err := splunkHecRawToLogData(tt.sc, tt.query, func(resource pcommon.Resource) {}, tt.config)
+			result, slLen, err := splunkHecRawToLogData(tt.sc, tt.query, func(resource pcommon.Resource) {}, tt.config, nil, nil, nil, 0)
 			require.NoError(t, err)
 			tt.assertResource(t, result, slLen)
 		})
 	}
 }
 
+func Test_SplunkHecToLogData_scope(t *testing.T) {
+	config := &Config{
+		HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs,
+		Scope:          ScopeConfig{Name: "myscope", Version: "1.2.3"},
+	}
+
+	ld, err := splunkHecToLogData(zap.NewNop(), []*splunk.Event{{Event: "foo"}}, nil, nil, config, 0)
+	require.NoError(t, err)
+	scope := ld.ResourceLogs().At(0).ScopeLogs().At(0).Scope()
+	assert.Equal(t, "myscope", scope.Name())
+	assert.Equal(t, "1.2.3", scope.Version())
+
+	ld, _, err = splunkHecRawToLogData(strings.NewReader("foo"), nil, nil, config, nil, nil, nil, 0)
+	require.NoError(t, err)
+	scope = ld.ResourceLogs().At(0).ScopeLogs().At(0).Scope()
+	assert.Equal(t, "myscope", scope.Name())
+	assert.Equal(t, "1.2.3", scope.Version())
+}
+
+func Test_SplunkHecToLogData_observedTime(t *testing.T) {
+	observedTime := pcommon.Timestamp(2e9)
+	for _, useReceiveTime := range []bool{false, true} {
+		t.Run(fmt.Sprintf("use_receive_time_on_missing_%t", useReceiveTime), func(t *testing.T) {
+			config := &Config{
+				HecToOtelAttrs:          defaultTestingHecConfig.HecToOtelAttrs,
+				UseReceiveTimeOnMissing: useReceiveTime,
+			}
+			wantMissingTime := pcommon.Timestamp(0)
+			if useReceiveTime {
+				wantMissingTime = observedTime
+			}
+
+			ld, err := splunkHecToLogData(zap.NewNop(), []*splunk.Event{{Event: "foo", Time: 1}, {Event: "bar"}}, nil, nil, config, observedTime)
+			require.NoError(t, err)
+			records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			assert.Equal(t, pcommon.Timestamp(1e9), records.At(0).Timestamp())
+			assert.Equal(t, observedTime, records.At(0).ObservedTimestamp())
+			assert.Equal(t, wantMissingTime, records.At(1).Timestamp())
+			assert.Equal(t, observedTime, records.At(1).ObservedTimestamp())
+
+			ld, _, err = splunkHecRawToLogData(strings.NewReader("foo"), nil, nil, config, nil, nil, nil, observedTime)
+			require.NoError(t, err)
+			record := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			assert.Equal(t, wantMissingTime, record.Timestamp())
+			assert.Equal(t, observedTime, record.ObservedTimestamp())
+		})
+	}
+}
+
+func Test_SplunkHecToLogData_recordMetadata(t *testing.T) {
+	config := &Config{
+		HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs,
+		RecordMetadata: []string{"source", "index"},
+	}
+	events := []*splunk.Event{
+		{Event: "foo", Host: "h", Source: "s1", SourceType: "st", Index: "i1"},
+		{Event: "bar", Host: "h", Source: "s2", SourceType: "st", Fields: map[string]interface{}{"com.splunk.source": "field"}},
+	}
+	ld, err := splunkHecToLogData(zap.NewNop(), events, nil, nil, config, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, ld.ResourceLogs().Len())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"host.name":             "h",
+		"com.splunk.sourcetype": "st",
+	}, rl.Resource().Attributes().AsRaw())
+	records := rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, map[string]interface{}{
+		"com.splunk.source": "s1",
+		"com.splunk.index":  "i1",
+	}, records.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{
+		"com.splunk.source": "s2",
+	}, records.At(1).Attributes().AsRaw())
+
+	ld, _, err = splunkHecRawToLogData(strings.NewReader("foo\nbar"), url.Values{"host": {"h"}, "source": {"s"}}, nil, config, nil, nil, nil, 0)
+	require.NoError(t, err)
+	rl = ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{"host.name": "h"}, rl.Resource().Attributes().AsRaw())
+	records = rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	for i := 0; i < records.Len(); i++ {
+		assert.Equal(t, map[string]interface{}{"com.splunk.source": "s"}, records.At(i).Attributes().AsRaw())
+	}
+}
+
+func Test_SplunkHecToLogData_otelFidelityReceiveTime(t *testing.T) {
+	encode := func(timestamp, observed pcommon.Timestamp) string {
+		ld := plog.NewLogs()
+		lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.SetTimestamp(timestamp)
+		lr.SetObservedTimestamp(observed)
+		b, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+		require.NoError(t, err)
+		return string(b)
+	}
+	events := []*splunk.Event{
+		{Event: "restored", Fields: map[string]interface{}{splunk.OtelFidelityField: encode(1, 2)}},
+		{Event: "missing", Fields: map[string]interface{}{splunk.OtelFidelityField: encode(0, 0)}},
+	}
+	for _, useReceiveTime := range []bool{false, true} {
+		config := &Config{HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs, UseReceiveTimeOnMissing: useReceiveTime, OtelFidelity: true}
+		ld, err := splunkHecToLogData(zap.NewNop(), events, nil, nil, config, 42)
+		require.NoError(t, err)
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		require.Equal(t, 2, records.Len())
+		assert.Equal(t, pcommon.Timestamp(1), records.At(0).Timestamp())
+		assert.Equal(t, pcommon.Timestamp(2), records.At(0).ObservedTimestamp())
+		assert.Equal(t, pcommon.Timestamp(42), records.At(1).ObservedTimestamp())
+		if useReceiveTime {
+			assert.Equal(t, pcommon.Timestamp(42), records.At(1).Timestamp())
+		} else {
+			assert.Equal(t, pcommon.Timestamp(0), records.At(1).Timestamp())
+		}
+	}
+}
+
+func Test_SplunkHecToLogData_fields(t *testing.T) {
+	config := &Config{
+		HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs,
+		Fields: FieldsConfig{
+			ResourceAttributes: []string{"k8s.pod.name", "service.name"},
+			Drop:               []string{"debug"},
+		},
+	}
+	events := []*splunk.Event{
+		{Event: "a", Host: "h", Fields: map[string]interface{}{"k8s.pod.name": "p1", "debug": "x", "user": "u"}},
+		{Event: "b", Host: "h", Fields: map[string]interface{}{"k8s.pod.name": "p2", "service.name": "s"}},
+		{Event: "c", Host: "h", Fields: map[string]interface{}{"k8s.pod.name": "p1"}},
+	}
+	ld, err := splunkHecToLogData(zap.NewNop(), events, nil, nil, config, 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"host.name":    "h",
+		"k8s.pod.name": "p1",
+	}, rl.Resource().Attributes().AsRaw())
+	records := rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, map[string]interface{}{"user": "u"}, records.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{}, records.At(1).Attributes().AsRaw())
+
+	rl = ld.ResourceLogs().At(1)
+	assert.Equal(t, map[string]interface{}{
+		"host.name":    "h",
+		"k8s.pod.name": "p2",
+		"service.name": "s",
+	}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, 0, rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().Len())
+}
+
+func Test_SplunkHecToLogData_severity(t *testing.T) {
+	config := &Config{
+		HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs,
+		Severity:       SeverityConfig{Field: "level", Mapping: map[string]int{"Verbose": 5}},
+	}
+	events := []*splunk.Event{
+		{Event: "a", Fields: map[string]interface{}{"level": "WARN"}},
+		{Event: "b", Fields: map[string]interface{}{"level": 3.0}},
+		{Event: "c", Fields: map[string]interface{}{"level": "verbose"}},
+		{Event: "d", Fields: map[string]interface{}{"level": "unknown"}},
+		{Event: "e", Fields: map[string]interface{}{"level": true}},
+		{Event: "f"},
+	}
+	ld, err := splunkHecToLogData(zap.NewNop(), events, nil, nil, config, 0)
+	require.NoError(t, err)
+	records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, len(events), records.Len())
+	expected := []struct {
+		text   string
+		number plog.SeverityNumber
+	}{
+		{"WARN", plog.SeverityNumberWarn},
+		{"3", plog.SeverityNumberError},
+		{"verbose", plog.SeverityNumberDebug},
+		{"unknown", plog.SeverityNumberUnspecified},
+		{"", plog.SeverityNumberUnspecified},
+		{"", plog.SeverityNumberUnspecified},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.text, records.At(i).SeverityText(), i)
+		assert.Equal(t, want.number, records.At(i).SeverityNumber(), i)
+	}
+}
+
+func Test_SplunkHecToLogData_parseJSONEvents(t *testing.T) {
+	events := []*splunk.Event{
+		{Event: ` {"msg":"started","pid":42,"ratio":0.5,"tags":["a"]}`},
+		{Event: `{"msg":`},
+		{Event: `["not","an","object"]`},
+		{Event: "plain"},
+	}
+	for _, enabled := range []bool{false, true} {
+		config := &Config{HecToOtelAttrs: defaultTestingHecConfig.HecToOtelAttrs, ParseJSONEvents: enabled}
+		ld, err := splunkHecToLogData(zap.NewNop(), events, nil, nil, config, 0)
+		require.NoError(t, err)
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		require.Equal(t, len(events), records.Len())
+		if enabled {
+			assert.Equal(t, map[string]interface{}{
+				"msg":   "started",
+				"pid":   int64(42),
+				"ratio": 0.5,
+				"tags":  []interface{}{"a"},
+			}, records.At(0).Body().AsRaw())
+		} else {
+			assert.Equal(t, events[0].Event, records.At(0).Body().AsRaw())
+		}
+		for i := 1; i < records.Len(); i++ {
+			assert.Equal(t, events[i].Event, records.At(i).Body().AsRaw())
+		}
+	}
+}
+
 func updateResourceMap(pmap pcommon.Map, host, source, sourcetype, index string) {
 	pmap.PutStr("host.name", host)
 	pmap.PutStr("com.splunk.source", source)
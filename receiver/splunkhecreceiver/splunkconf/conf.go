@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkconf // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkconf"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultStanza holds the settings set before any stanza.
+const defaultStanza = "default"
+
+// stanza is a section of a Splunk configuration file.
+type stanza struct {
+	name string
+	// keys lists the keys of settings in the order they are set.
+	keys     []string
+	settings map[string]string
+}
+
+func (s *stanza) set(key, value string) {
+	if _, ok := s.settings[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.settings[key] = value
+}
+
+// parseConf parses a Splunk configuration file, returning its stanzas in
+// order. Settings of a stanza repeated in the file are merged, the last value
+// of a setting winning, as done by Splunk.
+// See https://docs.splunk.com/Documentation/Splunk/latest/Admin/Howtoeditaconfigurationfile.
+func parseConf(reader io.Reader) ([]*stanza, error) {
+	var stanzas []*stanza
+	byName := make(map[string]*stanza)
+	current := func(name string) *stanza {
+		if s, ok := byName[name]; ok {
+			return s
+		}
+		s := &stanza{name: name, settings: make(map[string]string)}
+		byName[name] = s
+		stanzas = append(stanzas, s)
+		return s
+	}
+
+	var section *stanza
+	sc := bufio.NewScanner(reader)
+	lineNumber := 0
+	for sc.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(sc.Text())
+		// A trailing backslash continues the line on the next one.
+		for strings.HasSuffix(line, `\`) && sc.Scan() {
+			lineNumber++
+			line = strings.TrimSuffix(line, `\`) + "\n" + strings.TrimSpace(sc.Text())
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = current(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a stanza or a key = value setting: %q", lineNumber, line)
+		}
+		if section == nil {
+			section = current(defaultStanza)
+		}
+		section.set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return stanzas, nil
+}
+
+// parseBool parses a Splunk boolean setting.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "1", "true", "t", "yes", "y":
+		return true, nil
+	case "0", "false", "f", "no", "n":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
@@ -0,0 +1,263 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkconf // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkconf"
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// httpStanza is the inputs.conf stanza of the global HEC settings.
+	httpStanza = "http"
+	// tokenStanzaPrefix prefixes the inputs.conf stanzas of HEC tokens.
+	tokenStanzaPrefix = "http://"
+	// receiverKey is the key of the receiver in the collector configuration.
+	receiverKey = "splunk_hec"
+)
+
+// lineBreakerLookahead matches LINE_BREAKER settings breaking on newlines
+// followed by a pattern, such as ([\r\n]+)(?=\d{4}-) or ([\r\n]+)\d{4}-.
+var lineBreakerLookahead = regexp.MustCompile(`^\(\[\\r\\n\]\+\)(?:\(\?=(.+)\)|(.+))$`)
+
+// Receiver is the configuration of the Splunk HEC receiver converted from
+// Splunk configuration files.
+type Receiver struct {
+	Endpoint  string               `yaml:"endpoint,omitempty"`
+	Ack       *Ack                 `yaml:"ack,omitempty"`
+	Tokens    []Token              `yaml:"tokens,omitempty"`
+	Multiline map[string]Multiline `yaml:"multiline,omitempty"`
+}
+
+// Ack is the indexer acknowledgment configuration of the receiver.
+type Ack struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Token is a HEC token of the receiver.
+type Token struct {
+	Token      string   `yaml:"token"`
+	Index      string   `yaml:"index,omitempty"`
+	Indexes    []string `yaml:"indexes,omitempty"`
+	SourceType string   `yaml:"sourcetype,omitempty"`
+	Disabled   bool     `yaml:"disabled,omitempty"`
+}
+
+// Multiline is the configuration merging the lines of a sourcetype into events.
+type Multiline struct {
+	LineStartPattern string `yaml:"line_start_pattern"`
+	MaxLines         int    `yaml:"max_lines,omitempty"`
+}
+
+// Result is the outcome of a conversion.
+type Result struct {
+	// Receiver is the converted receiver configuration.
+	Receiver Receiver
+	// Warnings lists the settings that could not be converted, or whose
+	// behavior differs in the receiver.
+	Warnings []string
+}
+
+// YAML returns the receiver configuration, keyed by the receiver type so that
+// it can be pasted in the receivers section of a collector configuration.
+func (r *Result) YAML() ([]byte, error) {
+	return yaml.Marshal(map[string]Receiver{receiverKey: r.Receiver})
+}
+
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Convert converts the HEC stanzas of an inputs.conf file, and the line
+// breaking settings of the sourcetypes of a props.conf file, to the
+// configuration of the receiver. props is optional and can be nil.
+func Convert(inputs io.Reader, props io.Reader) (*Result, error) {
+	inputStanzas, err := parseConf(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse inputs.conf: %w", err)
+	}
+	result := &Result{}
+	for _, s := range inputStanzas {
+		switch {
+		case s.name == httpStanza:
+			if err = result.convertHTTP(s); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(s.name, tokenStanzaPrefix):
+			if err = result.convertToken(s); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if props == nil {
+		return result, nil
+	}
+	propsStanzas, err := parseConf(props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse props.conf: %w", err)
+	}
+	for _, s := range propsStanzas {
+		if err = result.convertProps(s); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// convertHTTP converts the global HEC settings.
+func (r *Result) convertHTTP(s *stanza) error {
+	for _, key := range s.keys {
+		value := s.settings[key]
+		switch key {
+		case "port":
+			if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+				return fmt.Errorf("[%s] invalid port %q", s.name, value)
+			}
+			r.Receiver.Endpoint = ":" + value
+		case "enableSSL":
+			enabled, err := parseBool(value)
+			if err != nil {
+				return fmt.Errorf("[%s] %s: %w", s.name, key, err)
+			}
+			if enabled {
+				r.warnf("[%s] %s: TLS must be configured with the tls settings of the receiver", s.name, key)
+			}
+		case "disabled":
+			disabled, err := parseBool(value)
+			if err != nil {
+				return fmt.Errorf("[%s] %s: %w", s.name, key, err)
+			}
+			if disabled {
+				r.warnf("[%s] %s: HEC is disabled, the receiver should not be added to a pipeline", s.name, key)
+			}
+		default:
+			r.warnf("[%s] %s is not converted", s.name, key)
+		}
+	}
+	return nil
+}
+
+// convertToken converts the stanza of a HEC token.
+func (r *Result) convertToken(s *stanza) error {
+	token := Token{Token: s.settings["token"]}
+	if token.Token == "" {
+		r.warnf("[%s] has no token and is skipped", s.name)
+		return nil
+	}
+	for _, key := range s.keys {
+		value := s.settings[key]
+		switch key {
+		case "token":
+		case "index":
+			token.Index = value
+		case "indexes":
+			for _, index := range strings.Split(value, ",") {
+				if index = strings.TrimSpace(index); index != "" {
+					token.Indexes = append(token.Indexes, index)
+				}
+			}
+		case "sourcetype":
+			token.SourceType = value
+		case "disabled":
+			disabled, err := parseBool(value)
+			if err != nil {
+				return fmt.Errorf("[%s] %s: %w", s.name, key, err)
+			}
+			token.Disabled = disabled
+		case "useACK":
+			enabled, err := parseBool(value)
+			if err != nil {
+				return fmt.Errorf("[%s] %s: %w", s.name, key, err)
+			}
+			if enabled {
+				r.Receiver.Ack = &Ack{Enabled: true}
+				r.warnf("[%s] %s: indexer acknowledgment is enabled for all the tokens of the receiver", s.name, key)
+			}
+		default:
+			r.warnf("[%s] %s is not converted", s.name, key)
+		}
+	}
+	// Splunk allows the default index of a token even when not listed.
+	if len(token.Indexes) > 0 && token.Index != "" && !contains(token.Indexes, token.Index) {
+		token.Indexes = append(token.Indexes, token.Index)
+	}
+	r.Receiver.Tokens = append(r.Receiver.Tokens, token)
+	return nil
+}
+
+// convertProps converts the line breaking settings of a sourcetype.
+func (r *Result) convertProps(s *stanza) error {
+	if s.name == defaultStanza || strings.Contains(s.name, "::") {
+		r.warnf("[%s] only sourcetype stanzas are converted", s.name)
+		return nil
+	}
+
+	var multiline Multiline
+	var breakOnlyBefore, lineBreaker string
+	lineMerge := true
+	for _, key := range s.keys {
+		value := s.settings[key]
+		switch key {
+		case "SHOULD_LINEMERGE":
+			merge, err := parseBool(value)
+			if err != nil {
+				return fmt.Errorf("[%s] %s: %w", s.name, key, err)
+			}
+			lineMerge = merge
+		case "BREAK_ONLY_BEFORE":
+			breakOnlyBefore = value
+		case "LINE_BREAKER":
+			match := lineBreakerLookahead.FindStringSubmatch(value)
+			if match == nil {
+				r.warnf("[%s] %s %q is not converted, only newlines followed by a pattern are supported", s.name, key, value)
+				continue
+			}
+			lineBreaker = match[1] + match[2]
+		case "MAX_EVENTS":
+			maxLines, err := strconv.Atoi(value)
+			if err != nil || maxLines < 0 {
+				return fmt.Errorf("[%s] invalid %s %q", s.name, key, value)
+			}
+			multiline.MaxLines = maxLines
+		default:
+			r.warnf("[%s] %s is not converted", s.name, key)
+		}
+	}
+	// BREAK_ONLY_BEFORE is only used when merging lines, and then takes
+	// precedence over the lines broken by LINE_BREAKER.
+	multiline.LineStartPattern = lineBreaker
+	if lineMerge && breakOnlyBefore != "" {
+		multiline.LineStartPattern = breakOnlyBefore
+	}
+	if multiline.LineStartPattern == "" {
+		return nil
+	}
+	if !strings.HasPrefix(multiline.LineStartPattern, "^") {
+		multiline.LineStartPattern = "^" + multiline.LineStartPattern
+	}
+	if _, err := regexp.Compile(multiline.LineStartPattern); err != nil {
+		r.warnf("[%s] line start pattern %q is not a valid regular expression and is skipped: %v", s.name, multiline.LineStartPattern, err)
+		return nil
+	}
+	if r.Receiver.Multiline == nil {
+		r.Receiver.Multiline = make(map[string]Multiline)
+	}
+	r.Receiver.Multiline[s.name] = multiline
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
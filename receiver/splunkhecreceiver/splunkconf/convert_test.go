@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkconf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	inputs, err := os.Open(filepath.Join("testdata", "inputs.conf"))
+	require.NoError(t, err)
+	defer inputs.Close()
+	props, err := os.Open(filepath.Join("testdata", "props.conf"))
+	require.NoError(t, err)
+	defer props.Close()
+
+	result, err := Convert(inputs, props)
+	require.NoError(t, err)
+	got, err := result.YAML()
+	require.NoError(t, err)
+	expected, err := os.ReadFile(filepath.Join("testdata", "expected.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(got))
+	assert.Equal(t, []string{
+		"[http] enableSSL: TLS must be configured with the tls settings of the receiver",
+		"[http] dedicatedIoThreads is not converted",
+		"[http://app] useACK: indexer acknowledgment is enabled for all the tokens of the receiver",
+		"[http://missing] has no token and is skipped",
+		"[default] only sourcetype stanzas are converted",
+		"[java:log] TIME_FORMAT is not converted",
+		`[custom] LINE_BREAKER "(;)" is not converted, only newlines followed by a pattern are supported`,
+		"[source::/var/log/app.log] only sourcetype stanzas are converted",
+	}, result.Warnings)
+}
+
+func TestConvert_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs string
+		props  string
+		err    string
+	}{
+		{
+			name:   "invalid_line",
+			inputs: "[http]\nport\n",
+			err:    `failed to parse inputs.conf: line 2: expected a stanza or a key = value setting: "port"`,
+		},
+		{
+			name:   "invalid_port",
+			inputs: "[http]\nport = http\n",
+			err:    `[http] invalid port "http"`,
+		},
+		{
+			name:   "invalid_bool",
+			inputs: "[http://a]\ntoken = a\ndisabled = maybe\n",
+			err:    `[http://a] disabled: invalid boolean "maybe"`,
+		},
+		{
+			name:  "invalid_max_events",
+			props: "[app]\nMAX_EVENTS = -1\n",
+			err:   `[app] invalid MAX_EVENTS "-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Convert(strings.NewReader(tt.inputs), strings.NewReader(tt.props))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseConf(t *testing.T) {
+	stanzas, err := parseConf(strings.NewReader(`global = 1
+# comment
+[a]
+x = 1
+y = first \
+  second
+[b]
+[a]
+x = 2
+`))
+	require.NoError(t, err)
+	require.Len(t, stanzas, 3)
+	assert.Equal(t, &stanza{name: defaultStanza, keys: []string{"global"}, settings: map[string]string{"global": "1"}}, stanzas[0])
+	assert.Equal(t, &stanza{name: "a", keys: []string{"x", "y"}, settings: map[string]string{"x": "2", "y": "first \nsecond"}}, stanzas[1])
+	assert.Equal(t, &stanza{name: "b", settings: map[string]string{}}, stanzas[2])
+}
@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package splunkconf converts the HTTP Event Collector settings of Splunk
+// inputs.conf and props.conf files to the configuration of the Splunk HEC
+// receiver, easing the migration of existing HEC deployments to the collector.
+package splunkconf // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkconf"
@@ -5,6 +5,7 @@ package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,36 +17,47 @@ import (
 )
 
 // splunkHecToMetricsData converts Splunk HEC metric points to
-// pmetric.Metrics. Returning the converted data and the number of
-// dropped time series.
-func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config) (pmetric.Metrics, int) {
+// pmetric.Metrics, of the types declared by types. Returning the converted
+// data and the number of dropped time series.
+func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config, types metricTypeRules) (pmetric.Metrics, int) {
 	numDroppedTimeSeries := 0
 	md := pmetric.NewMetrics()
-	scopeMetricsMap := make(map[[4]string]pmetric.ScopeMetrics)
+	scopeMetricsMap := make(map[[5]string]pmetric.ScopeMetrics)
+	resourceDimensions := make(map[string]struct{}, len(config.Metrics.ResourceDimensions))
+	for _, dimension := range config.Metrics.ResourceDimensions {
+		resourceDimensions[dimension] = struct{}{}
+	}
 	for _, event := range events {
 		values := event.GetMetricValues()
+		// A multi-metric event fans out to one metric per metric_name:<name>
+		// field, all sharing its timestamp and dimensions. Names are sorted
+		// to produce the metrics in a stable order.
+		metricNames := make([]string, 0, len(values))
+		for metricName := range values {
+			metricNames = append(metricNames, metricName)
+		}
+		sort.Strings(metricNames)
 
-		labels := buildAttributes(event.Fields)
+		labels := buildAttributes(event.Fields, resourceDimensions)
+		resourceLabels, resourceLabelsKey := buildResourceAttributes(event.Fields, config.Metrics.ResourceDimensions)
+		pointTimestamp := convertTimestamp(event.Time)
 
 		metrics := pmetric.NewMetricSlice()
-		for metricName, metricValue := range values {
-			pointTimestamp := convertTimestamp(event.Time)
-			metric := pmetric.NewMetric()
-			metric.SetName(metricName)
-
-			switch v := metricValue.(type) {
+		for _, metricName := range metricNames {
+			metricType := types.typeOf(event.SourceType, metricName)
+			switch v := values[metricName].(type) {
 			case int64:
-				addIntGauge(metrics, metricName, v, pointTimestamp, labels)
+				addIntMetric(metrics, metricName, metricType, v, pointTimestamp, labels)
 			case *int64:
-				addIntGauge(metrics, metricName, *v, pointTimestamp, labels)
+				addIntMetric(metrics, metricName, metricType, *v, pointTimestamp, labels)
 			case float64:
-				addDoubleGauge(metrics, metricName, v, pointTimestamp, labels)
+				addDoubleMetric(metrics, metricName, metricType, v, pointTimestamp, labels)
 			case *float64:
-				addDoubleGauge(metrics, metricName, *v, pointTimestamp, labels)
+				addDoubleMetric(metrics, metricName, metricType, *v, pointTimestamp, labels)
 			case string:
-				convertString(logger, &numDroppedTimeSeries, metrics, metricName, pointTimestamp, v, labels)
+				convertString(logger, &numDroppedTimeSeries, metrics, metricName, metricType, pointTimestamp, v, labels)
 			case *string:
-				convertString(logger, &numDroppedTimeSeries, metrics, metricName, pointTimestamp, *v, labels)
+				convertString(logger, &numDroppedTimeSeries, metrics, metricName, metricType, pointTimestamp, *v, labels)
 			default:
 				// drop this point as we do not know how to extract a value from it
 				numDroppedTimeSeries++
@@ -57,7 +69,7 @@ func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resource
 		if metrics.Len() == 0 {
 			continue
 		}
-		key := [4]string{event.Host, event.Source, event.SourceType, event.Index}
+		key := [5]string{event.Host, event.Source, event.SourceType, event.Index, resourceLabelsKey}
 		var sm pmetric.ScopeMetrics
 		var found bool
 		if sm, found = scopeMetricsMap[key]; !found {
@@ -78,6 +90,10 @@ func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resource
 			if event.Index != "" {
 				attrs.PutStr(config.HecToOtelAttrs.Index, event.Index)
 			}
+			resourceLabels.Range(func(k string, v pcommon.Value) bool {
+				v.CopyTo(attrs.PutEmpty(k))
+				return true
+			})
 			if resourceCustomizer != nil {
 				resourceCustomizer(resourceMetrics.Resource())
 			}
@@ -88,7 +104,7 @@ func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resource
 	return md, numDroppedTimeSeries
 }
 
-func convertString(logger *zap.Logger, numDroppedTimeSeries *int, metrics pmetric.MetricSlice, metricName string, pointTimestamp pcommon.Timestamp, s string, attributes pcommon.Map) {
+func convertString(logger *zap.Logger, numDroppedTimeSeries *int, metrics pmetric.MetricSlice, metricName string, metricType string, pointTimestamp pcommon.Timestamp, s string, attributes pcommon.Map) {
 	// best effort, cast to string and turn into a number
 	dbl, err := strconv.ParseFloat(s, 64)
 	if err != nil {
@@ -96,23 +112,23 @@ func convertString(logger *zap.Logger, numDroppedTimeSeries *int, metrics pmetri
 		logger.Debug("Cannot convert metric value from string to number",
 			zap.String("metric", metricName))
 	} else {
-		addDoubleGauge(metrics, metricName, dbl, pointTimestamp, attributes)
+		addDoubleMetric(metrics, metricName, metricType, dbl, pointTimestamp, attributes)
 	}
 }
 
-func addIntGauge(metrics pmetric.MetricSlice, metricName string, value int64, ts pcommon.Timestamp, attributes pcommon.Map) {
+func addIntMetric(metrics pmetric.MetricSlice, metricName string, metricType string, value int64, ts pcommon.Timestamp, attributes pcommon.Map) {
 	metric := metrics.AppendEmpty()
 	metric.SetName(metricName)
-	intPt := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	intPt := setEmptyNumberDataPoints(metric, metricType).AppendEmpty()
 	intPt.SetTimestamp(ts)
 	intPt.SetIntValue(value)
 	attributes.CopyTo(intPt.Attributes())
 }
 
-func addDoubleGauge(metrics pmetric.MetricSlice, metricName string, value float64, ts pcommon.Timestamp, attributes pcommon.Map) {
+func addDoubleMetric(metrics pmetric.MetricSlice, metricName string, metricType string, value float64, ts pcommon.Timestamp, attributes pcommon.Map) {
 	metric := metrics.AppendEmpty()
 	metric.SetName(metricName)
-	doublePt := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	doublePt := setEmptyNumberDataPoints(metric, metricType).AppendEmpty()
 	doublePt.SetTimestamp(ts)
 	doublePt.SetDoubleValue(value)
 	attributes.CopyTo(doublePt.Attributes())
@@ -123,19 +139,51 @@ func convertTimestamp(sec float64) pcommon.Timestamp {
 }
 
 // Extract dimensions from the Splunk event fields to populate metric data point attributes.
-func buildAttributes(dimensions map[string]interface{}) pcommon.Map {
+// Dimensions promoted to resource attributes are skipped.
+func buildAttributes(dimensions map[string]interface{}, resourceDimensions map[string]struct{}) pcommon.Map {
 	attributes := pcommon.NewMap()
 	attributes.EnsureCapacity(len(dimensions))
+	_, singleMetric := dimensions[splunk.HecMetricNameField]
 	for key, val := range dimensions {
 
-		if strings.HasPrefix(key, "metric_name") {
+		if strings.HasPrefix(key, splunk.HecMetricNameField) {
+			continue
+		}
+		if singleMetric && key == splunk.HecMetricValueField {
 			continue
 		}
 		if key == "" || val == nil {
 			// TODO: Log or metric for this odd ball?
 			continue
 		}
+		if _, ok := resourceDimensions[key]; ok {
+			continue
+		}
 		attributes.PutStr(key, fmt.Sprintf("%v", val))
 	}
 	return attributes
 }
+
+// buildResourceAttributes extracts the dimensions promoted to resource
+// attributes from the Splunk event fields. It also returns a key identifying
+// their values, so that only metrics sharing them are grouped in a resource.
+func buildResourceAttributes(dimensions map[string]interface{}, resourceDimensions []string) (pcommon.Map, string) {
+	attributes := pcommon.NewMap()
+	var key strings.Builder
+	for _, name := range resourceDimensions {
+		val, ok := dimensions[name]
+		if !ok || val == nil || strings.HasPrefix(name, splunk.HecMetricNameField) {
+			continue
+		}
+		if _, ok = attributes.Get(name); ok {
+			continue
+		}
+		str := fmt.Sprintf("%v", val)
+		attributes.PutStr(name, str)
+		key.WriteString(name)
+		key.WriteByte(0)
+		key.WriteString(str)
+		key.WriteByte(0)
+	}
+	return attributes, key.String()
+}
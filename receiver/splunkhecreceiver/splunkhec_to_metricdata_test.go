@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
@@ -53,6 +54,18 @@ func Test_splunkV2ToMetricsData(t *testing.T) {
 			wantMetricsData: buildDefaultMetricsData(nanos),
 			hecConfig:       defaultTestingHecConfig,
 		},
+		{
+			name: "single_metric_format",
+			splunkDataPoint: func() *splunk.Event {
+				pt := buildDefaultSplunkDataPt()
+				delete(pt.Fields, "metric_name:single")
+				pt.Fields["metric_name"] = "single"
+				pt.Fields["_value"] = int64Ptr(13)
+				return pt
+			}(),
+			wantMetricsData: buildDefaultMetricsData(nanos),
+			hecConfig:       defaultTestingHecConfig,
+		},
 		{
 			name: "multiple",
 			splunkDataPoint: func() *splunk.Event {
@@ -293,13 +306,175 @@ func Test_splunkV2ToMetricsData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{tt.splunkDataPoint}, func(resource pcommon.Resource) {}, tt.hecConfig)
+			md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{tt.splunkDataPoint}, func(resource pcommon.Resource) {}, tt.hecConfig, nil)
 			assert.Equal(t, tt.wantDroppedTimeseries, numDroppedTimeseries)
 			assert.NoError(t, pmetrictest.CompareMetrics(tt.wantMetricsData, md, pmetrictest.IgnoreMetricsOrder()))
 		})
 	}
 }
 
+func Test_splunkV2ToMetricsData_multiMetricOrder(t *testing.T) {
+	pt := &splunk.Event{
+		Time:  1.5,
+		Event: "metric",
+		Fields: map[string]interface{}{
+			"metric_name:c": int64(3),
+			"metric_name:a": float64(1.5),
+			"metric_name:b": "2",
+			"k0":            "v0",
+			"k1":            "v1",
+		},
+	}
+
+	md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{pt}, nil, defaultTestingHecConfig, nil)
+	assert.Equal(t, 0, numDroppedTimeseries)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	mts := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	var names []string
+	for i := 0; i < mts.Len(); i++ {
+		names = append(names, mts.At(i).Name())
+		dp := mts.At(i).Gauge().DataPoints().At(0)
+		assert.Equal(t, pcommon.Timestamp(1.5e9), dp.Timestamp())
+		assert.Equal(t, map[string]interface{}{"k0": "v0", "k1": "v1"}, dp.Attributes().AsRaw())
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+}
+
+func Test_splunkV2ToMetricsData_types(t *testing.T) {
+	config := *defaultTestingHecConfig
+	config.Metrics.Types = []MetricTypeConfig{
+		{SourceType: "statsd", MetricNamePattern: `\.count$`, Type: metricTypeDelta},
+		{MetricNamePattern: "_total$", Type: metricTypeCumulative},
+		{MetricNamePattern: "^requests", Type: metricTypeDelta},
+	}
+	types, err := newMetricTypeRules(&config)
+	require.NoError(t, err)
+	pt := &splunk.Event{
+		Time:       1.5,
+		Event:      "metric",
+		SourceType: "statsd",
+		Fields: map[string]interface{}{
+			"metric_name:requests.count": int64(3),
+			"metric_name:requests_total": "10",
+			"metric_name:requests.size":  float64(1.5),
+			"metric_name:cpu":            float64(0.5),
+		},
+	}
+
+	md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{pt}, nil, &config, types)
+	assert.Equal(t, 0, numDroppedTimeseries)
+	mts := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 4, mts.Len())
+	assert.Equal(t, "cpu", mts.At(0).Name())
+	assert.Equal(t, pmetric.MetricTypeGauge, mts.At(0).Type())
+	for i, want := range []struct {
+		name        string
+		temporality pmetric.AggregationTemporality
+	}{
+		{name: "requests.count", temporality: pmetric.AggregationTemporalityDelta},
+		{name: "requests.size", temporality: pmetric.AggregationTemporalityDelta},
+		{name: "requests_total", temporality: pmetric.AggregationTemporalityCumulative},
+	} {
+		metric := mts.At(i + 1)
+		assert.Equal(t, want.name, metric.Name())
+		require.Equal(t, pmetric.MetricTypeSum, metric.Type())
+		assert.True(t, metric.Sum().IsMonotonic())
+		assert.Equal(t, want.temporality, metric.Sum().AggregationTemporality())
+		assert.Equal(t, pcommon.Timestamp(1.5e9), metric.Sum().DataPoints().At(0).Timestamp())
+	}
+
+	// Rules only match the events of their sourcetype.
+	pt.SourceType = "other"
+	md, _ = splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{pt}, nil, &config, types)
+	metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(1)
+	assert.Equal(t, "requests.count", metric.Name())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, metric.Sum().AggregationTemporality())
+	assert.Equal(t, int64(3), metric.Sum().DataPoints().At(0).IntValue())
+	assert.Equal(t, pmetric.MetricTypeGauge, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Type())
+}
+
+func Test_cumulativeStarts(t *testing.T) {
+	config := *defaultTestingHecConfig
+	config.Metrics.Types = []MetricTypeConfig{{MetricNamePattern: "_total$", Type: metricTypeCumulative}}
+	types, err := newMetricTypeRules(&config)
+	require.NoError(t, err)
+	starts := newCumulativeStarts(types)
+	require.NotNil(t, starts)
+	assert.Nil(t, newCumulativeStarts(nil))
+
+	now := time.Now()
+	// convert returns the start times of the requests_total points of the hosts, sent at sec with value.
+	convert := func(sec float64, value int64, hosts ...string) []pcommon.Timestamp {
+		var events []*splunk.Event
+		for _, host := range hosts {
+			events = append(events, &splunk.Event{Time: sec, Host: host, Event: "metric", Fields: map[string]interface{}{
+				"metric_name:requests_total": value,
+				"metric_name:cpu":            float64(0.5),
+			}})
+		}
+		md, _ := splunkHecToMetricsData(zap.NewNop(), events, nil, &config, types)
+		starts.set(md, now)
+		var startTimes []pcommon.Timestamp
+		for i := 0; i < md.ResourceMetrics().Len(); i++ {
+			metrics := md.ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics()
+			assert.Equal(t, pcommon.Timestamp(0), metrics.At(0).Gauge().DataPoints().At(0).StartTimestamp())
+			startTimes = append(startTimes, metrics.At(1).Sum().DataPoints().At(0).StartTimestamp())
+		}
+		return startTimes
+	}
+
+	assert.Equal(t, []pcommon.Timestamp{1e9}, convert(1, 10, "a"))
+	// Series start with their first point.
+	assert.Equal(t, []pcommon.Timestamp{1e9, 2e9}, convert(2, 20, "a", "b"))
+	// Series restart after a reset.
+	assert.Equal(t, []pcommon.Timestamp{3e9}, convert(3, 5, "a"))
+	assert.Equal(t, []pcommon.Timestamp{2e9}, convert(3, 25, "b"))
+
+	// Series not received for a while are forgotten.
+	now = now.Add(cumulativeSeriesTTL)
+	assert.Equal(t, []pcommon.Timestamp{3e9}, convert(4, 30, "a"))
+	now = now.Add(cumulativeSeriesTTL)
+	assert.Len(t, starts.series, 1)
+	assert.Equal(t, []pcommon.Timestamp{5e9}, convert(5, 40, "b"))
+}
+
+func Test_splunkV2ToMetricsData_resourceDimensions(t *testing.T) {
+	config := *defaultTestingHecConfig
+	config.Metrics.ResourceDimensions = []string{"k8s.pod.name", "missing", "metric_name"}
+	newEvent := func(pod string, value int64) *splunk.Event {
+		return &splunk.Event{
+			Time:  1.5,
+			Host:  "localhost",
+			Event: "metric",
+			Fields: map[string]interface{}{
+				"metric_name":  "cpu",
+				"_value":       value,
+				"k8s.pod.name": pod,
+				"k0":           "v0",
+			},
+		}
+	}
+
+	md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), []*splunk.Event{newEvent("a", 1), newEvent("b", 2), newEvent("a", 3)}, nil, &config, nil)
+	assert.Equal(t, 0, numDroppedTimeseries)
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+	for i, want := range []struct {
+		pod    string
+		values []int64
+	}{{pod: "a", values: []int64{1, 3}}, {pod: "b", values: []int64{2}}} {
+		rm := md.ResourceMetrics().At(i)
+		assert.Equal(t, map[string]interface{}{"host.name": "localhost", "k8s.pod.name": want.pod}, rm.Resource().Attributes().AsRaw())
+		mts := rm.ScopeMetrics().At(0).Metrics()
+		require.Equal(t, len(want.values), mts.Len())
+		for j, value := range want.values {
+			assert.Equal(t, "cpu", mts.At(j).Name())
+			dp := mts.At(j).Gauge().DataPoints().At(0)
+			assert.Equal(t, value, dp.IntValue())
+			assert.Equal(t, map[string]interface{}{"k0": "v0"}, dp.Attributes().AsRaw())
+		}
+	}
+}
+
 func TestGroupMetricsByResource(t *testing.T) {
 	// Timestamps for Splunk have a resolution to the millisecond, where the time is reported in seconds with a floating value to the millisecond.
 	now := time.Now()
@@ -439,7 +614,7 @@ func TestGroupMetricsByResource(t *testing.T) {
 		dataPt.SetTimestamp(pcommon.Timestamp(nanoseconds))
 		dataPt.Attributes().PutStr("field", "value2-1")
 	}
-	md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), events, func(resource pcommon.Resource) {}, defaultTestingHecConfig)
+	md, numDroppedTimeseries := splunkHecToMetricsData(zap.NewNop(), events, func(resource pcommon.Resource) {}, defaultTestingHecConfig, nil)
 	assert.Equal(t, 0, numDroppedTimeseries)
 	assert.EqualValues(t, metrics, md)
 }
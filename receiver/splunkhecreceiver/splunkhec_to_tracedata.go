@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+var spanKinds = map[string]ptrace.SpanKind{
+	"SPAN_KIND_UNSPECIFIED": ptrace.SpanKindUnspecified,
+	"SPAN_KIND_INTERNAL":    ptrace.SpanKindInternal,
+	"SPAN_KIND_SERVER":      ptrace.SpanKindServer,
+	"SPAN_KIND_CLIENT":      ptrace.SpanKindClient,
+	"SPAN_KIND_PRODUCER":    ptrace.SpanKindProducer,
+	"SPAN_KIND_CONSUMER":    ptrace.SpanKindConsumer,
+}
+
+var statusCodes = map[string]ptrace.StatusCode{
+	"STATUS_CODE_UNSET": ptrace.StatusCodeUnset,
+	"STATUS_CODE_OK":    ptrace.StatusCodeOk,
+	"STATUS_CODE_ERROR": ptrace.StatusCodeError,
+}
+
+// isSpanEvent reports whether event holds a span, in the format of the spans
+// sent by the Splunk HEC exporter and Splunk tracing libraries: an object with
+// trace_id, span_id, start_time and end_time keys. Only the events of the
+// configured sourcetypes, if any, are considered.
+func isSpanEvent(event *splunk.Event, config *Config) bool {
+	if len(config.Traces.SourceTypes) > 0 && !containsString(config.Traces.SourceTypes, event.SourceType) {
+		return false
+	}
+	span, ok := event.Event.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok = span["trace_id"].(string); !ok {
+		return false
+	}
+	if _, ok = span["span_id"].(string); !ok {
+		return false
+	}
+	_, hasStart := span["start_time"]
+	_, hasEnd := span["end_time"]
+	return hasStart && hasEnd
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// splunkHecToTracesData converts the span events to ptrace.Traces. The fields
+// of the events become the attributes of the resources of their spans, along
+// with their host, source, sourcetype and index.
+func splunkHecToTracesData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config) (ptrace.Traces, error) {
+	td := ptrace.NewTraces()
+	scopeSpansMap := make(map[[5]string]ptrace.ScopeSpans)
+	for i, event := range events {
+		keys := make([]string, 0, len(event.Fields))
+		for k := range event.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var fieldsKey strings.Builder
+		for _, k := range keys {
+			fieldsKey.WriteString(k)
+			fieldsKey.WriteByte(0)
+			fieldsKey.WriteString(fmt.Sprint(event.Fields[k]))
+			fieldsKey.WriteByte(0)
+		}
+		key := [5]string{event.Host, event.Source, event.SourceType, event.Index, fieldsKey.String()}
+		ss, found := scopeSpansMap[key]
+		if !found {
+			rs := td.ResourceSpans().AppendEmpty()
+			attrs := rs.Resource().Attributes()
+			for _, k := range keys {
+				if err := convertToValue(logger, event.Fields[k], attrs.PutEmpty(k)); err != nil {
+					return td, err
+				}
+			}
+			putSplunkMetadata(attrs, config.HecToOtelAttrs, event.Host, event.Source, event.SourceType, event.Index)
+			if resourceCustomizer != nil {
+				resourceCustomizer(rs.Resource())
+			}
+			ss = rs.ScopeSpans().AppendEmpty()
+			ss.Scope().SetName(config.Scope.Name)
+			ss.Scope().SetVersion(config.Scope.Version)
+			scopeSpansMap[key] = ss
+		}
+		if err := convertSpan(logger, event.Event.(map[string]interface{}), ss.Spans().AppendEmpty()); err != nil {
+			return td, fmt.Errorf("invalid span of event %d: %w", i, err)
+		}
+	}
+	return td, nil
+}
+
+func convertSpan(logger *zap.Logger, src map[string]interface{}, span ptrace.Span) error {
+	traceID, err := parseTraceID(src["trace_id"])
+	if err != nil {
+		return err
+	}
+	span.SetTraceID(traceID)
+	spanID, err := parseSpanID(src["span_id"])
+	if err != nil {
+		return err
+	}
+	span.SetSpanID(spanID)
+	if parentSpanID, ok := src["parent_span_id"].(string); ok && parentSpanID != "" {
+		id, err := parseSpanID(parentSpanID)
+		if err != nil {
+			return err
+		}
+		span.SetParentSpanID(id)
+	}
+	span.SetName(stringOf(src["name"]))
+	span.SetKind(spanKinds[stringOf(src["kind"])])
+	span.SetStartTimestamp(timestampOf(src["start_time"]))
+	span.SetEndTimestamp(timestampOf(src["end_time"]))
+	if status, ok := src["status"].(map[string]interface{}); ok {
+		span.Status().SetCode(statusCodes[stringOf(status["code"])])
+		span.Status().SetMessage(stringOf(status["message"]))
+	}
+	if err = convertAttributes(logger, src["attributes"], span.Attributes()); err != nil {
+		return err
+	}
+	events, _ := src["events"].([]interface{})
+	for _, e := range events {
+		e, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		event := span.Events().AppendEmpty()
+		event.SetName(stringOf(e["name"]))
+		event.SetTimestamp(timestampOf(e["timestamp"]))
+		if err = convertAttributes(logger, e["attributes"], event.Attributes()); err != nil {
+			return err
+		}
+	}
+	links, _ := src["links"].([]interface{})
+	for _, l := range links {
+		l, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		link := span.Links().AppendEmpty()
+		if traceID, err = parseTraceID(l["trace_id"]); err != nil {
+			return err
+		}
+		link.SetTraceID(traceID)
+		if spanID, err = parseSpanID(l["span_id"]); err != nil {
+			return err
+		}
+		link.SetSpanID(spanID)
+		link.TraceState().FromRaw(stringOf(l["trace_state"]))
+		if err = convertAttributes(logger, l["attributes"], link.Attributes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertAttributes(logger *zap.Logger, src interface{}, dest pcommon.Map) error {
+	attributes, _ := src.(map[string]interface{})
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := convertToValue(logger, attributes[k], dest.PutEmpty(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseTraceID(v interface{}) (pcommon.TraceID, error) {
+	var id pcommon.TraceID
+	if err := decodeID(v, id[:]); err != nil {
+		return id, fmt.Errorf("invalid trace_id: %w", err)
+	}
+	return id, nil
+}
+
+func parseSpanID(v interface{}) (pcommon.SpanID, error) {
+	var id pcommon.SpanID
+	if err := decodeID(v, id[:]); err != nil {
+		return id, fmt.Errorf("invalid span_id: %w", err)
+	}
+	return id, nil
+}
+
+// decodeID decodes the hex string v into id, which must be exactly filled.
+func decodeID(v interface{}, id []byte) error {
+	s, _ := v.(string)
+	if hex.DecodedLen(len(s)) != len(id) {
+		return fmt.Errorf("%q is not %d hex encoded bytes", s, len(id))
+	}
+	_, err := hex.Decode(id, []byte(s))
+	return err
+}
+
+func stringOf(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// timestampOf returns the timestamp in nanoseconds held by v, as encoded by
+// the Splunk HEC exporter.
+func timestampOf(v interface{}) pcommon.Timestamp {
+	switch t := v.(type) {
+	case int64:
+		return pcommon.Timestamp(t)
+	case float64:
+		return pcommon.Timestamp(t)
+	}
+	return 0
+}
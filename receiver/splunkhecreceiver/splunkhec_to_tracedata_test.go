@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// spanEvent is a span event as sent by the Splunk HEC exporter.
+const spanEvent = `{"time":1.5,"host":"myhost","sourcetype":"otel:span","fields":{"service.name":"checkout"},` +
+	`"event":{"trace_id":"0102030405060708090a0b0c0d0e0f10","span_id":"0102030405060708","parent_span_id":"0807060504030201",` +
+	`"name":"GET /cart","attributes":{"http.status_code":200},"end_time":1500000002000000001,"kind":"SPAN_KIND_SERVER",` +
+	`"status":{"message":"oops","code":"STATUS_CODE_ERROR"},"start_time":1500000001000000001,` +
+	`"events":[{"attributes":{"k":"v"},"name":"retry","timestamp":1500000001500000000}],` +
+	`"links":[{"trace_id":"100f0e0d0c0b0a090807060504030201","span_id":"0807060504030201","trace_state":"a=b"}]}}`
+
+func TestIsSpanEvent(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	span := map[string]interface{}{"trace_id": "01", "span_id": "02", "start_time": int64(1), "end_time": int64(2)}
+	assert.True(t, isSpanEvent(&splunk.Event{Event: span}, config))
+	assert.False(t, isSpanEvent(&splunk.Event{Event: "GET /cart"}, config))
+	assert.False(t, isSpanEvent(&splunk.Event{Event: map[string]interface{}{"trace_id": "01", "span_id": "02"}}, config))
+
+	config.Traces.SourceTypes = []string{"otel:span"}
+	assert.False(t, isSpanEvent(&splunk.Event{Event: span}, config))
+	assert.True(t, isSpanEvent(&splunk.Event{Event: span, SourceType: "otel:span"}, config))
+}
+
+func Test_splunkHecToTracesData(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	var hecMsg hecEvent
+	require.NoError(t, hecJSON.UnmarshalFromString(spanEvent, &hecMsg))
+	var event splunk.Event
+	require.NoError(t, hecMsg.toEvent(&event, newTimestampParser(config.Timestamp)))
+	other := event
+	other.Host = "otherhost"
+
+	td, err := splunkHecToTracesData(zap.NewNop(), []*splunk.Event{&event, &other, &event}, nil, config)
+	require.NoError(t, err)
+	require.Equal(t, 2, td.ResourceSpans().Len())
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"service.name":          "checkout",
+		"host.name":             "myhost",
+		"com.splunk.sourcetype": "otel:span",
+	}, rs.Resource().Attributes().AsRaw())
+	assert.Equal(t, defaultScopeName, rs.ScopeSpans().At(0).Scope().Name())
+	require.Equal(t, 2, rs.ScopeSpans().At(0).Spans().Len())
+
+	span := rs.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), span.TraceID())
+	assert.Equal(t, pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}), span.SpanID())
+	assert.Equal(t, pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}), span.ParentSpanID())
+	assert.Equal(t, "GET /cart", span.Name())
+	assert.Equal(t, ptrace.SpanKindServer, span.Kind())
+	assert.Equal(t, pcommon.Timestamp(1500000001000000001), span.StartTimestamp())
+	assert.Equal(t, pcommon.Timestamp(1500000002000000001), span.EndTimestamp())
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "oops", span.Status().Message())
+	assert.Equal(t, map[string]interface{}{"http.status_code": int64(200)}, span.Attributes().AsRaw())
+	require.Equal(t, 1, span.Events().Len())
+	assert.Equal(t, "retry", span.Events().At(0).Name())
+	assert.Equal(t, pcommon.Timestamp(1500000001500000000), span.Events().At(0).Timestamp())
+	assert.Equal(t, map[string]interface{}{"k": "v"}, span.Events().At(0).Attributes().AsRaw())
+	require.Equal(t, 1, span.Links().Len())
+	assert.Equal(t, pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}), span.Links().At(0).SpanID())
+	assert.Equal(t, "a=b", span.Links().At(0).TraceState().AsRaw())
+
+	event.Event.(map[string]interface{})["span_id"] = "0102"
+	_, err = splunkHecToTracesData(zap.NewNop(), []*splunk.Event{&event}, nil, config)
+	assert.EqualError(t, err, `invalid span of event 0: invalid span_id: "0102" is not 8 hex encoded bytes`)
+}
+
+func Test_splunkhecReceiver_spans(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	logsSink := new(consumertest.LogsSink)
+	tracesSink := new(consumertest.TracesSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, logsSink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	send := func() *httptest.ResponseRecorder {
+		body := spanEvent + `{"event":"a log"}` + spanEvent
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		r.handleReq(w, req)
+		return w
+	}
+
+	// Spans are logged without a traces pipeline.
+	w := send()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, logsSink.LogRecordCount())
+
+	logsSink.Reset()
+	r.tracesConsumer = tracesSink
+	w = send()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, logsSink.LogRecordCount())
+	assert.Equal(t, 2, tracesSink.SpanCount())
+}
+
+func Test_splunkhecReceiver_spansOnly(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	sink := new(consumertest.TracesSink)
+	rcv, err := newTracesReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(spanEvent))
+	w := httptest.NewRecorder()
+	r.handleReq(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"text":"Success","code":0}`, w.Body.String())
+	assert.Equal(t, 1, sink.SpanCount())
+
+	req = httptest.NewRequest(http.MethodPost, "http://localhost/services/collector", strings.NewReader(`{"event":"a log"}`))
+	w = httptest.NewRecorder()
+	r.handleReq(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, string(errUnsupportedLogEvent), w.Body.String())
+}
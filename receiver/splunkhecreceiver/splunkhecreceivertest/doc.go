@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package splunkhecreceivertest provides helpers to build, start and exercise
+// the Splunk HEC receiver from tests, including in custom distributions that
+// embed it.
+package splunkhecreceivertest // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkhecreceivertest"
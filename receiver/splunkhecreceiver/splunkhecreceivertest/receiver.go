@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceivertest // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkhecreceivertest"
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+)
+
+// NewConfig returns the default receiver configuration bound to endpoint,
+// with each of the given modifiers applied in order.
+func NewConfig(endpoint string, modifiers ...func(*splunkhecreceiver.Config)) *splunkhecreceiver.Config {
+	cfg := splunkhecreceiver.NewFactory().CreateDefaultConfig().(*splunkhecreceiver.Config)
+	cfg.Endpoint = endpoint
+	for _, modify := range modifiers {
+		modify(cfg)
+	}
+	return cfg
+}
+
+// StartLogsReceiver creates and starts a logs receiver for cfg that forwards
+// all received data to an in-memory sink. The receiver is shut down when the
+// test completes.
+func StartLogsReceiver(t testing.TB, cfg *splunkhecreceiver.Config) (receiver.Logs, *consumertest.LogsSink) {
+	sink := new(consumertest.LogsSink)
+	rcv, err := splunkhecreceiver.NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	})
+	return rcv, sink
+}
+
+// StartMetricsReceiver creates and starts a metrics receiver for cfg that
+// forwards all received data to an in-memory sink. The receiver is shut down
+// when the test completes.
+func StartMetricsReceiver(t testing.TB, cfg *splunkhecreceiver.Config) (receiver.Metrics, *consumertest.MetricsSink) {
+	sink := new(consumertest.MetricsSink)
+	rcv, err := splunkhecreceiver.NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	})
+	return rcv, sink
+}
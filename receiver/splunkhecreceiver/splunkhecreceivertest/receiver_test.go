@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceivertest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg := NewConfig("localhost:1", func(cfg *splunkhecreceiver.Config) {
+		cfg.AccessTokenPassthrough = true
+	})
+	assert.Equal(t, "localhost:1", cfg.Endpoint)
+	assert.True(t, cfg.AccessTokenPassthrough)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestCreateReceivers(t *testing.T) {
+	cfg := NewConfig("localhost:1")
+	factory := splunkhecreceiver.NewFactory()
+
+	lr, err := factory.CreateLogsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, lr)
+
+	mr, err := factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mr)
+}
+
+func TestLogsReceiverLifecycle(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := StartLogsReceiver(t, NewConfig(endpoint))
+
+	req, err := NewEventRequest(endpoint, "token", map[string]interface{}{"event": "first"}, map[string]interface{}{"event": "second"})
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, err = NewRawRequest(endpoint, "token", url.Values{"sourcetype": []string{"raw"}}, "third\nfourth")
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 4
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMetricsReceiverLifecycle(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := StartMetricsReceiver(t, NewConfig(endpoint))
+
+	req, err := NewEventRequest(endpoint, "", map[string]interface{}{
+		"event":  "metric",
+		"fields": map[string]interface{}{"metric_name:cpu": 1.5},
+	})
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Eventually(t, func() bool {
+		return sink.DataPointCount() == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestAckLifecycle(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := StartLogsReceiver(t, NewConfig(endpoint, func(cfg *splunkhecreceiver.Config) {
+		cfg.Ack.Enabled = true
+	}))
+
+	req, err := NewEventRequest(endpoint, "token", map[string]interface{}{"event": "first"})
+	require.NoError(t, err)
+	req.Header.Set(channelHeader, "channel")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var eventResp struct {
+		AckID uint64 `json:"ackId"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&eventResp))
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, sink.LogRecordCount())
+
+	req, err = NewAckRequest(endpoint, "token", "channel", eventResp.AckID)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	var ackResp struct {
+		Acks map[string]bool `json:"acks"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ackResp))
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, map[string]bool{"0": true}, ackResp.Acks)
+}
+
+func TestNewAckRequest(t *testing.T) {
+	req, err := NewAckRequest("localhost:1", "token", "channel", 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/services/collector/ack", req.URL.Path)
+	assert.Equal(t, "channel", req.Header.Get("X-Splunk-Request-Channel"))
+	assert.Equal(t, "Splunk token", req.Header.Get("Authorization"))
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceivertest // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver/splunkhecreceivertest"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// EventPath is the default path of the HEC event endpoint.
+	EventPath = "/services/collector/event"
+	// RawPath is the default path of the HEC raw endpoint.
+	RawPath = "/services/collector/raw"
+	// AckPath is the default path of the HEC acknowledgement endpoint.
+	AckPath = "/services/collector/ack"
+
+	channelHeader = "X-Splunk-Request-Channel"
+)
+
+// NewEventRequest builds a POST request to the event endpoint of the receiver
+// listening on endpoint. Each event is serialized to JSON and the results are
+// concatenated, as HEC clients do when batching. If token is not empty it is
+// sent in the Authorization header.
+func NewEventRequest(endpoint, token string, events ...interface{}) (*http.Request, error) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return newRequest("http://"+endpoint+EventPath, token, &body)
+}
+
+// NewRawRequest builds a POST request to the raw endpoint of the receiver
+// listening on endpoint. query holds the optional host, source, sourcetype and
+// index parameters.
+func NewRawRequest(endpoint, token string, query url.Values, body string) (*http.Request, error) {
+	u := "http://" + endpoint + RawPath
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return newRequest(u, token, strings.NewReader(body))
+}
+
+// NewAckRequest builds a POST request querying the status of the given ack IDs
+// on channel.
+func NewAckRequest(endpoint, token, channel string, ackIDs ...uint64) (*http.Request, error) {
+	if ackIDs == nil {
+		ackIDs = []uint64{}
+	}
+	b, err := json.Marshal(map[string][]uint64{"acks": ackIDs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := newRequest("http://"+endpoint+AckPath, token, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(channelHeader, channel)
+	return req, nil
+}
+
+func newRequest(u, token string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Splunk "+token)
+	}
+	return req, nil
+}
@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// Units of the numeric times of events.
+const (
+	timeUnitSeconds      = "s"
+	timeUnitMilliseconds = "ms"
+	timeUnitMicroseconds = "us"
+	timeUnitNanoseconds  = "ns"
+	timeUnitAuto         = "auto"
+)
+
+// Handling of the events whose time is out of range.
+const (
+	outOfRangeReject = "reject"
+	outOfRangeClamp  = "clamp"
+
+	// originalTimeAttr is the attribute holding the original epoch seconds of
+	// the events whose time is clamped.
+	originalTimeAttr = "splunk.hec.original_time"
+)
+
+var errMissingExtractLayout = errors.New("timestamp extract layout must be specified")
+
+// timeUnitSecondsIn maps time units to the number of these units in a second.
+var timeUnitSecondsIn = map[string]float64{
+	timeUnitSeconds:      1,
+	timeUnitMilliseconds: 1e3,
+	timeUnitMicroseconds: 1e6,
+	timeUnitNanoseconds:  1e9,
+}
+
+// timestampParser converts the times of events to epoch seconds, the unit of
+// splunk.Event times.
+type timestampParser struct {
+	// perSecond is the number of units of numeric times in a second, or 0 to
+	// detect the unit of each time.
+	perSecond float64
+	layout    string
+	maxFuture time.Duration
+	maxPast   time.Duration
+	clamp     bool
+	// nanosecondsField is the field holding the precise time of events.
+	nanosecondsField string
+}
+
+func newTimestampParser(config TimestampConfig) *timestampParser {
+	perSecond := 1.0
+	switch config.Unit {
+	case "":
+	case timeUnitAuto:
+		perSecond = 0
+	default:
+		perSecond = timeUnitSecondsIn[config.Unit]
+	}
+	return &timestampParser{
+		perSecond: perSecond,
+		layout:    config.Layout,
+		maxFuture: config.MaxFuture,
+		maxPast:   config.MaxPast,
+		clamp:     config.OutOfRange == outOfRangeClamp,
+
+		nanosecondsField: config.NanosecondsField,
+	}
+}
+
+// fromNumber returns the epoch seconds of the numeric time t.
+func (p *timestampParser) fromNumber(t float64) float64 {
+	if p.perSecond != 0 {
+		return t / p.perSecond
+	}
+	// Times of the current era are in the 1e9 range in seconds, so larger
+	// values are detected as milliseconds, microseconds or nanoseconds.
+	switch {
+	case t >= 1e17:
+		return t / 1e9
+	case t >= 1e14:
+		return t / 1e6
+	case t >= 1e11:
+		return t / 1e3
+	}
+	return t
+}
+
+// fromString returns the epoch seconds of the string time t, either a number
+// or, when a layout is configured, a time formatted according to it.
+func (p *timestampParser) fromString(t string) (float64, error) {
+	number, err := strconv.ParseFloat(t, 64)
+	if err == nil {
+		return p.fromNumber(number), nil
+	}
+	if p.layout == "" {
+		return 0, err
+	}
+	parsed, err := time.Parse(p.layout, t)
+	if err != nil {
+		return 0, fmt.Errorf("time %q matches neither a number nor the layout %q: %w", t, p.layout, err)
+	}
+	return float64(parsed.UnixNano()) / 1e9, nil
+}
+
+// preciseTime sets the time of event to the epoch nanoseconds held by the
+// nanoseconds field, if any, normalizing the field to an int64 so that the
+// exact time is kept by the conversion to log records. Fields which are not
+// integers are left as is.
+func (p *timestampParser) preciseTime(event *splunk.Event) {
+	if p.nanosecondsField == "" {
+		return
+	}
+	var nanos int64
+	switch v := event.Fields[p.nanosecondsField].(type) {
+	case int64:
+		nanos = v
+	case string:
+		var err error
+		if nanos, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return
+		}
+		event.Fields[p.nanosecondsField] = nanos
+	default:
+		return
+	}
+	event.Time = float64(nanos) / 1e9
+}
+
+// preciseNanoseconds returns the epoch nanoseconds held by field of event, as
+// normalized by preciseTime.
+func preciseNanoseconds(event *splunk.Event, field string) (int64, bool) {
+	if field == "" {
+		return 0, false
+	}
+	nanos, ok := event.Fields[field].(int64)
+	return nanos, ok
+}
+
+// checkRange checks the time of event, received at receivedAt, is within the
+// configured range. Out of range times are set to receivedAt when clamping,
+// keeping the original time in the splunk.hec.original_time field, and are
+// otherwise reported by returning false. Events without time are in range.
+func (p *timestampParser) checkRange(event *splunk.Event, receivedAt time.Time) bool {
+	if event.Time == 0 || (p.maxFuture == 0 && p.maxPast == 0) {
+		return true
+	}
+	t := time.Unix(0, int64(event.Time*1e9))
+	if (p.maxFuture == 0 || !t.After(receivedAt.Add(p.maxFuture))) && (p.maxPast == 0 || !t.Before(receivedAt.Add(-p.maxPast))) {
+		return true
+	}
+	if !p.clamp {
+		return false
+	}
+	if event.Fields == nil {
+		event.Fields = map[string]interface{}{}
+	}
+	event.Fields[originalTimeAttr] = event.Time
+	if p.nanosecondsField != "" {
+		delete(event.Fields, p.nanosecondsField)
+	}
+	event.Time = float64(receivedAt.UnixNano()) / 1e9
+	return true
+}
+
+// timestampExtractor extracts the time of events from their text, like the
+// Splunk TIME_PREFIX and TIME_FORMAT settings.
+type timestampExtractor struct {
+	pattern  *regexp.Regexp
+	layout   string
+	location *time.Location
+}
+
+// newTimestampExtractor returns the extractor configured by config, or nil
+// when no pattern is configured.
+func newTimestampExtractor(config TimestampExtractConfig) (*timestampExtractor, error) {
+	if config.Pattern == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(config.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp extract pattern: %w", err)
+	}
+	if config.Layout == "" {
+		return nil, errMissingExtractLayout
+	}
+	location := time.UTC
+	if config.Location != "" {
+		if location, err = time.LoadLocation(config.Location); err != nil {
+			return nil, fmt.Errorf("invalid timestamp extract location: %w", err)
+		}
+	}
+	return &timestampExtractor{pattern: pattern, layout: config.Layout, location: location}, nil
+}
+
+// extract returns the time held by text, received at receivedAt, matched by
+// the first capturing group of the pattern, or by the whole match when it has
+// none. Times without year, such as syslog times, are set in the year they are
+// received, unless that puts them more than a day after receivedAt, when they
+// are set in the previous year. extract returns false when text holds no time,
+// as well as when e is nil.
+func (e *timestampExtractor) extract(text string, receivedAt time.Time) (time.Time, bool) {
+	if e == nil {
+		return time.Time{}, false
+	}
+	match := e.pattern.FindStringSubmatch(text)
+	if match == nil {
+		return time.Time{}, false
+	}
+	value := match[0]
+	if len(match) > 1 {
+		value = match[1]
+	}
+	t, err := time.ParseInLocation(e.layout, value, e.location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if t.Year() == 0 {
+		t = t.AddDate(receivedAt.Year(), 0, 0)
+		if t.After(receivedAt.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+	}
+	return t, true
+}
+
+// extractTime sets the time of string events without time to the time held by
+// their text, if any.
+func (e *timestampExtractor) extractTime(event *splunk.Event, receivedAt time.Time) {
+	if event.Time != 0 {
+		return
+	}
+	text, ok := event.Event.(string)
+	if !ok {
+		return
+	}
+	if t, ok := e.extract(text, receivedAt); ok {
+		event.Time = float64(t.UnixNano()) / 1e9
+	}
+}
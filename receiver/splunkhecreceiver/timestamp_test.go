@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_timestampParser_fromNumber(t *testing.T) {
+	tests := []struct {
+		unit string
+		time float64
+		want float64
+	}{
+		{unit: "", time: 1.5e9, want: 1.5e9},
+		{unit: timeUnitSeconds, time: 1.5e9, want: 1.5e9},
+		{unit: timeUnitMilliseconds, time: 1.5e12, want: 1.5e9},
+		{unit: timeUnitMicroseconds, time: 1.5e15, want: 1.5e9},
+		{unit: timeUnitNanoseconds, time: 1.5e18, want: 1.5e9},
+		{unit: timeUnitAuto, time: 1.5e9, want: 1.5e9},
+		{unit: timeUnitAuto, time: 1.5e12, want: 1.5e9},
+		{unit: timeUnitAuto, time: 1.5e15, want: 1.5e9},
+		{unit: timeUnitAuto, time: 1.5e18, want: 1.5e9},
+	}
+	for _, tt := range tests {
+		p := newTimestampParser(TimestampConfig{Unit: tt.unit})
+		assert.InDelta(t, tt.want, p.fromNumber(tt.time), 1e-3, "unit %q, time %v", tt.unit, tt.time)
+	}
+}
+
+func Test_timestampParser_fromString(t *testing.T) {
+	p := newTimestampParser(TimestampConfig{Unit: timeUnitMilliseconds, Layout: time.RFC3339Nano})
+
+	seconds, err := p.fromString("1500000000000")
+	require.NoError(t, err)
+	assert.Equal(t, 1.5e9, seconds)
+
+	seconds, err = p.fromString("2017-07-14T02:40:00.5Z")
+	require.NoError(t, err)
+	assert.Equal(t, 1.5000000005e9, seconds)
+
+	_, err = p.fromString("yesterday")
+	assert.EqualError(t, err, `time "yesterday" matches neither a number nor the layout "2006-01-02T15:04:05.999999999Z07:00": parsing time "yesterday" as "2006-01-02T15:04:05.999999999Z07:00": cannot parse "yesterday" as "2006"`)
+
+	_, err = newTimestampParser(TimestampConfig{}).fromString("2017-07-14T02:40:00Z")
+	assert.Error(t, err)
+}
+
+func Test_timestampParser_checkRange(t *testing.T) {
+	receivedAt := time.Unix(1.5e9, 0)
+	tests := []struct {
+		name       string
+		outOfRange string
+		time       float64
+		want       bool
+		wantTime   float64
+		wantFields map[string]interface{}
+	}{
+		{name: "missing", time: 0, want: true, wantTime: 0},
+		{name: "in_range", time: 1.5e9 - 3600, want: true, wantTime: 1.5e9 - 3600},
+		{name: "max_future", time: 1.5e9 + 3600, want: true, wantTime: 1.5e9 + 3600},
+		{name: "future", time: 1.5e9 + 3601, want: false, wantTime: 1.5e9 + 3601},
+		{name: "past", time: 1.5e9 - 86401, want: false, wantTime: 1.5e9 - 86401},
+		{
+			name:       "clamped_future",
+			outOfRange: outOfRangeClamp,
+			time:       1.5e9 + 3601,
+			want:       true,
+			wantTime:   1.5e9,
+			wantFields: map[string]interface{}{originalTimeAttr: 1.5e9 + 3601},
+		},
+		{
+			name:       "clamped_past",
+			outOfRange: outOfRangeClamp,
+			time:       1.5e9 - 86401,
+			want:       true,
+			wantTime:   1.5e9,
+			wantFields: map[string]interface{}{originalTimeAttr: 1.5e9 - 86401},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTimestampParser(TimestampConfig{MaxFuture: time.Hour, MaxPast: 24 * time.Hour, OutOfRange: tt.outOfRange})
+			event := &splunk.Event{Time: tt.time}
+			assert.Equal(t, tt.want, p.checkRange(event, receivedAt))
+			assert.Equal(t, tt.wantTime, event.Time)
+			assert.Equal(t, tt.wantFields, event.Fields)
+		})
+	}
+
+	// Times are not checked when no range is configured.
+	event := &splunk.Event{Time: 1}
+	assert.True(t, newTimestampParser(TimestampConfig{}).checkRange(event, receivedAt))
+	assert.Equal(t, float64(1), event.Time)
+}
+
+func Test_timestampParser_preciseTime(t *testing.T) {
+	p := newTimestampParser(TimestampConfig{NanosecondsField: "time_ns"})
+	tests := []struct {
+		name       string
+		fields     map[string]interface{}
+		wantTime   float64
+		wantFields map[string]interface{}
+	}{
+		{name: "missing", wantTime: 1.5e9},
+		{
+			name:       "number",
+			fields:     map[string]interface{}{"time_ns": int64(1600000000123456789)},
+			wantTime:   1600000000.123456789,
+			wantFields: map[string]interface{}{"time_ns": int64(1600000000123456789)},
+		},
+		{
+			name:       "string",
+			fields:     map[string]interface{}{"time_ns": "1600000000123456789"},
+			wantTime:   1600000000.123456789,
+			wantFields: map[string]interface{}{"time_ns": int64(1600000000123456789)},
+		},
+		{
+			name:       "not_integer",
+			fields:     map[string]interface{}{"time_ns": "soon"},
+			wantTime:   1.5e9,
+			wantFields: map[string]interface{}{"time_ns": "soon"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &splunk.Event{Time: 1.5e9, Fields: tt.fields}
+			p.preciseTime(event)
+			assert.Equal(t, tt.wantTime, event.Time)
+			assert.Equal(t, tt.wantFields, event.Fields)
+		})
+	}
+
+	// Clamped times drop the nanoseconds.
+	p = newTimestampParser(TimestampConfig{MaxPast: time.Hour, OutOfRange: outOfRangeClamp, NanosecondsField: "time_ns"})
+	event := &splunk.Event{Fields: map[string]interface{}{"time_ns": int64(1000000000123456789)}}
+	p.preciseTime(event)
+	assert.True(t, p.checkRange(event, time.Unix(1.5e9, 0)))
+	_, precise := preciseNanoseconds(event, "time_ns")
+	assert.False(t, precise)
+}
+
+func Test_timestampExtractor_extract(t *testing.T) {
+	receivedAt := time.Date(2023, time.January, 1, 0, 30, 0, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	tests := []struct {
+		name   string
+		config TimestampExtractConfig
+		text   string
+		want   time.Time
+		found  bool
+	}{
+		{
+			name:   "capturing_group",
+			config: TimestampExtractConfig{Pattern: `time=(\S+)`, Layout: time.RFC3339},
+			text:   "level=info time=2022-12-31T23:00:00+01:00 msg=done",
+			want:   time.Date(2022, time.December, 31, 22, 0, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name:   "whole_match",
+			config: TimestampExtractConfig{Pattern: `\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}`, Layout: "2006-01-02 15:04:05"},
+			text:   "[2022-12-31 12:00:00] done",
+			want:   time.Date(2022, time.December, 31, 12, 0, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name:   "location",
+			config: TimestampExtractConfig{Pattern: `^\S+ \S+`, Layout: "2006-01-02 15:04:05", Location: "Europe/Paris"},
+			text:   "2022-12-31 12:00:00 done",
+			want:   time.Date(2022, time.December, 31, 12, 0, 0, 0, paris),
+			found:  true,
+		},
+		{
+			name:   "syslog_current_year",
+			config: TimestampExtractConfig{Pattern: `^(\w{3} [ \d]\d \d{2}:\d{2}:\d{2})`, Layout: "Jan _2 15:04:05"},
+			text:   "Jan  1 00:15:00 host sshd[42]: accepted",
+			want:   time.Date(2023, time.January, 1, 0, 15, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name:   "syslog_previous_year",
+			config: TimestampExtractConfig{Pattern: `^(\w{3} [ \d]\d \d{2}:\d{2}:\d{2})`, Layout: "Jan _2 15:04:05"},
+			text:   "Dec 31 23:59:00 host sshd[42]: accepted",
+			want:   time.Date(2022, time.December, 31, 23, 59, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name:   "no_match",
+			config: TimestampExtractConfig{Pattern: `time=(\S+)`, Layout: time.RFC3339},
+			text:   "level=info msg=done",
+		},
+		{
+			name:   "layout_mismatch",
+			config: TimestampExtractConfig{Pattern: `time=(\S+)`, Layout: time.RFC3339},
+			text:   "time=yesterday",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newTimestampExtractor(tt.config)
+			require.NoError(t, err)
+			got, found := e.extract(tt.text, receivedAt)
+			assert.Equal(t, tt.found, found)
+			assert.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+		})
+	}
+
+	e, err := newTimestampExtractor(TimestampExtractConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, e)
+	_, found := e.extract("2022-12-31T12:00:00Z", receivedAt)
+	assert.False(t, found)
+}
+
+func Test_timestampExtractor_extractTime(t *testing.T) {
+	e, err := newTimestampExtractor(TimestampExtractConfig{Pattern: `^\S+`, Layout: time.RFC3339})
+	require.NoError(t, err)
+	receivedAt := time.Unix(1.7e9, 0)
+
+	event := &splunk.Event{Event: "2022-12-31T12:00:00Z done"}
+	e.extractTime(event, receivedAt)
+	assert.Equal(t, float64(time.Date(2022, time.December, 31, 12, 0, 0, 0, time.UTC).Unix()), event.Time)
+
+	// Events with time and structured events are left untouched.
+	event = &splunk.Event{Event: "2022-12-31T12:00:00Z done", Time: 1.5e9}
+	e.extractTime(event, receivedAt)
+	assert.Equal(t, 1.5e9, event.Time)
+	event = &splunk.Event{Event: map[string]interface{}{"time": "2022-12-31T12:00:00Z"}}
+	e.extractTime(event, receivedAt)
+	assert.Zero(t, event.Time)
+}
@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+const authorizationHeader = "Authorization"
+
+var (
+	errMissingToken         = errors.New("missing HEC token")
+	errInvalidAuthorization = errors.New("invalid authorization header")
+	errInvalidToken         = errors.New("invalid HEC token")
+	errTokenDisabled        = errors.New("disabled HEC token")
+	errIncorrectIndex       = errors.New("index not allowed for HEC token")
+)
+
+// tokenSet holds the HEC tokens accepted by the receiver.
+type tokenSet map[string]*TokenConfig
+
+func newTokenSet(config *Config) tokenSet {
+	if len(config.Tokens) == 0 {
+		return nil
+	}
+	tokens := make(tokenSet, len(config.Tokens))
+	for i := range config.Tokens {
+		tokens[string(config.Tokens[i].Token)] = &config.Tokens[i]
+	}
+	return tokens
+}
+
+// tokenSource holds the HEC tokens accepted by the receiver, the configured
+// ones along with the ones of the tokens file, which is reloaded when it
+// changes so that tokens can be rotated without restarting the receiver.
+type tokenSource struct {
+	configured []TokenConfig
+	path       string
+	logger     *zap.Logger
+
+	mu      sync.RWMutex
+	modTime time.Time
+	tokens  tokenSet
+}
+
+// newTokenSource returns the tokens configured by config, or nil when
+// neither tokens nor a tokens file are configured, in which case requests
+// are not authorized.
+func newTokenSource(config *Config, logger *zap.Logger) *tokenSource {
+	if len(config.Tokens) == 0 && config.TokensFile.Path == "" {
+		return nil
+	}
+	return &tokenSource{
+		configured: config.Tokens,
+		path:       config.TokensFile.Path,
+		logger:     logger,
+		tokens:     newTokenSet(config),
+	}
+}
+
+// load reads the tokens file if it changed since it was last read. The
+// configured tokens take precedence over the ones of the file.
+func (s *tokenSource) load() error {
+	if s.path == "" {
+		return nil
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	fileTokens, err := readTokensFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read tokens file %q: %w", s.path, err)
+	}
+	tokens := make(tokenSet, len(s.configured)+len(fileTokens))
+	for i := range fileTokens {
+		tokens[string(fileTokens[i].Token)] = &fileTokens[i]
+	}
+	for i := range s.configured {
+		tokens[string(s.configured[i].Token)] = &s.configured[i]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modTime = info.ModTime()
+	s.tokens = tokens
+	return nil
+}
+
+// reload reloads the tokens file every interval until ctx is done. The
+// previous tokens are kept when the file cannot be read.
+func (s *tokenSource) reload(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.load(); err != nil {
+				s.logger.Warn("Failed to reload the tokens file, keeping the previous tokens", zap.Error(err))
+			}
+		}
+	}
+}
+
+// authorize returns the token req is authorized with, among the current
+// tokens.
+func (s *tokenSource) authorize(req *http.Request) (*TokenConfig, int, []byte, error) {
+	s.mu.RLock()
+	tokens := s.tokens
+	s.mu.RUnlock()
+	return tokens.authorize(req)
+}
+
+// readTokensFile reads the YAML or JSON list of tokens held by the file at
+// path, which have the keys of the tokens setting.
+func readTokensFile(path string) ([]TokenConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw []interface{}
+	if err = yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+	var parsed struct {
+		Tokens []TokenConfig `mapstructure:"tokens"`
+	}
+	if err = confmap.NewFromStringMap(map[string]interface{}{"tokens": raw}).Unmarshal(&parsed); err != nil {
+		return nil, err
+	}
+	if err = validateTokens(parsed.Tokens); err != nil {
+		return nil, err
+	}
+	return parsed.Tokens, nil
+}
+
+// authorize returns the token req is authorized with. When req is not
+// authorized, it returns the status and HEC response to reject it with.
+func (t tokenSet) authorize(req *http.Request) (*TokenConfig, int, []byte, error) {
+	authorization := req.Header.Get(authorizationHeader)
+	if authorization == "" {
+		return nil, http.StatusUnauthorized, tokenRequiredRespBody, errMissingToken
+	}
+	if !strings.HasPrefix(authorization, splunk.HECTokenHeader+" ") {
+		return nil, http.StatusUnauthorized, invalidAuthorizationRespBody, errInvalidAuthorization
+	}
+	token, ok := t[authorization[len(splunk.HECTokenHeader)+1:]]
+	if !ok {
+		return nil, http.StatusForbidden, invalidTokenRespBody, errInvalidToken
+	}
+	if token.Disabled {
+		return nil, http.StatusForbidden, tokenDisabledRespBody, errTokenDisabled
+	}
+	return token, 0, nil, nil
+}
+
+// applyDefaults sets the default index and sourcetype of token on event when
+// it does not specify them.
+func (t *TokenConfig) applyDefaults(event *splunk.Event) {
+	if t == nil {
+		return
+	}
+	if event.Index == "" {
+		event.Index = t.Index
+	}
+	if event.SourceType == "" {
+		event.SourceType = t.SourceType
+	}
+}
+
+// allowsIndex returns whether data sent with token can be written to index.
+// The empty index stands for the default index and is always allowed.
+func (t *TokenConfig) allowsIndex(index string) bool {
+	if t == nil || len(t.Indexes) == 0 || index == "" {
+		return true
+	}
+	for _, allowed := range t.Indexes {
+		if index == allowed {
+			return true
+		}
+	}
+	return false
+}
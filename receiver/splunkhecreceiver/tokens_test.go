@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func Test_splunkhecReceiver_tokens(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Ack.Enabled = true
+	config.Tokens = []TokenConfig{
+		{Token: "defaults", Index: "main", SourceType: "app"},
+		{Token: "plain"},
+		{Token: "restricted", Index: "main", Indexes: []string{"main", "audit"}},
+		{Token: "disabled", Disabled: true},
+	}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		body          string
+		wantStatus    int
+		wantBody      []byte
+		wantIndex     interface{}
+		wantSrcType   interface{}
+	}{
+		{
+			name:       "missing_token",
+			path:       "/services/collector/event",
+			body:       `{"event":"foo"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   tokenRequiredRespBody,
+		},
+		{
+			name:          "invalid_authorization",
+			path:          "/services/collector/event",
+			authorization: "Bearer plain",
+			body:          `{"event":"foo"}`,
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      invalidAuthorizationRespBody,
+		},
+		{
+			name:          "invalid_token",
+			path:          "/services/collector/raw",
+			authorization: "Splunk unknown",
+			body:          "foo",
+			wantStatus:    http.StatusForbidden,
+			wantBody:      invalidTokenRespBody,
+		},
+		{
+			name:          "ack_invalid_token",
+			path:          "/services/collector/ack",
+			authorization: "Splunk unknown",
+			body:          `{"acks":[0]}`,
+			wantStatus:    http.StatusForbidden,
+			wantBody:      invalidTokenRespBody,
+		},
+		{
+			name:          "token_defaults",
+			path:          "/services/collector/event",
+			authorization: "Splunk defaults",
+			body:          `{"event":"foo"}`,
+			wantStatus:    http.StatusOK,
+			wantIndex:     "main",
+			wantSrcType:   "app",
+		},
+		{
+			name:          "event_metadata_wins",
+			path:          "/services/collector/event",
+			authorization: "Splunk defaults",
+			body:          `{"event":"foo","index":"other","sourcetype":"mine"}`,
+			wantStatus:    http.StatusOK,
+			wantIndex:     "other",
+			wantSrcType:   "mine",
+		},
+		{
+			name:          "raw_token_defaults",
+			path:          "/services/collector/raw?index=other",
+			authorization: "Splunk defaults",
+			body:          "foo",
+			wantStatus:    http.StatusOK,
+			wantIndex:     "other",
+			wantSrcType:   "app",
+		},
+		{
+			name:          "disabled_token",
+			path:          "/services/collector/event",
+			authorization: "Splunk disabled",
+			body:          `{"event":"foo"}`,
+			wantStatus:    http.StatusForbidden,
+			wantBody:      tokenDisabledRespBody,
+		},
+		{
+			name:          "allowed_index",
+			path:          "/services/collector/event",
+			authorization: "Splunk restricted",
+			body:          `{"event":"foo","index":"audit"}`,
+			wantStatus:    http.StatusOK,
+			wantIndex:     "audit",
+		},
+		{
+			name:          "incorrect_index",
+			path:          "/services/collector/event",
+			authorization: "Splunk restricted",
+			body:          `{"event":"foo","index":"main"}{"event":"bar","index":"other"}`,
+			wantStatus:    http.StatusBadRequest,
+			wantBody:      []byte(`{"text":"Incorrect index","code":7,"invalid-event-number":1}`),
+		},
+		{
+			name:          "raw_incorrect_index",
+			path:          "/services/collector/raw?index=other",
+			authorization: "Splunk restricted",
+			body:          "foo",
+			wantStatus:    http.StatusBadRequest,
+			wantBody:      incorrectIndexRespBody,
+		},
+		{
+			name:          "token_without_defaults",
+			path:          "/services/collector/event",
+			authorization: "Splunk plain",
+			body:          `{"event":"foo"}`,
+			wantStatus:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink.Reset()
+			req := httptest.NewRequest("POST", "http://localhost:0"+tt.path, strings.NewReader(tt.body))
+			req.Header.Set(channelHeader, "ch")
+			if tt.authorization != "" {
+				req.Header.Set(authorizationHeader, tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.server.Handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != nil {
+				assert.Equal(t, string(tt.wantBody), w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				assert.Equal(t, 0, sink.LogRecordCount())
+				return
+			}
+			require.Equal(t, 1, sink.LogRecordCount())
+			attrs := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().AsRaw()
+			assert.Equal(t, tt.wantIndex, attrs[splunk.DefaultIndexLabel])
+			assert.Equal(t, tt.wantSrcType, attrs[splunk.DefaultSourceTypeLabel])
+		})
+	}
+}
+
+func Test_tokenSource_load(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "yaml",
+			content: "- token: rotated\n  index: main\n- token: configured\n  disabled: true\n",
+			want:    []string{"configured", "rotated"},
+		},
+		{
+			name:    "json",
+			content: `[{"token":"rotated","index":"main"}]`,
+			want:    []string{"configured", "rotated"},
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    []string{"configured"},
+		},
+		{
+			name:    "duplicate_token",
+			content: "- token: rotated\n- token: rotated\n",
+			wantErr: errDuplicateToken.Error(),
+		},
+		{
+			name:    "not_a_list",
+			content: `{"token":"rotated"}`,
+			wantErr: "failed to read tokens file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+			config := createDefaultConfig().(*Config)
+			config.Tokens = []TokenConfig{{Token: "configured", Index: "audit"}}
+			config.TokensFile.Path = path
+			source := newTokenSource(config, zap.NewNop())
+
+			err := source.load()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var tokens []string
+			for token := range source.tokens {
+				tokens = append(tokens, token)
+			}
+			assert.ElementsMatch(t, tt.want, tokens)
+			// Configured tokens take precedence over the ones of the file.
+			assert.Equal(t, "audit", source.tokens["configured"].Index)
+		})
+	}
+}
+
+func Test_splunkhecReceiver_tokensFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- token: old\n"), 0600))
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0"
+	config.TokensFile = TokensFileConfig{Path: path, ReloadInterval: 10 * time.Millisecond}
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	status := func(token string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "http://localhost/services/collector", strings.NewReader(`{"event":"foo"}`))
+		req.Header.Set("Authorization", splunk.HECTokenHeader+" "+token)
+		r.handleReq(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, status("old"))
+	assert.Equal(t, http.StatusForbidden, status("new"))
+
+	require.NoError(t, os.WriteFile(path, []byte("- token: new\n"), 0600))
+	// Ensure the modification time changes on file systems with coarse timestamps.
+	require.NoError(t, os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+	assert.Eventually(t, func() bool { return status("new") == http.StatusOK }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusForbidden, status("old"))
+
+	// The previous tokens are kept when the file becomes invalid.
+	require.NoError(t, os.WriteFile(path, []byte("- token: new\n- token: new\n"), 0600))
+	require.NoError(t, os.Chtimes(path, time.Now().Add(2*time.Minute), time.Now().Add(2*time.Minute)))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, status("new"))
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const traceparentHeader = "traceparent"
+
+// traceContext is the W3C trace context of a request.
+type traceContext struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+	flags   byte
+}
+
+// parseTraceparent returns the trace context held by the traceparent header of
+// req, if it holds a valid one.
+// See https://www.w3.org/TR/trace-context/#traceparent-header.
+func parseTraceparent(req *http.Request) (traceContext, bool) {
+	var tc traceContext
+	parts := strings.Split(req.Header.Get(traceparentHeader), "-")
+	var version, flags [1]byte
+	if len(parts) < 4 || !decodeHex(parts[0], version[:]) || version[0] == 0xff || (version[0] == 0 && len(parts) != 4) {
+		return tc, false
+	}
+	if !decodeHex(parts[1], tc.traceID[:]) || !decodeHex(parts[2], tc.spanID[:]) || !decodeHex(parts[3], flags[:]) {
+		return tc, false
+	}
+	if tc.traceID.IsEmpty() || tc.spanID.IsEmpty() {
+		return tc, false
+	}
+	tc.flags = flags[0]
+	return tc, true
+}
+
+// decodeHex decodes the lower case hexadecimal string s to dst, which it must exactly fill.
+func decodeHex(s string, dst []byte) bool {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// setTraceContext sets the trace context of req on the log records of ld
+// that do not already belong to a trace, when trace context propagation is
+// enabled and req carries one.
+func (r *splunkReceiver) setTraceContext(req *http.Request, ld plog.Logs) {
+	if !r.config.PropagateTraceContext {
+		return
+	}
+	tc, ok := parseTraceparent(req)
+	if !ok {
+		return
+	}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			lrs := sls.At(j).LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				lr := lrs.At(k)
+				if !lr.TraceID().IsEmpty() {
+					continue
+				}
+				lr.SetTraceID(tc.traceID)
+				lr.SetSpanID(tc.spanID)
+				lr.SetFlags(plog.LogRecordFlags(tc.flags))
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func Test_parseTraceparent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		want        traceContext
+		wantOK      bool
+	}{
+		{
+			name:        "sampled",
+			traceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			want: traceContext{
+				traceID: pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+				spanID:  pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+				flags:   1,
+			},
+			wantOK: true,
+		},
+		{
+			name:        "future_version",
+			traceparent: "cc-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00-extra",
+			want: traceContext{
+				traceID: pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+				spanID:  pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+			},
+			wantOK: true,
+		},
+		{name: "missing"},
+		{name: "invalid_version", traceparent: "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		{name: "extra_part", traceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra"},
+		{name: "upper_case", traceparent: "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01"},
+		{name: "short_trace_id", traceparent: "00-0af7651916cd43dd8448eb211c8031-b7ad6b7169203331-01"},
+		{name: "empty_trace_id", traceparent: "00-00000000000000000000000000000000-b7ad6b7169203331-01"},
+		{name: "empty_span_id", traceparent: "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01"},
+		{name: "invalid_flags", traceparent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-zz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "http://localhost/services/collector", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			got, ok := parseTraceparent(req)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_splunkhecReceiver_propagateTraceContext(t *testing.T) {
+	traceparent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	for _, enabled := range []bool{true, false} {
+		for _, path := range []string{"/services/collector", "/services/collector/raw"} {
+			config := createDefaultConfig().(*Config)
+			config.PropagateTraceContext = enabled
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+			require.NoError(t, err)
+			r := rcv.(*splunkReceiver)
+
+			body := "foo\nbar"
+			handle := r.handleRawReq
+			if path == "/services/collector" {
+				body = `{"event":"foo"}{"event":"bar"}`
+				handle = r.handleReq
+			}
+			req := httptest.NewRequest("POST", "http://localhost"+path, strings.NewReader(body))
+			req.Header.Set("traceparent", traceparent)
+			w := httptest.NewRecorder()
+			handle(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			require.Equal(t, 2, sink.LogRecordCount())
+			lrs := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			for i := 0; i < lrs.Len(); i++ {
+				lr := lrs.At(i)
+				if enabled {
+					assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", lr.TraceID().String())
+					assert.Equal(t, "b7ad6b7169203331", lr.SpanID().String())
+					assert.Equal(t, plog.LogRecordFlags(1), lr.Flags())
+				} else {
+					assert.True(t, lr.TraceID().IsEmpty())
+					assert.True(t, lr.SpanID().IsEmpty())
+				}
+			}
+		}
+	}
+}
+
+func Test_splunkhecReceiver_setTraceContextKeepsRecordTrace(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.PropagateTraceContext = true
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, consumertest.NewNop())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().SetTraceID(pcommon.TraceID{1})
+	lrs.AppendEmpty()
+	req := httptest.NewRequest("POST", "http://localhost/services/collector", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	rcv.(*splunkReceiver).setTraceContext(req, ld)
+
+	assert.Equal(t, pcommon.TraceID{1}, lrs.At(0).TraceID())
+	assert.True(t, lrs.At(0).SpanID().IsEmpty())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", lrs.At(1).TraceID().String())
+}
@@ -0,0 +1,34 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+)
+
+// listenUnixSocket listens on the Unix domain socket at path. A socket left
+// behind by a previous process that did not shut down cleanly is removed, but
+// any other file at path is kept and makes listening fail.
+func listenUnixSocket(path string) (net.Listener, error) {
+	info, err := os.Lstat(path)
+	switch {
+	case err == nil && info.Mode()&fs.ModeSocket != 0:
+		if err = os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale unix socket %s: %w", path, err)
+		}
+	case err == nil:
+		return nil, fmt.Errorf("failed to bind to unix socket %s: file exists and is not a socket", path)
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to bind to unix socket %s: %w", path, err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to unix socket %s: %w", path, err)
+	}
+	return ln, nil
+}
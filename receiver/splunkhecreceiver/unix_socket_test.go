@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunkhecreceiver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+// socketPath returns the path of a socket in a new temporary directory. The
+// directory is kept short as socket paths are limited to about 100 bytes.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "hec")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "hec.sock")
+}
+
+func Test_splunkhecReceiver_unixSocket(t *testing.T) {
+	path := socketPath(t)
+	// A socket left behind by a previous run is replaced.
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.UnixSocket.Path = path
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(receivertest.NewNopCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Post("http://unix/services/collector/raw", "text/plain", strings.NewReader("foo"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 1, sink.LogRecordCount())
+	assert.Equal(t, "foo", sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+
+	resp, err = http.Post("http://"+config.Endpoint+"/services/collector/raw", "text/plain", strings.NewReader("bar"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, sink.LogRecordCount())
+
+	require.NoError(t, rcv.Shutdown(context.Background()))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestListenUnixSocketNotASocket(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+
+	_, err := listenUnixSocket(path)
+	assert.ErrorContains(t, err, "file exists and is not a socket")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
+
+import (
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+var (
+	errEmptyEndpoint          = errors.New("endpoint must be specified")
+	errInvalidMaxMessageSize  = errors.New("max_message_size must be positive")
+	errEmptyHecToOtelAttrsKey = errors.New("hec_metadata_to_otel_attrs keys must not be empty")
+)
+
+// Config defines configuration for the Splunk S2S receiver.
+type Config struct {
+	// Endpoint is the address to listen on for Splunk forwarders, of the form `<ip addr>:<port>`.
+	Endpoint string `mapstructure:"endpoint"`
+	// MaxMessageSize is the maximum size in bytes of a message sent by a forwarder. Connections sending larger
+	// messages are closed.
+	MaxMessageSize int `mapstructure:"max_message_size"`
+	// HecToOtelAttrs creates a mapping from the host, source, sourcetype and index of events to resource attributes,
+	// as the Splunk HEC receiver does.
+	HecToOtelAttrs splunk.HecToOtelAttrs `mapstructure:"hec_metadata_to_otel_attrs"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return errEmptyEndpoint
+	}
+	if c.MaxMessageSize <= 0 {
+		return errInvalidMaxMessageSize
+	}
+	if c.HecToOtelAttrs.Host == "" || c.HecToOtelAttrs.Source == "" || c.HecToOtelAttrs.SourceType == "" || c.HecToOtelAttrs.Index == "" {
+		return errEmptyHecToOtelAttrsKey
+	}
+	return nil
+}
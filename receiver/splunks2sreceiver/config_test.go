@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: createDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "allsettings"),
+			expected: &Config{
+				Endpoint:       "localhost:9997",
+				MaxMessageSize: 1048576,
+				HecToOtelAttrs: splunk.HecToOtelAttrs{
+					Source:     "file.name",
+					SourceType: "foobar",
+					Index:      "myindex",
+					Host:       "myhostfield",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, component.UnmarshalConfig(sub, cfg))
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    error
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name:   "empty_endpoint",
+			modify: func(cfg *Config) { cfg.Endpoint = "" },
+			err:    errEmptyEndpoint,
+		},
+		{
+			name:   "zero_max_message_size",
+			modify: func(cfg *Config) { cfg.MaxMessageSize = 0 },
+			err:    errInvalidMaxMessageSize,
+		},
+		{
+			name:   "empty_hec_metadata_to_otel_attrs",
+			modify: func(cfg *Config) { cfg.HecToOtelAttrs.Index = "" },
+			err:    errEmptyHecToOtelAttrsKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			assert.Equal(t, tt.err, cfg.Validate())
+		})
+	}
+}
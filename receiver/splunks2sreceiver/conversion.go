@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// logsBuilder converts the events received from a forwarder to logs, grouping
+// the events sharing their host, source, sourcetype and index in a resource.
+type logsBuilder struct {
+	attrs     splunk.HecToOtelAttrs
+	ld        plog.Logs
+	resources map[[4]string]plog.LogRecordSlice
+	count     int
+}
+
+func newLogsBuilder(attrs splunk.HecToOtelAttrs) *logsBuilder {
+	return &logsBuilder{
+		attrs:     attrs,
+		ld:        plog.NewLogs(),
+		resources: map[[4]string]plog.LogRecordSlice{},
+	}
+}
+
+// append converts the event held by the key/value pairs of a message,
+// returning false if the message holds no event, such as control messages.
+func (b *logsBuilder) append(pairs map[string]string) bool {
+	raw, ok := pairs[rawKey]
+	if !ok {
+		return false
+	}
+	key := [4]string{
+		strings.TrimPrefix(pairs[hostKey], hostPrefix),
+		strings.TrimPrefix(pairs[sourceKey], sourcePrefix),
+		strings.TrimPrefix(pairs[sourceTypeKey], sourceTypePrefix),
+		pairs[indexKey],
+	}
+	records, found := b.resources[key]
+	if !found {
+		rl := b.ld.ResourceLogs().AppendEmpty()
+		attrs := rl.Resource().Attributes()
+		for i, name := range []string{b.attrs.Host, b.attrs.Source, b.attrs.SourceType, b.attrs.Index} {
+			if key[i] != "" {
+				attrs.PutStr(name, key[i])
+			}
+		}
+		records = rl.ScopeLogs().AppendEmpty().LogRecords()
+		b.resources[key] = records
+	}
+	record := records.AppendEmpty()
+	record.Body().SetStr(raw)
+	record.SetTimestamp(parseTime(pairs[timeKey], pairs[subsecondKey]))
+	putIndexedFields(record.Attributes(), pairs[metaKey])
+	b.count++
+	return true
+}
+
+// logs returns the converted logs and resets the builder.
+func (b *logsBuilder) logs() plog.Logs {
+	ld := b.ld
+	b.ld = plog.NewLogs()
+	b.resources = map[[4]string]plog.LogRecordSlice{}
+	b.count = 0
+	return ld
+}
+
+// parseTime returns the timestamp of an event from its epoch seconds and their
+// optional decimal fraction, such as ".250".
+func parseTime(seconds string, subsecond string) pcommon.Timestamp {
+	secs, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return 0
+	}
+	ts := time.Unix(secs, 0)
+	if frac, err := strconv.ParseFloat("0"+subsecond, 64); err == nil && subsecond != "" {
+		ts = ts.Add(time.Duration(frac * float64(time.Second)))
+	}
+	return pcommon.NewTimestampFromTime(ts)
+}
+
+// putIndexedFields sets the indexed fields of an event, held by its _meta key
+// as space separated field::value pairs, as attributes.
+func putIndexedFields(attrs pcommon.Map, meta string) {
+	for _, field := range strings.Fields(meta) {
+		name, value, ok := strings.Cut(field, "::")
+		if !ok || name == "" || strings.HasPrefix(name, "_") {
+			continue
+		}
+		attrs.PutStr(name, value)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestLogsBuilder(t *testing.T) {
+	builder := newLogsBuilder(createDefaultConfig().(*Config).HecToOtelAttrs)
+
+	assert.False(t, builder.append(map[string]string{"__s2s_control_msg": "cd_ack"}))
+	assert.True(t, builder.append(map[string]string{
+		rawKey:        "first",
+		timeKey:       "1690000000",
+		subsecondKey:  ".250",
+		hostKey:       "host::myhost",
+		sourceKey:     "source::/var/log/app.log",
+		sourceTypeKey: "sourcetype::app",
+		indexKey:      "main",
+		metaKey:       "env::prod _ignored::1 team::ops",
+	}))
+	assert.True(t, builder.append(map[string]string{
+		rawKey:        "second",
+		timeKey:       "1690000001",
+		hostKey:       "host::myhost",
+		sourceKey:     "source::/var/log/app.log",
+		sourceTypeKey: "sourcetype::app",
+		indexKey:      "main",
+	}))
+	assert.True(t, builder.append(map[string]string{
+		rawKey:  "third",
+		hostKey: "host::otherhost",
+	}))
+	assert.Equal(t, 3, builder.count)
+
+	ld := builder.logs()
+	assert.Equal(t, 0, builder.count)
+	assert.Equal(t, 0, builder.logs().LogRecordCount())
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"host.name":             "myhost",
+		"com.splunk.source":     "/var/log/app.log",
+		"com.splunk.sourcetype": "app",
+		"com.splunk.index":      "main",
+	}, rl.Resource().Attributes().AsRaw())
+	records := rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+	assert.Equal(t, "first", records.At(0).Body().Str())
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1690000000, 250_000_000)), records.At(0).Timestamp())
+	assert.Equal(t, map[string]interface{}{"env": "prod", "team": "ops"}, records.At(0).Attributes().AsRaw())
+	assert.Equal(t, "second", records.At(1).Body().Str())
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1690000001, 0)), records.At(1).Timestamp())
+
+	rl = ld.ResourceLogs().At(1)
+	assert.Equal(t, map[string]interface{}{"host.name": "otherhost"}, rl.Resource().Attributes().AsRaw())
+	record := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "third", record.Body().Str())
+	assert.Equal(t, pcommon.Timestamp(0), record.Timestamp())
+}
+
+func TestParseTime(t *testing.T) {
+	assert.Equal(t, pcommon.Timestamp(0), parseTime("", ""))
+	assert.Equal(t, pcommon.Timestamp(0), parseTime("invalid", ".5"))
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1690000000, 0)), parseTime("1690000000", "invalid"))
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1690000000, 500_000_000)), parseTime("1690000000", ".5"))
+}
@@ -0,0 +1,9 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate mdatagen metadata.yaml
+
+// Package splunks2sreceiver implements a receiver that can be used by the
+// OpenTelemetry collector to receive logs from Splunk forwarders over the
+// Splunk-to-Splunk protocol.
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver/internal/metadata"
+)
+
+const (
+	// Default endpoint to bind to, the port Splunk indexers receive data from forwarders on.
+	defaultEndpoint = ":9997"
+	// Default maximum size of a message sent by a forwarder.
+	defaultMaxMessageSize = 8 * 1024 * 1024
+)
+
+// NewFactory creates a factory for the Splunk S2S receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Endpoint:       defaultEndpoint,
+		MaxMessageSize: defaultMaxMessageSize,
+		HecToOtelAttrs: splunk.HecToOtelAttrs{
+			Source:     splunk.DefaultSourceLabel,
+			SourceType: splunk.DefaultSourceTypeLabel,
+			Index:      splunk.DefaultIndexLabel,
+			Host:       conventions.AttributeHostName,
+		},
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.CreateSettings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	return newS2SReceiver(params, cfg.(*Config), consumer)
+}
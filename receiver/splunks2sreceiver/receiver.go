@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+const (
+	// Format of the received data, as reported by the receiver telemetry.
+	s2sFormat = "s2s"
+	// maxBatchSize is the maximum number of events passed at once to the next consumer.
+	maxBatchSize = 100
+)
+
+var errNilNextConsumer = errors.New("nil nextConsumer")
+
+// s2sReceiver receives the events sent by Splunk forwarders.
+type s2sReceiver struct {
+	settings     receiver.CreateSettings
+	config       *Config
+	nextConsumer consumer.Logs
+	obsrecv      *obsreport.Receiver
+	listener     net.Listener
+	mu           sync.Mutex
+	conns        map[net.Conn]struct{}
+	closed       bool
+	wg           sync.WaitGroup
+}
+
+var _ receiver.Logs = (*s2sReceiver)(nil)
+
+func newS2SReceiver(settings receiver.CreateSettings, config *Config, nextConsumer consumer.Logs) (*s2sReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             settings.ID,
+		Transport:              "tcp",
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s2sReceiver{
+		settings:     settings,
+		config:       config,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+		conns:        map[net.Conn]struct{}{},
+	}, nil
+}
+
+// Start starts listening for forwarders.
+func (r *s2sReceiver) Start(_ context.Context, host component.Host) error {
+	ln, err := net.Listen("tcp", r.config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+	}
+	r.listener = ln
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.acceptConns(host)
+	}()
+	return nil
+}
+
+// Shutdown stops listening for forwarders and closes their connections.
+func (r *s2sReceiver) Shutdown(context.Context) error {
+	if r.listener == nil {
+		return nil
+	}
+	err := r.listener.Close()
+	r.mu.Lock()
+	r.closed = true
+	for conn := range r.conns {
+		_ = conn.Close()
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return err
+}
+
+func (r *s2sReceiver) acceptConns(host component.Host) {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				host.ReportFatalError(err)
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.closed {
+			r.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.mu.Unlock()
+		go func() {
+			defer r.wg.Done()
+			defer func() {
+				r.mu.Lock()
+				delete(r.conns, conn)
+				r.mu.Unlock()
+				_ = conn.Close()
+			}()
+			if err := r.handleConn(context.Background(), conn); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				r.settings.Logger.Warn("Closing forwarder connection", zap.Stringer("remote_addr", conn.RemoteAddr()), zap.Error(err))
+			}
+		}()
+	}
+}
+
+// handleConn reads the events sent over conn, passing them to the next
+// consumer in batches of the events read without waiting for the forwarder.
+func (r *s2sReceiver) handleConn(ctx context.Context, conn net.Conn) error {
+	reader := bufio.NewReader(conn)
+	sig, err := readSignature(reader)
+	if err != nil {
+		return err
+	}
+	r.settings.Logger.Debug("Forwarder connected", zap.String("server_name", sig.serverName), zap.Stringer("remote_addr", conn.RemoteAddr()))
+
+	builder := newLogsBuilder(r.config.HecToOtelAttrs)
+	for {
+		pairs, err := readMessage(reader, r.config.MaxMessageSize)
+		if err != nil {
+			if flushErr := r.flush(ctx, builder); flushErr != nil {
+				r.settings.Logger.Warn("Failed to pass events to the next consumer", zap.Error(flushErr))
+			}
+			return err
+		}
+		builder.append(pairs)
+		if builder.count >= maxBatchSize || reader.Buffered() == 0 {
+			if err = r.flush(ctx, builder); err != nil {
+				// The protocol has no way to refuse events but closing the
+				// connection.
+				return err
+			}
+		}
+	}
+}
+
+func (r *s2sReceiver) flush(ctx context.Context, builder *logsBuilder) error {
+	if builder.count == 0 {
+		return nil
+	}
+	count := builder.count
+	ctx = r.obsrecv.StartLogsOp(ctx)
+	err := r.nextConsumer.ConsumeLogs(ctx, builder.logs())
+	r.obsrecv.EndLogsOp(ctx, s2sFormat, count, err)
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testutil"
+)
+
+func startReceiver(t *testing.T, config *Config, sink *consumertest.LogsSink) *s2sReceiver {
+	r, err := newS2SReceiver(receivertest.NewNopCreateSettings(), config, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r
+}
+
+func TestReceiveEvents(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.LogsSink)
+	startReceiver(t, config, sink)
+
+	conn, err := net.Dial("tcp", config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write(encodeSignature(signatureV2, "forwarder1", "8089"))
+	require.NoError(t, err)
+	for _, raw := range []string{"first", "second"} {
+		_, err = conn.Write(encodeMessage(rawKey, raw, timeKey, "1690000000", hostKey, "host::myhost", indexKey, "main"))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	var bodies []string
+	for _, ld := range sink.AllLogs() {
+		rl := ld.ResourceLogs().At(0)
+		assert.Equal(t, map[string]interface{}{"host.name": "myhost", "com.splunk.index": "main"}, rl.Resource().Attributes().AsRaw())
+		records := rl.ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			bodies = append(bodies, records.At(i).Body().Str())
+		}
+	}
+	assert.Equal(t, []string{"first", "second"}, bodies)
+}
+
+func TestRejectUnsupportedSignature(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.LogsSink)
+	startReceiver(t, config, sink)
+
+	conn, err := net.Dial("tcp", config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write(encodeSignature("--splunk-cooked-mode-v3--", "forwarder1", "8089"))
+	require.NoError(t, err)
+	_, _ = conn.Write(encodeMessage(rawKey, "dropped"))
+
+	// The receiver closes the connection.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "connection was not closed")
+	assert.Equal(t, 0, sink.LogRecordCount())
+}
+
+func TestCloseConnectionOnConsumerError(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newS2SReceiver(receivertest.NewNopCreateSettings(), config, consumertest.NewErr(errors.New("consumer error")))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("tcp", config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write(encodeSignature(signatureV2, "forwarder1", "8089"))
+	require.NoError(t, err)
+	_, err = conn.Write(encodeMessage(rawKey, "refused"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "connection was not closed")
+}
+
+func TestShutdownWithOpenConnection(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newS2SReceiver(receivertest.NewNopCreateSettings(), config, new(consumertest.LogsSink))
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("tcp", config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write(encodeSignature(signatureV2, "forwarder1", "8089"))
+	require.NoError(t, err)
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	r, err := newS2SReceiver(receivertest.NewNopCreateSettings(), createDefaultConfig().(*Config), new(consumertest.LogsSink))
+	require.NoError(t, err)
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunks2sreceiver"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The cooked mode v2 of the Splunk-to-Splunk protocol starts with a signature
+// block holding the signature, the name of the forwarder and its management
+// port, each null-padded to a fixed size. The block is followed by messages,
+// each prefixed by its size, holding the key/value pairs of an event.
+const (
+	signatureV2    = "--splunk-cooked-mode-v2--"
+	signatureSize  = 128
+	serverNameSize = 256
+	mgmtPortSize   = 16
+
+	// Keys of the key/value pairs of events.
+	rawKey        = "_raw"
+	timeKey       = "_time"
+	subsecondKey  = "_subsecond"
+	metaKey       = "_meta"
+	hostKey       = "MetaData:Host"
+	sourceKey     = "MetaData:Source"
+	sourceTypeKey = "MetaData:Sourcetype"
+	indexKey      = "_MetaData:Index"
+
+	// Prefixes of the values of the metadata keys.
+	hostPrefix       = "host::"
+	sourcePrefix     = "source::"
+	sourceTypePrefix = "sourcetype::"
+)
+
+var errMessageTooLarge = errors.New("message too large")
+
+// signature is the signature block sent by a forwarder when it connects.
+type signature struct {
+	serverName string
+	mgmtPort   string
+}
+
+// readSignature reads the signature block of a connection, failing unless the
+// forwarder uses the cooked mode v2 of the protocol.
+func readSignature(r io.Reader) (signature, error) {
+	var block [signatureSize + serverNameSize + mgmtPortSize]byte
+	if _, err := io.ReadFull(r, block[:]); err != nil {
+		return signature{}, fmt.Errorf("failed to read signature: %w", err)
+	}
+	if s := cString(block[:signatureSize]); s != signatureV2 {
+		return signature{}, fmt.Errorf("unsupported signature %q", s)
+	}
+	return signature{
+		serverName: cString(block[signatureSize : signatureSize+serverNameSize]),
+		mgmtPort:   cString(block[signatureSize+serverNameSize:]),
+	}, nil
+}
+
+// readMessage reads a message, returning the key/value pairs it holds.
+// Messages larger than maxSize are rejected.
+func readMessage(r io.Reader, maxSize int) (map[string]string, error) {
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if int64(size) > int64(maxSize) {
+		return nil, fmt.Errorf("%w: %d bytes", errMessageTooLarge, size)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	return parseMessage(msg)
+}
+
+// parseMessage parses the key/value pairs of a message. The pairs are followed
+// by a trailer which is ignored.
+func parseMessage(msg []byte) (map[string]string, error) {
+	count, msg, err := readUint32(msg)
+	if err != nil {
+		return nil, err
+	}
+	// Each pair takes at least the 8 bytes of the sizes of its key and value.
+	if uint64(count)*8 > uint64(len(msg)) {
+		return nil, fmt.Errorf("invalid message: %d pairs in %d bytes", count, len(msg))
+	}
+	pairs := make(map[string]string, count)
+	for i := uint32(0); i < count; i++ {
+		var key, value string
+		if key, msg, err = readString(msg); err != nil {
+			return nil, err
+		}
+		if value, msg, err = readString(msg); err != nil {
+			return nil, err
+		}
+		pairs[key] = value
+	}
+	return pairs, nil
+}
+
+func readUint32(b []byte) (uint32, []byte, error) {
+	if len(b) < 4 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	return binary.BigEndian.Uint32(b), b[4:], nil
+}
+
+// readString reads a string prefixed by its size, including its terminating
+// null character.
+func readString(b []byte) (string, []byte, error) {
+	size, b, err := readUint32(b)
+	if err != nil {
+		return "", nil, err
+	}
+	if uint64(size) > uint64(len(b)) {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+	return cString(b[:size]), b[size:], nil
+}
+
+// cString returns the string held by b up to its first null character.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
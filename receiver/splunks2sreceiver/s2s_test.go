@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package splunks2sreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeSignature encodes the signature block sent by a forwarder.
+func encodeSignature(sig string, serverName string, mgmtPort string) []byte {
+	block := make([]byte, signatureSize+serverNameSize+mgmtPortSize)
+	copy(block, sig)
+	copy(block[signatureSize:], serverName)
+	copy(block[signatureSize+serverNameSize:], mgmtPort)
+	return block
+}
+
+// encodeMessage encodes a message holding the key/value pairs, in order.
+func encodeMessage(pairs ...string) []byte {
+	var body bytes.Buffer
+	_ = binary.Write(&body, binary.BigEndian, uint32(len(pairs)/2))
+	for _, s := range pairs {
+		_ = binary.Write(&body, binary.BigEndian, uint32(len(s)+1))
+		body.WriteString(s)
+		body.WriteByte(0)
+	}
+	// Trailer.
+	_ = binary.Write(&body, binary.BigEndian, uint32(0))
+	body.WriteString("_raw")
+
+	var msg bytes.Buffer
+	_ = binary.Write(&msg, binary.BigEndian, uint32(body.Len()))
+	msg.Write(body.Bytes())
+	return msg.Bytes()
+}
+
+func TestReadSignature(t *testing.T) {
+	sig, err := readSignature(bytes.NewReader(encodeSignature(signatureV2, "forwarder1", "8089")))
+	require.NoError(t, err)
+	assert.Equal(t, signature{serverName: "forwarder1", mgmtPort: "8089"}, sig)
+
+	_, err = readSignature(bytes.NewReader(encodeSignature("--splunk-cooked-mode-v3--", "forwarder1", "8089")))
+	assert.EqualError(t, err, `unsupported signature "--splunk-cooked-mode-v3--"`)
+
+	_, err = readSignature(bytes.NewReader([]byte(signatureV2)))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     []byte
+		maxSize int
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "event",
+			msg:     encodeMessage(rawKey, "hello", timeKey, "1690000000"),
+			maxSize: 1024,
+			want:    map[string]string{rawKey: "hello", timeKey: "1690000000"},
+		},
+		{
+			name:    "empty",
+			msg:     encodeMessage(),
+			maxSize: 1024,
+			want:    map[string]string{},
+		},
+		{
+			name:    "too_large",
+			msg:     encodeMessage(rawKey, "hello"),
+			maxSize: 8,
+			wantErr: "message too large: 31 bytes",
+		},
+		{
+			name:    "invalid_count",
+			msg:     []byte{0, 0, 0, 4, 0, 0, 0, 9},
+			maxSize: 1024,
+			wantErr: "invalid message: 9 pairs in 0 bytes",
+		},
+		{
+			name:    "truncated_string",
+			msg:     []byte{0, 0, 0, 12, 0, 0, 0, 1, 0, 0, 0, 9, 'a', 0, 0, 0, 0, 0},
+			maxSize: 1024,
+			wantErr: io.ErrUnexpectedEOF.Error(),
+		},
+		{
+			name:    "truncated_message",
+			msg:     []byte{0, 0, 0, 16, 0, 0, 0, 1},
+			maxSize: 1024,
+			wantErr: "failed to read message: unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := readMessage(bytes.NewReader(tt.msg), tt.maxSize)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, pairs)
+		})
+	}
+}
@@ -338,3 +338,5 @@ retract (
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest => ../pkg/pdatatest
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil => ../pkg/pdatautil
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage => ../extension/storage